@@ -0,0 +1,102 @@
+package cache_command
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-cli-tool/internal/cache"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/utils"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var asJSON bool
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the cache of the analysis results (.gocli/cache)",
+	Long: `The analyze command caches the results of every file in .gocli/cache next
+to the project configuration file (or at the top of the git repository),
+keyed by the content of the file, the version of the tool and the
+configuration. The files that did not change are not analyzed again.`,
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Print the number of cached files and the size of the cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stateDir, err := stateDir()
+		if err != nil {
+			return err
+		}
+
+		stats, err := cache.ReadStats(stateDir)
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(stats)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%sCache: %s%s\n", utils.BLUE, stats.Dir, utils.RESET_COLOR)
+		fmt.Fprintf(cmd.OutOrStdout(), "Entries: %d\n", stats.Entries)
+		fmt.Fprintf(cmd.OutOrStdout(), "Size: %s\n", formatSize(stats.Bytes))
+		return nil
+	},
+}
+
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all the cached results",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stateDir, err := stateDir()
+		if err != nil {
+			return err
+		}
+
+		stats, err := cache.Clear(stateDir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%sRemoved %d entries (%s) from %s%s\n", utils.GREEN, stats.Entries, formatSize(stats.Bytes), stats.Dir, utils.RESET_COLOR)
+		return nil
+	},
+}
+
+// stateDir returns the state directory of the project of the working
+// directory, where analyze keeps its cache.
+func stateDir() (string, error) {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return config.StateDir(workingDirectory), nil
+}
+
+// formatSize formats a number of bytes with the largest fitting unit.
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	size, suffix := float64(bytes)/unit, 0
+	for size >= unit && suffix < 3 {
+		size /= unit
+		suffix++
+	}
+	return fmt.Sprintf("%.1f %ciB", size, "KMGT"[suffix])
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&asJSON, "json", false, "Print the statistics as JSON")
+	CacheCmd.AddCommand(statsCmd)
+	CacheCmd.AddCommand(clearCmd)
+}
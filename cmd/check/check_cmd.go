@@ -0,0 +1,148 @@
+package check
+
+import (
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/utils"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+var qualityGateAnalyzer analyzer.QualityGateAnalyzer
+
+// limits holds the threshold flags, which override the configuration file.
+var limits config.Thresholds
+
+var CheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Fail when the code exceeds the quality thresholds",
+	Long: `Evaluate the quality thresholds of the project configuration on a file or
+directory and print every violation with its file and line.
+
+The command exits with a non-zero status when a threshold is exceeded, so
+it can gate merges in a CI pipeline. Flags override the thresholds of the
+.gocli.yaml or .gocli.json file; a negative limit disables a rule.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := policies.ValidateUserInput(); err != nil {
+			return err
+		}
+
+		thresholds := effectiveThresholds(cmd)
+
+		var violations []analyzer.Violation
+		var err error
+		if utils.FilePath != "" {
+			violations, err = qualityGateAnalyzer.CheckFile(utils.FilePath, thresholds)
+		} else {
+			if !policies.ValidateDirectoryPath(utils.DirectoryPath) {
+				return fmt.Errorf("please provide a valid directory path")
+			}
+			violations, err = qualityGateAnalyzer.CheckDirectory(utils.DirectoryPath, thresholds)
+		}
+		skipped, fatal := analyzer.Skipped(err)
+		if fatal != nil {
+			return fatal
+		}
+
+		checkReport := newReport(violations)
+		checkReport.Skipped = skipped
+		if printErr := report.Print(cmd.OutOrStdout(), checkReport, report.JSON); printErr != nil {
+			return printErr
+		}
+
+		if len(violations) > 0 {
+			return fmt.Errorf("%d quality gate violation(s)", len(violations))
+		}
+		// the files that could not be read were not checked
+		return err
+	},
+}
+
+// newReport lists the violations; the text format prints them one per line
+// in red, or a confirmation when the code passes.
+func newReport(violations []analyzer.Violation) report.Report {
+	rows := make([][]interface{}, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []interface{}{violation.File, violation.Line, violation.Rule, violation.Message})
+	}
+
+	return report.Report{
+		Title: string(utils.QUALITY_GATES),
+		Columns: []report.Column{
+			{Key: "file", Title: "File"},
+			{Key: "line", Title: "Line"},
+			{Key: "rule", Title: "Rule"},
+			{Key: "message", Title: "Message"},
+		},
+		Rows:    rows,
+		Summary: []report.Field{{Key: "violations", Title: "Violations", Value: len(violations)}},
+		Data: map[string]interface{}{
+			"passed":     len(violations) == 0,
+			"violations": append([]analyzer.Violation{}, violations...),
+		},
+		Text: func(w io.Writer) {
+			for _, violation := range violations {
+				fmt.Fprintf(w, "%s%s%s\n", utils.RED, violation, utils.RESET_COLOR)
+			}
+			if len(violations) == 0 {
+				fmt.Fprintf(w, "%sAll quality gates passed.%s\n", utils.GREEN, utils.RESET_COLOR)
+			}
+		},
+	}
+}
+
+// effectiveThresholds returns the configured thresholds overridden by the
+// flags given on the command line.
+func effectiveThresholds(cmd *cobra.Command) config.Thresholds {
+	thresholds := config.Active.Thresholds
+	flags := cmd.Flags()
+
+	if flags.Changed("max-function-lines") {
+		thresholds.MaxFunctionLines = limits.MaxFunctionLines
+	}
+	if flags.Changed("max-complexity") {
+		thresholds.MaxComplexity = limits.MaxComplexity
+	}
+	if flags.Changed("max-cognitive-complexity") {
+		thresholds.MaxCognitiveComplexity = limits.MaxCognitiveComplexity
+	}
+	if flags.Changed("max-nesting-depth") {
+		thresholds.MaxNestingDepth = limits.MaxNestingDepth
+	}
+	if flags.Changed("min-comment-percentage") {
+		thresholds.MinCommentPercentage = limits.MinCommentPercentage
+	}
+	if flags.Changed("min-maintainability") {
+		thresholds.MinMaintainability = limits.MinMaintainability
+	}
+	if flags.Changed("max-dependencies") {
+		thresholds.MaxDependencies = limits.MaxDependencies
+	}
+	if flags.Changed("allow-mixed-indentation") {
+		thresholds.AllowMixedIndentation = limits.AllowMixedIndentation
+	}
+
+	return thresholds
+}
+
+func init() {
+	qualityGateAnalyzer = &analyzer.QualityGateAnalyzerImpl{}
+	CheckCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file")
+	CheckCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files")
+	CheckCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (JSON by default)")
+	CheckCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+	CheckCmd.Flags().IntVar(&limits.MaxFunctionLines, "max-function-lines", 0, "Maximum number of lines of a function")
+	CheckCmd.Flags().IntVar(&limits.MaxComplexity, "max-complexity", 0, "Maximum cyclomatic complexity of a function")
+	CheckCmd.Flags().IntVar(&limits.MaxCognitiveComplexity, "max-cognitive-complexity", 0, "Maximum cognitive complexity of a function")
+	CheckCmd.Flags().IntVar(&limits.MaxNestingDepth, "max-nesting-depth", 0, "Maximum nesting depth of a function")
+	CheckCmd.Flags().Float64Var(&limits.MinCommentPercentage, "min-comment-percentage", 0, "Minimum percentage of comment lines of a file")
+	CheckCmd.Flags().Float64Var(&limits.MinMaintainability, "min-maintainability", 0, "Minimum maintainability index (0-100) of a file")
+	CheckCmd.Flags().IntVar(&limits.MaxDependencies, "max-dependencies", 0, "Maximum number of external dependencies of a file")
+	CheckCmd.Flags().BoolVar(&limits.AllowMixedIndentation, "allow-mixed-indentation", false, "Accept files indented with both tabs and spaces")
+}
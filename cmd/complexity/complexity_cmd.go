@@ -0,0 +1,146 @@
+package complexity
+
+import (
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/utils"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+var complexityAnalyzer analyzer.ComplexityAnalyzer
+
+var top int
+
+var columns = []report.Column{
+	{Key: "file", Title: "File"},
+	{Key: "average", Title: "Average complexity"},
+	{Key: "max", Title: "Max complexity"},
+	{Key: "functions", Title: "Functions"},
+}
+
+var ComplexityCmd = &cobra.Command{
+	Use:           "complexity",
+	Short:         "Calculate the cyclomatic complexity of the functions in a JavaScript file",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := policies.ValidateUserInput(); err != nil {
+			return err
+		}
+
+		if utils.FilePath != "" {
+			result, err := complexityAnalyzer.CalculateComplexity(utils.FilePath)
+			if err != nil {
+				return err
+			}
+			fileReport := newReport([][]interface{}{row(utils.FilePath, result)}, result)
+			fileReport.Text = func(w io.Writer) {
+				printSummary(result, "", w)
+				printWorstFunctions(result, w)
+			}
+			return report.Print(cmd.OutOrStdout(), fileReport, report.HTML)
+		}
+
+		if utils.DirectoryPath != "" && !policies.ValidateDirectoryPath(utils.DirectoryPath) {
+			return fmt.Errorf("please provide a valid directory path")
+		}
+
+		results, total, err := complexityAnalyzer.CalculateComplexityByDirectory(utils.DirectoryPath)
+		skipped, fatal := analyzer.Skipped(err)
+		if fatal != nil {
+			return fatal
+		}
+
+		if len(results) == 0 && len(skipped) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%sNo JavaScript files found in the provided directory.%s\n", utils.RED, utils.RESET_COLOR)
+			return nil
+		}
+
+		fileNames := report.SortedKeys(results)
+		rows := make([][]interface{}, 0, len(fileNames))
+		for _, fileName := range fileNames {
+			rows = append(rows, row(fileName, results[fileName]))
+		}
+
+		directoryReport := newReport(rows, total)
+		directoryReport.Text = func(w io.Writer) {
+			printDirectoryResults(fileNames, results, w)
+			printSummary(total, " in directory", w)
+			printWorstFunctions(total, w)
+		}
+		directoryReport.Skipped = skipped
+		if printErr := report.Print(cmd.OutOrStdout(), directoryReport, report.HTML); printErr != nil {
+			return printErr
+		}
+		return err
+	},
+}
+
+func row(fileName string, result analyzer.ComplexityResult) []interface{} {
+	return []interface{}{fileName, result.Average, result.Max, len(result.Functions)}
+}
+
+// newReport builds the report of the complexities; the json and yaml
+// formats also list the most complex functions.
+func newReport(rows [][]interface{}, total analyzer.ComplexityResult) report.Report {
+	result := report.Report{
+		Title:   string(utils.COMPLEXITY),
+		Columns: columns,
+		Rows:    rows,
+		Summary: []report.Field{
+			{Key: "average", Title: "Average complexity", Value: total.Average},
+			{Key: "max", Title: "Max complexity", Value: total.Max},
+		},
+	}
+
+	data := result.Table()
+	data["worst_functions"] = total.Worst(top)
+	result.Data = data
+
+	return result
+}
+
+func printSummary(result analyzer.ComplexityResult, scope string, w io.Writer) {
+	fmt.Fprintf(w, "%sAverage complexity%s:%s %.2f\n", utils.BLUE, scope, utils.RESET_COLOR, result.Average)
+	fmt.Fprintf(w, "%sMax complexity%s:%s %d\n", utils.BLUE, scope, utils.RESET_COLOR, result.Max)
+}
+
+func printDirectoryResults(fileNames []string, results analyzer.ComplexityMap, w io.Writer) {
+	for _, fileName := range fileNames {
+		result := results[fileName]
+		fmt.Fprintf(w, "%s%s:%s average=%.2f, max=%d, functions=%d\n", utils.BLUE, fileName, utils.RESET_COLOR, result.Average, result.Max, len(result.Functions))
+	}
+}
+
+func printWorstFunctions(result analyzer.ComplexityResult, w io.Writer) {
+	worst := result.Worst(top)
+	if len(worst) == 0 {
+		return
+	}
+
+	// functions above the configured threshold are highlighted
+	limit := config.Active.Thresholds.MaxComplexity
+
+	fmt.Fprintf(w, "%sMost complex functions:%s\n", utils.BLUE, utils.RESET_COLOR)
+	for _, function := range worst {
+		color := utils.GREEN
+		if limit > 0 && function.Complexity > limit {
+			color = utils.RED
+		}
+		fmt.Fprintf(w, "  %s%d%s %s (%s:%d-%d)\n", color, function.Complexity, utils.RESET_COLOR, function.Name, function.File, function.StartLine, function.EndLine)
+	}
+}
+
+func init() {
+	complexityAnalyzer = &analyzer.ComplexityAnalyzerImpl{}
+	ComplexityCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file")
+	ComplexityCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files")
+	ComplexityCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (HTML by default)")
+	ComplexityCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+	ComplexityCmd.Flags().IntVarP(&top, "top", "t", 10, "Number of most complex functions to list")
+}
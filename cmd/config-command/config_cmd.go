@@ -0,0 +1,61 @@
+package config_command
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/utils"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var asJSON bool
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the project configuration (.gocli.yaml or .gocli.json)",
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Print the configuration in effect for the current directory: the
+settings of the nearest .gocli.yaml, .gocli.yml or .gocli.json found from
+the working directory upward, completed with the default values and
+overridden by the --include, --exclude, --ext and --tab-width flags.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workingDirectory, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		// the root command already loaded the configuration and applied the
+		// flags over it
+		settings := config.Active
+		path := config.Find(workingDirectory)
+
+		if path == "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s# No configuration file found, using the defaults%s\n", utils.BLUE, utils.RESET_COLOR)
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s# Configuration file: %s%s\n", utils.BLUE, path, utils.RESET_COLOR)
+		}
+
+		if asJSON {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(settings)
+		}
+
+		encoder := yaml.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(settings)
+	},
+}
+
+func init() {
+	showCmd.Flags().BoolVar(&asJSON, "json", false, "Print the configuration as JSON instead of YAML")
+	ConfigCmd.AddCommand(showCmd)
+}
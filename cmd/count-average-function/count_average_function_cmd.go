@@ -4,57 +4,134 @@ import (
 	"fmt"
 	"go-cli-tool/internal/analyzer"
 	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
+	"go-cli-tool/internal/watch"
+	"io"
 
 	"github.com/spf13/cobra"
 )
 
 var averageFunctionAnalyzer analyzer.AverageFunctionAnalyzer = &analyzer.AverageFunctionAnalyzerImpl{}
 
+var columns = []report.Column{
+	{Key: "file", Title: "File"},
+	{Key: "average_function_size", Title: "Average function size"},
+}
+
 var CountAverageFunctionSizeCmd = &cobra.Command{
-	Use:   "count-average-function-size",
-	Short: "Calculate the average function size in a JavaScript file or directory",
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:           "count-average-function-size",
+	Short:         "Calculate the average function size in a JavaScript file or directory",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		filePath, _ := cmd.Flags().GetString("file")
 		directoryPath, _ := cmd.Flags().GetString("directory")
 
-		if filePath == "" && directoryPath == "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "%sInvalid input. Please provide a file (-f) or directory (-d).%s\n", utils.RED, utils.RESET_COLOR)
-			return
-		}
-
 		utils.FilePath = filePath
 		utils.DirectoryPath = directoryPath
+		if err := policies.ValidateUserInput(); err != nil {
+			return err
+		}
+
+		if utils.Watch {
+			return watchDirectory(cmd)
+		}
 
 		if utils.FilePath != "" {
-			average := averageFunctionAnalyzer.CalculateAverageFunctionSize(utils.FilePath)
-			fmt.Fprintf(cmd.OutOrStdout(), "%sAverage function size:%s %.2f lines\n", utils.BLUE, utils.RESET_COLOR, average)
-			return
+			average, err := averageFunctionAnalyzer.CalculateAverageFunctionSize(utils.FilePath)
+			if err != nil {
+				return err
+			}
+			return report.Print(cmd.OutOrStdout(), report.Report{
+				Title:   string(utils.AVERAGE_FUNCTION_SIZE),
+				Columns: columns,
+				Rows:    [][]interface{}{{utils.FilePath, average}},
+				Summary: []report.Field{{Key: "average_function_size", Title: "Average function size", Value: average}},
+				Text: func(w io.Writer) {
+					fmt.Fprintf(w, "%sAverage function size:%s %.2f lines\n", utils.BLUE, utils.RESET_COLOR, average)
+				},
+			}, report.HTML)
 		}
 
 		if utils.DirectoryPath != "" {
 			if !policies.ValidateDirectoryPath(utils.DirectoryPath) {
-				fmt.Fprintf(cmd.OutOrStdout(), "%sPlease provide a valid directory path.%s\n", utils.RED, utils.RESET_COLOR)
-				return
+				return fmt.Errorf("please provide a valid directory path")
 			}
 
-			results, overallAverage := averageFunctionAnalyzer.CalculateAverageFunctionSizeByDirectory(utils.DirectoryPath)
+			results, overallAverage, err := averageFunctionAnalyzer.CalculateAverageFunctionSizeByDirectory(utils.DirectoryPath)
+			skipped, fatal := analyzer.Skipped(err)
+			if fatal != nil {
+				return fatal
+			}
 
-			if len(results) == 0 {
+			if len(results) == 0 && len(skipped) == 0 {
 				fmt.Fprintf(cmd.OutOrStdout(), "%sNo JavaScript files found in the provided directory.%s\n", utils.RED, utils.RESET_COLOR)
-				return
+				return nil
 			}
 
-			for file, avg := range results {
-				fmt.Fprintf(cmd.OutOrStdout(), "%sAverage function size in %s:%s %.2f lines\n", utils.BLUE, file, utils.RESET_COLOR, avg)
+			files := report.SortedKeys(results)
+			rows := make([][]interface{}, 0, len(files))
+			for _, file := range files {
+				rows = append(rows, []interface{}{file, results[file]})
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "%sOverall average function size in directory:%s %.2f lines\n", utils.BLUE, utils.RESET_COLOR, overallAverage)
+			if printErr := report.Print(cmd.OutOrStdout(), report.Report{
+				Title:   string(utils.AVERAGE_FUNCTION_SIZE),
+				Columns: columns,
+				Rows:    rows,
+				Summary: []report.Field{{Key: "overall_average_function_size", Title: "Overall average function size", Value: overallAverage}},
+				Text: func(w io.Writer) {
+					for _, file := range files {
+						fmt.Fprintf(w, "%sAverage function size in %s:%s %.2f lines\n", utils.BLUE, file, utils.RESET_COLOR, results[file])
+					}
+
+					fmt.Fprintf(w, "%sOverall average function size in directory:%s %.2f lines\n", utils.BLUE, utils.RESET_COLOR, overallAverage)
+				},
+				Skipped: skipped,
+			}, report.HTML); printErr != nil {
+				return printErr
+			}
+			return err
 		}
+		return nil
 	},
 }
 
+// watchDirectory redraws the average function size as the files of the
+// directory change.
+func watchDirectory(cmd *cobra.Command) error {
+	session := &watch.Session[float64]{
+		Title:   string(utils.AVERAGE_FUNCTION_SIZE),
+		Dir:     utils.DirectoryPath,
+		Analyze: averageFunctionAnalyzer.CalculateAverageFunctionSize,
+		Summarize: func(results map[string]float64) []watch.Metric {
+			// as by directory, the files without functions are left out
+			var sum float64
+			var withFunctions int
+			for _, average := range results {
+				if average > 0 {
+					sum += average
+					withFunctions++
+				}
+			}
+			var overallAverage float64
+			if withFunctions > 0 {
+				overallAverage = sum / float64(withFunctions)
+			}
+			return []watch.Metric{{Name: "Average function size", Value: overallAverage}}
+		},
+	}
+
+	return session.Watch(cmd.Context(), cmd.OutOrStdout())
+}
+
 func init() {
 	CountAverageFunctionSizeCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file")
 	CountAverageFunctionSizeCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files")
+	CountAverageFunctionSizeCmd.Flags().StringVar(&utils.ChangedSince, "changed-since", "", "Only analyze the files of the directory changed since the common ancestor of this git ref and HEAD, including uncommitted and untracked files")
+	CountAverageFunctionSizeCmd.Flags().BoolVar(&utils.Staged, "staged", false, "Only analyze the files of the directory staged in git")
+	CountAverageFunctionSizeCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (HTML by default)")
+	CountAverageFunctionSizeCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+	CountAverageFunctionSizeCmd.Flags().BoolVar(&utils.Watch, "watch", false, watch.FlagUsage)
 }
@@ -3,71 +3,239 @@ package count_class_and_functions
 import (
 	"fmt"
 	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/parser"
 	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
-	"go-cli-tool/templates"
+	"go-cli-tool/internal/watch"
+	"io"
 
 	"github.com/spf13/cobra"
 )
 
 var countClassAndFunctions analyzer.CountClassesAndFunctionsAnalyzer
 
+var listFunctions bool
+
+var columns = []report.Column{
+	{Key: "file", Title: "File"},
+	{Key: "classes", Title: "Classes"},
+	{Key: "functions", Title: "Functions"},
+	{Key: "interfaces", Title: "Interfaces"},
+	{Key: "enums", Title: "Enums"},
+	{Key: "type_aliases", Title: "Type aliases"},
+}
+
 var CountClassAndFunctionsCmd = &cobra.Command{
     Use:   "count-class-and-functions",
     Short: "Count classes and functions in a JavaScript file",
-    Run: func(cmd *cobra.Command, args []string) {
+    SilenceUsage:  true,
+    SilenceErrors: true,
+    RunE: func(cmd *cobra.Command, args []string) error {
         
-        err := policies.ValidateUserInput(cmd)
+        if err := policies.ValidateUserInput(); err != nil {
+            return err
+        }
 
-        if err {
-            return
+        if utils.Watch {
+            return watchDirectory(cmd)
         }
 
         if utils.FilePath != "" {
-            result := countClassAndFunctions.CountClassesAndFunctionsByFilePath(utils.FilePath)
-            fmt.Fprintf(cmd.OutOrStdout(),"%sFunctions:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Functions)
-            fmt.Fprintf(cmd.OutOrStdout(),"%sClasses:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Classes)
-            return
+            result, err := countClassAndFunctions.CountClassesAndFunctionsByFilePath(utils.FilePath)
+            if err != nil {
+                return err
+            }
+
+            var functions analyzer.FunctionsMap
+            if listFunctions {
+                fileFunctions, err := countClassAndFunctions.ListFunctionsByFilePath(utils.FilePath)
+                if err != nil {
+                    return err
+                }
+                functions = analyzer.FunctionsMap{utils.FilePath: fileFunctions}
+            }
+
+            fileReport := newReport([][]interface{}{row(utils.FilePath, result)}, result, functions)
+            fileReport.Text = func(w io.Writer) {
+                fmt.Fprintf(w,"%sFunctions:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Functions)
+                fmt.Fprintf(w,"%sClasses:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Classes)
+                printTypeDeclarations(result, "", w)
+
+                if listFunctions {
+                    printFunctions(functions[utils.FilePath], w)
+                }
+            }
+            return report.Print(cmd.OutOrStdout(), fileReport, report.HTML)
         }
 
         if utils.DirectoryPath != "" {
             if !policies.ValidateDirectoryPath(utils.DirectoryPath) {
-                fmt.Fprintf(cmd.OutOrStdout(),"%sPlease provide a valid directory path.%s", utils.RED, utils.RESET_COLOR)
-                return
+                return fmt.Errorf("please provide a valid directory path")
             }
         }
 
-        result, totalClassesAndFunctions := countClassAndFunctions.CountClassesAndFunctionsByDirectory(utils.DirectoryPath)
+        result, totalClassesAndFunctions, err := countClassAndFunctions.CountClassesAndFunctionsByDirectory(utils.DirectoryPath)
+        skipped, fatal := analyzer.Skipped(err)
+        if fatal != nil {
+            return fatal
+        }
 
-        if len(result) == 0 {
+        if len(result) == 0 && len(skipped) == 0 {
             fmt.Fprintf(cmd.OutOrStdout(),"%sNo JavaScript files found in the provided directory.%s", utils.RED, utils.RESET_COLOR)
-            return
+            return nil
+        }
+
+        var functions analyzer.FunctionsMap
+        if listFunctions {
+            // the files skipped by the listing are the ones skipped above
+            var listErr error
+            functions, listErr = countClassAndFunctions.ListFunctionsByDirectory(utils.DirectoryPath)
+            if _, fatal := analyzer.Skipped(listErr); fatal != nil {
+                return fatal
+            }
+        }
+
+        fileNames := report.SortedKeys(result)
+        rows := make([][]interface{}, 0, len(fileNames))
+        for _, fileName := range fileNames {
+            rows = append(rows, row(fileName, result[fileName]))
         }
 
-        concatFuncsAndClasses := fmt.Sprintf("Total Classes: %d, Total Functions: %d", totalClassesAndFunctions.Classes, totalClassesAndFunctions.Functions)
+        directoryReport := newReport(rows, totalClassesAndFunctions, functions)
+        directoryReport.Text = func(w io.Writer) {
+            printResults(fileNames, result, totalClassesAndFunctions, w)
 
-        if utils.OutputFilePath != "" {
-            templates.SaveResultsToHTML(result, concatFuncsAndClasses, utils.OutputFilePath, utils.COUNT_CLASS_AND_FUNCTIONS,cmd, true, true)
-        } else {
-            printResults(result, totalClassesAndFunctions,cmd)
+            for _, fileName := range report.SortedKeys(functions) {
+                fmt.Fprintf(w,"%s%s:%s\n", utils.BLUE, fileName, utils.RESET_COLOR)
+                printFunctions(functions[fileName], w)
+            }
+        }
+        directoryReport.Skipped = skipped
+        if printErr := report.Print(cmd.OutOrStdout(), directoryReport, report.HTML); printErr != nil {
+            return printErr
         }
+        return err
     },
 }
 
+func row(fileName string, result analyzer.ClassFuncResult) []interface{} {
+    return []interface{}{fileName, result.Classes, result.Functions, result.Interfaces, result.Enums, result.TypeAliases}
+}
 
-func printResults(result analyzer.ClassesAndFunctionsMap, totalClassesAndFuncByDirectory analyzer.ClassFuncResult, cmd *cobra.Command) {
-    for fileName, result := range result {
+// newReport builds the report of the counts; the function records listed
+// with -l are added to the json and yaml formats.
+func newReport(rows [][]interface{}, total analyzer.ClassFuncResult, functions analyzer.FunctionsMap) report.Report {
+    result := report.Report{
+        Title:   string(utils.COUNT_CLASS_AND_FUNCTIONS),
+        Columns: columns,
+        Rows:    rows,
+        Summary: []report.Field{
+            {Key: "total_classes", Title: "Total classes", Value: total.Classes},
+            {Key: "total_functions", Title: "Total functions", Value: total.Functions},
+            {Key: "total_interfaces", Title: "Total interfaces", Value: total.Interfaces},
+            {Key: "total_enums", Title: "Total enums", Value: total.Enums},
+            {Key: "total_type_aliases", Title: "Total type aliases", Value: total.TypeAliases},
+        },
+    }
 
-        fmt.Fprintf(cmd.OutOrStdout(),"%sFunctions:%s %s %d\n", utils.BLUE, fileName,utils.RESET_COLOR, result.Functions)
-        fmt.Fprintf(cmd.OutOrStdout(),"%sClasses:%s %s %d\n", utils.BLUE, fileName, utils.RESET_COLOR, result.Classes)
+    if functions != nil {
+        data := result.Table()
+        data["function_list"] = functions
+        result.Data = data
     }
-    fmt.Fprintf(cmd.OutOrStdout(),"%sTotal Classes in directory%s:%d\n", utils.BLUE, utils.RESET_COLOR, totalClassesAndFuncByDirectory.Classes)
-    fmt.Fprintf(cmd.OutOrStdout(),"%sTotal Functions in directory%s:%d\n", utils.BLUE, utils.RESET_COLOR, totalClassesAndFuncByDirectory.Functions)
+
+    return result
+}
+
+func printResults(fileNames []string, result analyzer.ClassesAndFunctionsMap, totalClassesAndFuncByDirectory analyzer.ClassFuncResult, w io.Writer) {
+    for _, fileName := range fileNames {
+        result := result[fileName]
+
+        fmt.Fprintf(w,"%sFunctions:%s %s %d\n", utils.BLUE, fileName,utils.RESET_COLOR, result.Functions)
+        fmt.Fprintf(w,"%sClasses:%s %s %d\n", utils.BLUE, fileName, utils.RESET_COLOR, result.Classes)
+    }
+    fmt.Fprintf(w,"%sTotal Classes in directory%s:%d\n", utils.BLUE, utils.RESET_COLOR, totalClassesAndFuncByDirectory.Classes)
+    fmt.Fprintf(w,"%sTotal Functions in directory%s:%d\n", utils.BLUE, utils.RESET_COLOR, totalClassesAndFuncByDirectory.Functions)
+    printTypeDeclarations(totalClassesAndFuncByDirectory, "Total ", w)
+}
+
+// printTypeDeclarations prints the TypeScript interface, enum and type
+// alias counts. Nothing is printed for plain JavaScript code.
+func printTypeDeclarations(result analyzer.ClassFuncResult, prefix string, w io.Writer) {
+    if result.Interfaces > 0 {
+        fmt.Fprintf(w,"%s%sInterfaces:%s %d\n", utils.BLUE, prefix, utils.RESET_COLOR, result.Interfaces)
+    }
+    if result.Enums > 0 {
+        fmt.Fprintf(w,"%s%sEnums:%s %d\n", utils.BLUE, prefix, utils.RESET_COLOR, result.Enums)
+    }
+    if result.TypeAliases > 0 {
+        fmt.Fprintf(w,"%s%sType aliases:%s %d\n", utils.BLUE, prefix, utils.RESET_COLOR, result.TypeAliases)
+    }
+}
+
+func printFunctions(functions []parser.Function, w io.Writer) {
+    for _, function := range functions {
+        name := function.Name
+        if name == "" {
+            name = "<anonymous>"
+        }
+        if function.Class != "" {
+            name = function.Class + "." + name
+        }
+
+        kind := string(function.Kind)
+        if function.Access != "" {
+            kind = function.Access + " " + kind
+        }
+        if function.Async {
+            kind = "async " + kind
+        }
+        if function.Generator {
+            kind = "generator " + kind
+        }
+
+        fmt.Fprintf(w,"  %s%s%s (%s) lines %d-%d, %d params\n", utils.GREEN, name, utils.RESET_COLOR, kind, function.StartLine, function.EndLine, function.Params)
+    }
+}
+
+// watchDirectory redraws the totals of classes, functions and type
+// declarations as the files of the directory change.
+func watchDirectory(cmd *cobra.Command) error {
+    session := &watch.Session[analyzer.ClassFuncResult]{
+        Title: string(utils.COUNT_CLASS_AND_FUNCTIONS),
+        Dir:   utils.DirectoryPath,
+        Analyze: countClassAndFunctions.CountClassesAndFunctionsByFilePath,
+        Summarize: func(results map[string]analyzer.ClassFuncResult) []watch.Metric {
+            var total analyzer.ClassFuncResult
+            for _, result := range results {
+                total.Classes += result.Classes
+                total.Functions += result.Functions
+                total.Interfaces += result.Interfaces
+                total.Enums += result.Enums
+                total.TypeAliases += result.TypeAliases
+            }
+            return []watch.Metric{
+                {Name: "Classes", Value: float64(total.Classes)},
+                {Name: "Functions", Value: float64(total.Functions)},
+                {Name: "Interfaces", Value: float64(total.Interfaces)},
+                {Name: "Enums", Value: float64(total.Enums)},
+                {Name: "Type aliases", Value: float64(total.TypeAliases)},
+            }
+        },
+    }
+
+    return session.Watch(cmd.Context(), cmd.OutOrStdout())
 }
 
 func init() {
     countClassAndFunctions = &analyzer.CountClassAndFunctionsImpl{}
     CountClassAndFunctionsCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file")
     CountClassAndFunctionsCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files")
-    CountClassAndFunctionsCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file")
-}
\ No newline at end of file
+    CountClassAndFunctionsCmd.Flags().StringVar(&utils.ChangedSince, "changed-since", "", "Only analyze the files of the directory changed since the common ancestor of this git ref and HEAD, including uncommitted and untracked files")
+    CountClassAndFunctionsCmd.Flags().BoolVar(&utils.Staged, "staged", false, "Only analyze the files of the directory staged in git")
+    CountClassAndFunctionsCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (HTML by default)")
+    CountClassAndFunctionsCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+    CountClassAndFunctionsCmd.Flags().BoolVar(&utils.Watch, "watch", false, watch.FlagUsage)
+    CountClassAndFunctionsCmd.Flags().BoolVarP(&listFunctions, "list", "l", false, "List every function with its kind, lines and parameter count")
+}
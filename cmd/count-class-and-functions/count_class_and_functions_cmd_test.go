@@ -5,6 +5,7 @@ import (
 	count_class_and_functions "go-cli-tool/cmd/count-class-and-functions"
 	"go-cli-tool/tests"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -33,17 +34,15 @@ func TestCountClassAndFunctionsCommandWithNoArgs(t *testing.T) {
 
     cmd.SetArgs([]string{})
 
-	cmd.Execute()
-
-    expectedOutput := "Please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files.\n"
-
-    actualOutput := stdout.String()
+	err := cmd.Execute()
 
-    if actualOutput != expectedOutput {
-        t.Errorf("CountClassAndFunctionsCommand() = %v, want %v", actualOutput, expectedOutput)
-        t.Logf("Actual Output: %q", actualOutput)
-        t.Logf("Expected Output: %q", expectedOutput)
-    }
+	expectedError := "please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("CountClassAndFunctionsCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountClassAndFunctionsCommand() printed %q, want no output", stdout.String())
+	}
 }
 
 func TestCountClassAndFunctionsWithFilePath(t *testing.T) {
@@ -59,7 +58,7 @@ func TestCountClassAndFunctionsWithFilePath(t *testing.T) {
 
 	cmd.Execute()
 
-	expectedOutput := "\x1b[34mFunctions:\x1b[0m 20\n\x1b[34mClasses:\x1b[0m 1\n"
+	expectedOutput := "\x1b[34mFunctions:\x1b[0m 21\n\x1b[34mClasses:\x1b[0m 1\n"
 
 	actualOutput := stdout.String()
 
@@ -81,16 +80,14 @@ func TestCountClassAndFunctionsCommandWithWrongFilePathExtension(t *testing.T) {
 
 	cmd.SetArgs([]string{"-f", "../../main.go"})
 
-	cmd.Execute()
-
-	expectedOutput := "\x1b[31mOnly JavaScript files are accepted.\x1b[0m"
-
-	actualOutput := stdout.String()
+	err := cmd.Execute()
 
-	if actualOutput != expectedOutput {
-		t.Errorf("CountClassAndFunctionsCommand() = %v, want %v", actualOutput, expectedOutput)
-		t.Logf("Actual Output: %q", actualOutput)
-		t.Logf("Expected Output: %q", expectedOutput)
+	expectedError := "only JavaScript and TypeScript files are accepted"
+	if err == nil || !strings.Contains(err.Error(), expectedError) {
+		t.Errorf("CountClassAndFunctionsCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountClassAndFunctionsCommand() printed %q, want no output", stdout.String())
 	}
 }
 
@@ -110,7 +107,7 @@ func TestCountClassAndFunctionsCommandWithDirectoryPath(t *testing.T) {
         t.Errorf("CountClassAndFunctions() error = %v, want nil", err)
     }
 
-    expectedOutput := "\x1b[34mFunctions:test.js \x1b[0m 20\n\x1b[34mClasses:test.js \x1b[0m 1\n\x1b[34mTotal Classes in directory\x1b[0m:1\n\x1b[34mTotal Functions in directory\x1b[0m:20\n"
+    expectedOutput := "\x1b[34mFunctions:test.js \x1b[0m 21\n\x1b[34mClasses:test.js \x1b[0m 1\n\x1b[34mTotal Classes in directory\x1b[0m:1\n\x1b[34mTotal Functions in directory\x1b[0m:21\n"
 
     actualOutput := stdout.String()
 
@@ -132,16 +129,15 @@ func TestCountClassAndFunctionsCommandWithWrongDirectoryPath(t *testing.T) {
 
 	cmd.SetArgs([]string{"-d", "../../main.go"})
 
-	cmd.Execute()
-
-	expectedOutput := "\x1b[31mPlease provide a valid directory path.\x1b[0m"
+	err := cmd.Execute()
 
-	actualOutput := stdout.String()
+	expectedError := "please provide a valid directory path"
 
-	if actualOutput != expectedOutput {
-		t.Errorf("CountClassAndFunctionsCommand() = %v, want %v", actualOutput, expectedOutput)
-		t.Logf("Actual Output: %q", actualOutput)
-		t.Logf("Expected Output: %q", expectedOutput)
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("CountClassAndFunctionsCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountClassAndFunctionsCommand() printed %q, want no output", stdout.String())
 	}
 }
 
@@ -4,65 +4,127 @@ import (
 	"fmt"
 	"go-cli-tool/internal/analyzer"
 	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
-	"go-cli-tool/templates"
+	"go-cli-tool/internal/watch"
+	"io"
 
 	"github.com/spf13/cobra"
 )
 
 var countCommentsAnalyzer analyzer.CountCommentsAnalyzer
 
+var columns = []report.Column{{Key: "file", Title: "File"}, {Key: "comment_lines", Title: "Comment lines"}}
+
 var CountCommentsCmd = &cobra.Command{
     Use:   "count-comments",
     Short: "Count total comment lines in a JavaScript file",
-    Run: func(cmd *cobra.Command, args []string) {
+    SilenceUsage:  true,
+    SilenceErrors: true,
+    RunE: func(cmd *cobra.Command, args []string) error {
 
-        err := policies.ValidateUserInput(cmd)
+        if err := policies.ValidateUserInput(); err != nil {
+            return err
+        }
 
-        if err {
-            return
+        if utils.Watch {
+            return watchDirectory(cmd)
         }
 
         if utils.FilePath != "" {
-            result := countCommentsAnalyzer.CountCommentsByFilePath(utils.FilePath)
-            fmt.Fprintf(cmd.OutOrStdout(),"%sTotal comments:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.CommentLines)
-            return
+            result, err := countCommentsAnalyzer.CountCommentsByFilePath(utils.FilePath)
+            if err != nil {
+                return err
+            }
+            return report.Print(cmd.OutOrStdout(), report.Report{
+                Title:   string(utils.COUNT_COMMENTS),
+                Columns: columns,
+                Rows:    [][]interface{}{{utils.FilePath, result.CommentLines}},
+                Summary: []report.Field{{Key: "total_comments", Title: "Total comments", Value: result.CommentLines}},
+                Text: func(w io.Writer) {
+                    fmt.Fprintf(w,"%sTotal comments:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.CommentLines)
+                },
+            }, report.HTML)
         }
 
         if utils.DirectoryPath != "" {
             if !policies.ValidateDirectoryPath(utils.DirectoryPath) {
-                fmt.Fprintf(cmd.OutOrStdout(),"%sPlease provide a valid directory path.%s", utils.RED, utils.RESET_COLOR)
-                return
+                return fmt.Errorf("please provide a valid directory path")
             }
         }
 
-        result, totalCommentsByDirectory := countCommentsAnalyzer.CountCommentsByDirectory(utils.DirectoryPath)
+        result, totalCommentsByDirectory, err := countCommentsAnalyzer.CountCommentsByDirectory(utils.DirectoryPath)
+        skipped, fatal := analyzer.Skipped(err)
+        if fatal != nil {
+            return fatal
+        }
 
-        if len(result) == 0 {
+        if len(result) == 0 && len(skipped) == 0 {
             fmt.Fprintf(cmd.OutOrStdout(),"%sNo JavaScript files found in the provided directory.%s", utils.RED, utils.RESET_COLOR)
-            return
+            return nil
         }
 
-        totalLinesStr := fmt.Sprintf("Total comments: %d", totalCommentsByDirectory.TotalComments)
-
-        if utils.OutputFilePath != "" {
-            templates.SaveResultsToHTML(result, totalLinesStr, utils.OutputFilePath, utils.COUNT_COMMENTS, cmd, false, false)
-        } else {
-            printResults(result, totalCommentsByDirectory,cmd)
+        directory := directoryReport(result, totalCommentsByDirectory)
+        directory.Skipped = skipped
+        if printErr := report.Print(cmd.OutOrStdout(), directory, report.HTML); printErr != nil {
+            return printErr
         }
+        return err
     },
 }
 
-func printResults(result analyzer.CommentsMap, totalCommentsByDirectory analyzer.CommentResult, cmd *cobra.Command) {
-    for fileName, result := range result {
-        fmt.Fprintf(cmd.OutOrStdout(),"%s Comment lines in %s:%s %d\n", utils.BLUE, fileName, utils.RESET_COLOR, result.CommentLines)
+func directoryReport(result analyzer.CommentsMap, totalCommentsByDirectory analyzer.CommentResult) report.Report {
+    fileNames := report.SortedKeys(result)
+
+    rows := make([][]interface{}, 0, len(fileNames))
+    for _, fileName := range fileNames {
+        rows = append(rows, []interface{}{fileName, result[fileName].CommentLines})
+    }
+
+    return report.Report{
+        Title:   string(utils.COUNT_COMMENTS),
+        Columns: columns,
+        Rows:    rows,
+        Summary: []report.Field{{Key: "total_comments", Title: "Total comments", Value: totalCommentsByDirectory.TotalComments}},
+        Text: func(w io.Writer) {
+            printResults(fileNames, result, totalCommentsByDirectory, w)
+        },
     }
-    fmt.Fprintf(cmd.OutOrStdout(),"%sTotal Comments in directory:%s %d\n", utils.BLUE, utils.RESET_COLOR, totalCommentsByDirectory.TotalComments)
+}
+
+func printResults(fileNames []string, result analyzer.CommentsMap, totalCommentsByDirectory analyzer.CommentResult, w io.Writer) {
+    for _, fileName := range fileNames {
+        fmt.Fprintf(w,"%s Comment lines in %s:%s %d\n", utils.BLUE, fileName, utils.RESET_COLOR, result[fileName].CommentLines)
+    }
+    fmt.Fprintf(w,"%sTotal Comments in directory:%s %d\n", utils.BLUE, utils.RESET_COLOR, totalCommentsByDirectory.TotalComments)
+}
+
+// watchDirectory redraws the total of comment lines as the files of the
+// directory change.
+func watchDirectory(cmd *cobra.Command) error {
+    session := &watch.Session[analyzer.CommentResult]{
+        Title: string(utils.COUNT_COMMENTS),
+        Dir:   utils.DirectoryPath,
+        Analyze: countCommentsAnalyzer.CountCommentsByFilePath,
+        Summarize: func(results map[string]analyzer.CommentResult) []watch.Metric {
+            total := 0
+            for _, result := range results {
+                total += result.CommentLines
+            }
+            return []watch.Metric{{Name: "Total comments", Value: float64(total)}}
+        },
+    }
+
+    return session.Watch(cmd.Context(), cmd.OutOrStdout())
 }
 
 func init() {
     countCommentsAnalyzer = &analyzer.CountCommentsAnalyzerImpl{}
     CountCommentsCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file (must be a single file, not a directory)")
     CountCommentsCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files. The tool will automatically expand the provided path.")
-    CountCommentsCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file. The tool will generate an HTML report with the results if a directory is provided. If not provided, the tool will print the results to the console. Note: This flag is ignored when the -f flag is used to provide a single file.")
-}
\ No newline at end of file
+    CountCommentsCmd.Flags().StringVar(&utils.ChangedSince, "changed-since", "", "Only analyze the files of the directory changed since the common ancestor of this git ref and HEAD, including uncommitted and untracked files")
+    CountCommentsCmd.Flags().BoolVar(&utils.Staged, "staged", false, "Only analyze the files of the directory staged in git")
+    CountCommentsCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into. Without --format the format follows the file extension, and HTML is used for a directory. If not provided, the tool will print the results to the console.")
+    CountCommentsCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+    CountCommentsCmd.Flags().BoolVar(&utils.Watch, "watch", false, watch.FlagUsage)
+}
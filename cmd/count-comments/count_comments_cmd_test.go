@@ -5,6 +5,7 @@ import (
 	count_comments "go-cli-tool/cmd/count-comments"
 	"go-cli-tool/tests"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -34,17 +35,15 @@ func TestCountCommentsCommandWithNoArgs(t *testing.T) {
 
     cmd.SetArgs([]string{})
 
-	cmd.Execute()
-
-    expectedOutput := "Please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files.\n"
-
-    actualOutput := stdout.String()
+	err := cmd.Execute()
 
-    if actualOutput != expectedOutput {
-        t.Errorf("CountCommentsCommand() = %v, want %v", actualOutput, expectedOutput)
-        t.Logf("Actual Output: %q", actualOutput)
-        t.Logf("Expected Output: %q", expectedOutput)
-    }
+	expectedError := "please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("CountCommentsCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountCommentsCommand() printed %q, want no output", stdout.String())
+	}
 }
 
 func TestCountCommentsCommandWithFilePath(t *testing.T) {
@@ -82,16 +81,14 @@ func TestCountCommentsCommandWithWrongFilePathExtension(t *testing.T) {
 
 	cmd.SetArgs([]string{"-f", "../../main.go"})
 
-	cmd.Execute()
-
-	expectedOutput := "\x1b[31mOnly JavaScript files are accepted.\x1b[0m"
-
-	actualOutput := stdout.String()
+	err := cmd.Execute()
 
-	if actualOutput != expectedOutput {
-		t.Errorf("CountCommentsCommand() = %v, want %v", actualOutput, expectedOutput)
-		t.Logf("Actual Output: %q", actualOutput)
-		t.Logf("Expected Output: %q", expectedOutput)
+	expectedError := "only JavaScript and TypeScript files are accepted"
+	if err == nil || !strings.Contains(err.Error(), expectedError) {
+		t.Errorf("CountCommentsCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountCommentsCommand() printed %q, want no output", stdout.String())
 	}
 }
 
@@ -133,16 +130,15 @@ func TestCountCommentsCommandWithWrongDirectoryPath(t *testing.T) {
 
 	cmd.SetArgs([]string{"-d", "../../main.go"})
 
-	cmd.Execute()
-
-	expectedOutput := "\x1b[31mPlease provide a valid directory path.\x1b[0m"
+	err := cmd.Execute()
 
-	actualOutput := stdout.String()
+	expectedError := "please provide a valid directory path"
 
-	if actualOutput != expectedOutput {
-		t.Errorf("CountCommentsCommand() = %v, want %v", actualOutput, expectedOutput)
-		t.Logf("Actual Output: %q", actualOutput)
-		t.Logf("Expected Output: %q", expectedOutput)
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("CountCommentsCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountCommentsCommand() printed %q, want no output", stdout.String())
 	}
 }
 
@@ -4,67 +4,128 @@ import (
 	"fmt"
 	"go-cli-tool/internal/analyzer"
 	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
-	"go-cli-tool/templates"
+	"go-cli-tool/internal/watch"
+	"io"
 
 	"github.com/spf13/cobra"
 )
 
 var countLinesAnalyzer analyzer.CountLinesAnalyzer
 
+var columns = []report.Column{{Key: "file", Title: "File"}, {Key: "lines", Title: "Lines"}}
+
 var CountLinesAnalyzer = &cobra.Command{
     Use:   "count-lines",
     Short: "Count total lines in a JavaScript file",
-    Run: func(cmd *cobra.Command, args []string) {
+    SilenceUsage:  true,
+    SilenceErrors: true,
+    RunE: func(cmd *cobra.Command, args []string) error {
 
-        err := policies.ValidateUserInput(cmd)
+        if err := policies.ValidateUserInput(); err != nil {
+            return err
+        }
 
-        if err {
-            return
+        if utils.Watch {
+            return watchDirectory(cmd)
         }
 
         if utils.FilePath != "" {
-            result := countLinesAnalyzer.CountLinesByFilePath(utils.FilePath)
-            fmt.Fprintf(cmd.OutOrStdout(),"%sTotal lines:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.TotalLines)
-            return
+            result, err := countLinesAnalyzer.CountLinesByFilePath(utils.FilePath)
+            if err != nil {
+                return err
+            }
+            return report.Print(cmd.OutOrStdout(), report.Report{
+                Title:   string(utils.COUNT_LINES),
+                Columns: columns,
+                Rows:    [][]interface{}{{utils.FilePath, result.TotalLines}},
+                Summary: []report.Field{{Key: "total_lines", Title: "Total lines", Value: result.TotalLines}},
+                Text: func(w io.Writer) {
+                    fmt.Fprintf(w,"%sTotal lines:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.TotalLines)
+                },
+            }, report.HTML)
         }
 
         if utils.DirectoryPath != "" {
             if !policies.ValidateDirectoryPath(utils.DirectoryPath) {
-                fmt.Fprintf(cmd.OutOrStdout(),"%sPlease provide a valid directory path.%s", utils.RED, utils.RESET_COLOR)
-                return
+                return fmt.Errorf("please provide a valid directory path")
             }
         }
 
-        result, totalLinesByDirectory := countLinesAnalyzer.CountLinesByDirectory(utils.DirectoryPath)
-
-        totalLinesStr := fmt.Sprintf("Total lines: %d", totalLinesByDirectory.TotalLines)
+        result, totalLinesByDirectory, err := countLinesAnalyzer.CountLinesByDirectory(utils.DirectoryPath)
+        skipped, fatal := analyzer.Skipped(err)
+        if fatal != nil {
+            return fatal
+        }
 
-        if len(result) == 0 {
+        if len(result) == 0 && len(skipped) == 0 {
             fmt.Fprintf(cmd.OutOrStdout(),"%sNo JavaScript files found in the provided directory.%s", utils.RED, utils.RESET_COLOR)
-            return
+            return nil
         }
 
-        if utils.OutputFilePath != "" {
-
-            templates.SaveResultsToHTML(result, totalLinesStr, utils.OutputFilePath, utils.COUNT_LINES, cmd, false, false)
-        } else {
-            printResults(result, totalLinesByDirectory,cmd)
+        directory := directoryReport(result, totalLinesByDirectory)
+        directory.Skipped = skipped
+        if printErr := report.Print(cmd.OutOrStdout(), directory, report.HTML); printErr != nil {
+            return printErr
         }
+        return err
     },
 }
 
-func printResults(result analyzer.FilesNameCountLineMap, totalLinesByDirectory analyzer.LineResult, cmd *cobra.Command) {
-    for fileName, result := range result {
+func directoryReport(result analyzer.FilesNameCountLineMap, totalLinesByDirectory analyzer.LineResult) report.Report {
+    fileNames := report.SortedKeys(result)
+
+    rows := make([][]interface{}, 0, len(fileNames))
+    for _, fileName := range fileNames {
+        rows = append(rows, []interface{}{fileName, result[fileName].TotalLines})
+    }
+
+    return report.Report{
+        Title:   string(utils.COUNT_LINES),
+        Columns: columns,
+        Rows:    rows,
+        Summary: []report.Field{{Key: "total_lines", Title: "Total lines", Value: totalLinesByDirectory.TotalLines}},
+        Text: func(w io.Writer) {
+            printResults(fileNames, result, totalLinesByDirectory, w)
+        },
+    }
+}
+
+func printResults(fileNames []string, result analyzer.FilesNameCountLineMap, totalLinesByDirectory analyzer.LineResult, w io.Writer) {
+    for _, fileName := range fileNames {
 
-        fmt.Fprintf(cmd.OutOrStdout(),"%s Total lines in %s:%s %d\n", utils.BLUE, fileName, utils.RESET_COLOR, result.TotalLines)
+        fmt.Fprintf(w,"%s Total lines in %s:%s %d\n", utils.BLUE, fileName, utils.RESET_COLOR, result[fileName].TotalLines)
     }
-    fmt.Fprintf(cmd.OutOrStdout(),"%sTotal lines in directory:%s %d\n", utils.BLUE, utils.RESET_COLOR, totalLinesByDirectory.TotalLines)
+    fmt.Fprintf(w,"%sTotal lines in directory:%s %d\n", utils.BLUE, utils.RESET_COLOR, totalLinesByDirectory.TotalLines)
+}
+
+// watchDirectory redraws the total of lines as the files of the directory
+// change.
+func watchDirectory(cmd *cobra.Command) error {
+    session := &watch.Session[analyzer.LineResult]{
+        Title: string(utils.COUNT_LINES),
+        Dir:   utils.DirectoryPath,
+        Analyze: countLinesAnalyzer.CountLinesByFilePath,
+        Summarize: func(results map[string]analyzer.LineResult) []watch.Metric {
+            total := 0
+            for _, result := range results {
+                total += result.TotalLines
+            }
+            return []watch.Metric{{Name: "Total lines", Value: float64(total)}}
+        },
+    }
+
+    return session.Watch(cmd.Context(), cmd.OutOrStdout())
 }
 
 func init() {
     countLinesAnalyzer = &analyzer.CountLinesAnalyzerImpl{}
     CountLinesAnalyzer.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file (must be a single file, not a directory)")
     CountLinesAnalyzer.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files. The tool will automatically expand the provided path.")
-    CountLinesAnalyzer.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file. The tool will generate an HTML report with the results if a directory is provided. If not provided, the tool will print the results to the console. Note: This flag is ignored when the -f flag is used to provide a single file.")
-}
\ No newline at end of file
+    CountLinesAnalyzer.Flags().StringVar(&utils.ChangedSince, "changed-since", "", "Only analyze the files of the directory changed since the common ancestor of this git ref and HEAD, including uncommitted and untracked files")
+    CountLinesAnalyzer.Flags().BoolVar(&utils.Staged, "staged", false, "Only analyze the files of the directory staged in git")
+    CountLinesAnalyzer.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into. Without --format the format follows the file extension, and HTML is used for a directory. If not provided, the tool will print the results to the console.")
+    CountLinesAnalyzer.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+    CountLinesAnalyzer.Flags().BoolVar(&utils.Watch, "watch", false, watch.FlagUsage)
+}
@@ -5,6 +5,8 @@ import (
 	count_lines "go-cli-tool/cmd/count-lines"
 	"go-cli-tool/tests"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -37,17 +39,15 @@ func TestCountLinesCommandWithNoArgs(t *testing.T) {
 	cmd.SetArgs([]string{})
 
 	// execute the count lines command w/ args
-	cmd.Execute()
-
-	// check the output
-	expectedOutput := "Please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files.\n"
-
-	actualOutput := stdout.String()
+	err := cmd.Execute()
 
-	if actualOutput != expectedOutput {
-		t.Errorf("CountLinesCommand() = %v, want %v", actualOutput, expectedOutput)
-		t.Logf("Actual Output: %q", actualOutput)
-		t.Logf("Expected Output: %q", expectedOutput)
+	// check the returned error and that nothing was printed
+	expectedError := "please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("CountLinesCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountLinesCommand() printed %q, want no output", stdout.String())
 	}
 }
 
@@ -96,17 +96,15 @@ func TestCountLinesCommandWithWrongFilePathExtension(t *testing.T) {
 	cmd.SetArgs([]string{"-f", "../../main.go"})
 
 	// execute the count lines command w/ args
-	cmd.Execute()
-
-	// check the output
-	expectedOutput := "\x1b[31mOnly JavaScript files are accepted.\x1b[0m"
-
-	actualOutput := stdout.String()
+	err := cmd.Execute()
 
-	if actualOutput != expectedOutput {
-		t.Errorf("CountLinesCommand() = %v, want %v", actualOutput, expectedOutput)
-		t.Logf("Actual Output: %q", actualOutput)
-		t.Logf("Expected Output: %q", expectedOutput)
+	// check the returned error and that nothing was printed
+	expectedError := "only JavaScript and TypeScript files are accepted"
+	if err == nil || !strings.Contains(err.Error(), expectedError) {
+		t.Errorf("CountLinesCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountLinesCommand() printed %q, want no output", stdout.String())
 	}
 }
 
@@ -157,17 +155,16 @@ func TestCountLinesCommandWithWrongDirectoryPath(t *testing.T) {
 	cmd.SetArgs([]string{"-d", "../../main.go"})
 
 	// execute the count lines command w/ args
-	cmd.Execute()
+	err := cmd.Execute()
 
-	// check the output
-	expectedOutput := "\x1b[31mPlease provide a valid directory path.\x1b[0m"
+	// check the error, which the root command prints
+	expectedError := "please provide a valid directory path"
 
-	actualOutput := stdout.String()
-
-	if actualOutput != expectedOutput {
-		t.Errorf("CountLinesCommand() = %v, want %v", actualOutput, expectedOutput)
-		t.Logf("Actual Output: %q", actualOutput)
-		t.Logf("Expected Output: %q", expectedOutput)
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("CountLinesCommand() error = %v, want %v", err, expectedError)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("CountLinesCommand() printed %q, want no output", stdout.String())
 	}
 }
 
@@ -239,3 +236,51 @@ func TestCountLinesCommandWithValidDirectoryAndGeneratingReportHTML(t *testing.T
 		t.Errorf("Failed to remove the report file: %v", err)
 	}
 }
+
+func TestCountLinesCommandWithUnreadableFile(t *testing.T) {
+
+	tests.ResetGlobals()
+
+	// copy the test file next to a link to a file that does not exist
+	dir := t.TempDir()
+	source, err := os.ReadFile("../../javascript-tests/test.js")
+	if err != nil {
+		t.Fatalf("Failed to read the test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "test.js"), source, 0644); err != nil {
+		t.Fatalf("Failed to write the test file: %v", err)
+	}
+	locked := filepath.Join(dir, "locked.js")
+	if err := os.Symlink(filepath.Join(dir, "removed.js"), locked); err != nil {
+		t.Fatalf("Failed to create the link: %v", err)
+	}
+
+	// create the count lines command
+	cmd := count_lines.CountLinesAnalyzer
+
+	// redirect the stdout to a buffer to capture the output
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+
+	// set the args
+	cmd.SetArgs([]string{"-d", dir})
+
+	// the report of the other files is printed, and the command fails
+	expectedError := locked + " was skipped: no such file or directory"
+	if err := cmd.Execute(); err == nil || err.Error() != expectedError {
+		t.Errorf("CountLinesCommand() error = %v, want %v", err, expectedError)
+	}
+
+	// check the output
+	expectedOutput := "\x1b[34m Total lines in test.js:\x1b[0m 157\n\x1b[34mTotal lines in directory:\x1b[0m 157\n" +
+		"\x1b[31mSkipped files:\x1b[0m\n  " + locked + ": no such file or directory\n"
+
+	actualOutput := stdout.String()
+
+	if actualOutput != expectedOutput {
+		t.Errorf("CountLinesCommand() = %v, want %v", actualOutput, expectedOutput)
+		t.Logf("Actual Output: %q", actualOutput)
+		t.Logf("Expected Output: %q", expectedOutput)
+	}
+}
@@ -4,74 +4,136 @@ import (
 	"fmt"
 	"go-cli-tool/internal/analyzer"
 	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
-	"go-cli-tool/templates"
+	"go-cli-tool/internal/watch"
+	"io"
 
 	"github.com/spf13/cobra"
 )
 
 var methodCountAnalyzer analyzer.MethodCountAnalyzer
 
+var columns = []report.Column{
+	{Key: "file", Title: "File"},
+	{Key: "public", Title: "Public methods"},
+	{Key: "private", Title: "Private methods"},
+}
+
 var CountMethodsAnalyzer = &cobra.Command{
-	Use:   "count-methods",
-	Short: "Count public and private methods in JavaScript files",
-	Run: func(cmd *cobra.Command, args []string) {
-		err := policies.ValidateUserInput(cmd)
-		if err {
-			return
+	Use:           "count-methods",
+	Short:         "Count public and private methods in JavaScript files",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := policies.ValidateUserInput(); err != nil {
+			return err
+		}
+
+		if utils.Watch {
+			return watchDirectory(cmd)
 		}
 
 		if utils.FilePath != "" {
-			result := methodCountAnalyzer.AnalyzeFile(utils.FilePath)
-			printSingleFileResult(result, cmd)
-			return
+			result, err := methodCountAnalyzer.AnalyzeFile(utils.FilePath)
+			if err != nil {
+				return err
+			}
+			fileReport := newReport([][]interface{}{{utils.FilePath, result.Public, result.Private}}, result)
+			fileReport.Text = func(w io.Writer) {
+				printSingleFileResult(result, w)
+			}
+			return report.Print(cmd.OutOrStdout(), fileReport, report.HTML)
 		}
 
 		if utils.DirectoryPath != "" && !policies.ValidateDirectoryPath(utils.DirectoryPath) {
-			fmt.Fprintf(cmd.OutOrStdout(), "%sInvalid directory path.%s\n", utils.RED, utils.RESET_COLOR)
-			return
+			return fmt.Errorf("please provide a valid directory path")
 		}
 
-		result, total := methodCountAnalyzer.AnalyzeDirectory(utils.DirectoryPath)
+		result, total, err := methodCountAnalyzer.AnalyzeDirectory(utils.DirectoryPath)
+		skipped, fatal := analyzer.Skipped(err)
+		if fatal != nil {
+			return fatal
+		}
 
-		if len(result) == 0 {
+		if len(result) == 0 && len(skipped) == 0 {
 			fmt.Fprintf(cmd.OutOrStdout(), "%sNo JavaScript files found in the provided directory.%s\n", utils.RED, utils.RESET_COLOR)
-			return
+			return nil
 		}
 
-		if utils.OutputFilePath != "" {
-			// Converte o resultado para o formato usado no template HTML
-			filesNameCountLineMap := make(analyzer.FilesNameCountLineMap)
-			for file, data := range result {
-				filesNameCountLineMap[file] = analyzer.LineResult{
-					TotalLines: int(data.Public + data.Private),
-				}
-			}
-
-			summary := fmt.Sprintf("Total public: %d, private: %d", total.Public, total.Private)
+		files := report.SortedKeys(result)
+		rows := make([][]interface{}, 0, len(files))
+		for _, file := range files {
+			rows = append(rows, []interface{}{file, result[file].Public, result[file].Private})
+		}
 
-			templates.SaveResultsToHTML(filesNameCountLineMap, summary, utils.OutputFilePath, "COUNT_METHODS", cmd, true, true)
-		} else {
-			printDirectoryResults(result, total, cmd)
+		directoryReport := newReport(rows, total)
+		directoryReport.Text = func(w io.Writer) {
+			printDirectoryResults(files, result, total, w)
 		}
+		directoryReport.Skipped = skipped
+		if printErr := report.Print(cmd.OutOrStdout(), directoryReport, report.HTML); printErr != nil {
+			return printErr
+		}
+		return err
 	},
 }
 
-func printSingleFileResult(result analyzer.MethodCountResult, cmd *cobra.Command) {
-	fmt.Fprintf(cmd.OutOrStdout(), "%sPublic methods:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Public)
-	fmt.Fprintf(cmd.OutOrStdout(), "%sPrivate methods:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Private)
+func newReport(rows [][]interface{}, total analyzer.MethodCountResult) report.Report {
+	return report.Report{
+		Title:   string(utils.COUNT_METHODS),
+		Columns: columns,
+		Rows:    rows,
+		Summary: []report.Field{
+			{Key: "total_public", Title: "Total public methods", Value: total.Public},
+			{Key: "total_private", Title: "Total private methods", Value: total.Private},
+		},
+	}
+}
+
+func printSingleFileResult(result analyzer.MethodCountResult, w io.Writer) {
+	fmt.Fprintf(w, "%sPublic methods:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Public)
+	fmt.Fprintf(w, "%sPrivate methods:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.Private)
+}
+
+func printDirectoryResults(files []string, result analyzer.MethodCountMap, total analyzer.MethodCountResult, w io.Writer) {
+	for _, file := range files {
+		data := result[file]
+		fmt.Fprintf(w, "%s%s:%s public=%d, private=%d\n", utils.BLUE, file, utils.RESET_COLOR, data.Public, data.Private)
+	}
+	fmt.Fprintf(w, "%sTotal:%s public=%d, private=%d\n", utils.BLUE, utils.RESET_COLOR, total.Public, total.Private)
 }
 
-func printDirectoryResults(result analyzer.MethodCountMap, total analyzer.MethodCountResult, cmd *cobra.Command) {
-	for file, data := range result {
-		fmt.Fprintf(cmd.OutOrStdout(), "%s%s:%s public=%d, private=%d\n", utils.BLUE, file, utils.RESET_COLOR, data.Public, data.Private)
+// watchDirectory redraws the totals of public and private methods as the
+// files of the directory change.
+func watchDirectory(cmd *cobra.Command) error {
+	session := &watch.Session[analyzer.MethodCountResult]{
+		Title:   string(utils.COUNT_METHODS),
+		Dir:     utils.DirectoryPath,
+		Analyze: methodCountAnalyzer.AnalyzeFile,
+		Summarize: func(results map[string]analyzer.MethodCountResult) []watch.Metric {
+			var total analyzer.MethodCountResult
+			for _, result := range results {
+				total.Public += result.Public
+				total.Private += result.Private
+			}
+			return []watch.Metric{
+				{Name: "Public methods", Value: float64(total.Public)},
+				{Name: "Private methods", Value: float64(total.Private)},
+			}
+		},
 	}
-	fmt.Fprintf(cmd.OutOrStdout(), "%sTotal:%s public=%d, private=%d\n", utils.BLUE, utils.RESET_COLOR, total.Public, total.Private)
+
+	return session.Watch(cmd.Context(), cmd.OutOrStdout())
 }
 
 func init() {
 	methodCountAnalyzer = &analyzer.MethodCountAnalyzerImpl{}
 	CountMethodsAnalyzer.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to a JavaScript file")
 	CountMethodsAnalyzer.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to a directory")
-	CountMethodsAnalyzer.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "HTML output file (optional)")
+	CountMethodsAnalyzer.Flags().StringVar(&utils.ChangedSince, "changed-since", "", "Only analyze the files of the directory changed since the common ancestor of this git ref and HEAD, including uncommitted and untracked files")
+	CountMethodsAnalyzer.Flags().BoolVar(&utils.Staged, "staged", false, "Only analyze the files of the directory staged in git")
+	CountMethodsAnalyzer.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Output file, or a directory to write report.<format> into (HTML by default)")
+	CountMethodsAnalyzer.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+	CountMethodsAnalyzer.Flags().BoolVar(&utils.Watch, "watch", false, watch.FlagUsage)
 }
@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"go-cli-tool/internal/analyzer"
 	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
-	"go-cli-tool/templates"
-	"path/filepath"
+	"go-cli-tool/internal/watch"
+	"io"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,76 +15,155 @@ import (
 
 var countPercentAnalyzer analyzer.CountPercentAnalyzer
 
+var columns = []report.Column{
+	{Key: "file", Title: "File"},
+	{Key: "lines", Title: "Lines"},
+	{Key: "comment_lines", Title: "Comment lines"},
+	{Key: "comment_percentage", Title: "Comment %"},
+}
+
 var CountPercentCmd = &cobra.Command{
-	Use:   "count-percent",
-	Short: "Count total comment lines and calculate the percentage of comments in a JavaScript file",
-	Run: func(cmd *cobra.Command, args []string) {
-		err := policies.ValidateUserInput(cmd)
-		if err {
-			return
+	Use:           "count-percent",
+	Short:         "Count total comment lines and calculate the percentage of comments in a JavaScript file",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := policies.ValidateUserInput(); err != nil {
+			return err
+		}
+
+		if utils.Watch {
+			return watchDirectory(cmd)
 		}
 
 		// Se o arquivo for passado com o flag -f
 		if utils.FilePath != "" {
-			result := countPercentAnalyzer.CountPercentByFilePath(utils.FilePath)
-			fmt.Fprintf(cmd.OutOrStdout(), "%sTotal comments in file:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.CommentLines)
-			fmt.Fprintf(cmd.OutOrStdout(), "%sComment Percentage in file:%s %.2f%%\n", utils.BLUE, utils.RESET_COLOR, result.CommentPercentage)
-			return
+			result, err := countPercentAnalyzer.CountPercentByFilePath(utils.FilePath)
+			if err != nil {
+				return err
+			}
+			return report.Print(cmd.OutOrStdout(), report.Report{
+				Title:   string(utils.COMMENT_PERCENTAGE),
+				Columns: columns,
+				Rows:    [][]interface{}{row(utils.FilePath, result)},
+				Summary: summary(result),
+				Text: func(w io.Writer) {
+					fmt.Fprintf(w, "%sTotal comments in file:%s %d\n", utils.BLUE, utils.RESET_COLOR, result.CommentLines)
+					fmt.Fprintf(w, "%sComment Percentage in file:%s %.2f%%\n", utils.BLUE, utils.RESET_COLOR, result.CommentPercentage)
+				},
+			}, report.HTML)
 		}
 
 		// Se o diretório for passado com o flag -d
 		if utils.DirectoryPath != "" {
 			if !policies.ValidateDirectoryPath(utils.DirectoryPath) {
-				fmt.Fprintf(cmd.OutOrStdout(), "%sPlease provide a valid directory path.%s\n", utils.RED, utils.RESET_COLOR)
-				return
+				return fmt.Errorf("please provide a valid directory path")
 			}
 
-			results, totals := countPercentAnalyzer.CountCommentsByDirectory(utils.DirectoryPath)
+			results, totals, err := countPercentAnalyzer.CountCommentsByDirectory(utils.DirectoryPath)
+			skipped, fatal := analyzer.Skipped(err)
+			if fatal != nil {
+				return fatal
+			}
 
-			if len(results) == 0 {
+			if len(results) == 0 && len(skipped) == 0 {
 				fmt.Fprintf(cmd.OutOrStdout(), "%sNo JavaScript files found in the provided directory.%s\n", utils.RED, utils.RESET_COLOR)
-				return
+				return nil
 			}
 
-			// Exibir total de comentários no diretório
-			totalLinesStr := fmt.Sprintf("Total lines: %d | Comment lines: %d", totals.TotalLines, totals.CommentLines)
+			filePaths := report.SortedKeys(results)
+			rows := make([][]interface{}, 0, len(filePaths))
+			for _, filePath := range filePaths {
+				rows = append(rows, row(filePath, results[filePath]))
+			}
 
-			// Se o usuário desejar gerar o relatório em HTML
-			if utils.OutputFilePath != "" {
-				templates.SaveResultsToHTML(results, totalLinesStr, utils.OutputFilePath, utils.COUNT_COMMENTS, cmd, false, false)
-			} else {
-				printResults(results, totals, cmd)
+			if printErr := report.Print(cmd.OutOrStdout(), report.Report{
+				Title:   string(utils.COMMENT_PERCENTAGE),
+				Columns: columns,
+				Rows:    rows,
+				Summary: summary(totals),
+				Text: func(w io.Writer) {
+					printResults(filePaths, results, totals, w)
+				},
+				Skipped: skipped,
+			}, report.HTML); printErr != nil {
+				return printErr
 			}
+			return err
 		}
+		return nil
 	},
 }
 
+func row(filePath string, result analyzer.PercentResult) []interface{} {
+	return []interface{}{filePath, result.TotalLines, result.CommentLines, result.CommentPercentage}
+}
+
+func summary(result analyzer.PercentResult) []report.Field {
+	return []report.Field{
+		{Key: "total_lines", Title: "Total lines", Value: result.TotalLines},
+		{Key: "comment_lines", Title: "Comment lines", Value: result.CommentLines},
+		{Key: "comment_percentage", Title: "Comment percentage", Value: result.CommentPercentage},
+	}
+}
+
 // Função para exibir os resultados
-func printResults(results analyzer.PercentResultMap, totals analyzer.PercentResult, cmd *cobra.Command) {
+func printResults(filePaths []string, results analyzer.PercentResultMap, totals analyzer.PercentResult, w io.Writer) {
 	// Exibe os resultados dos arquivos individuais
-	for filePath, result := range results {
-		shortName := filepath.Base(filePath)
-		fmt.Fprintf(cmd.OutOrStdout(), "%sComment lines in %s:%s %d (%.2f%%)\n",
-			utils.BLUE, shortName, utils.RESET_COLOR,
+	for _, filePath := range filePaths {
+		result := results[filePath]
+		fmt.Fprintf(w, "%sComment lines in %s:%s %d (%.2f%%)\n",
+			utils.BLUE, filePath, utils.RESET_COLOR,
 			result.CommentLines, result.CommentPercentage)
 	}
 
 	// Exibe os totais consolidados
-	fmt.Fprintf(cmd.OutOrStdout(), "%s\n%sTOTAL RESULTS%s\n",
+	fmt.Fprintf(w, "%s\n%sTOTAL RESULTS%s\n",
 		strings.Repeat("-", 40), utils.GREEN, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "%sTotal Lines:%s %d\n",
+	fmt.Fprintf(w, "%sTotal Lines:%s %d\n",
 		utils.BLUE, utils.RESET_COLOR, totals.TotalLines)
-	fmt.Fprintf(cmd.OutOrStdout(), "%sTotal Comments:%s %d\n",
+	fmt.Fprintf(w, "%sTotal Comments:%s %d\n",
 		utils.BLUE, utils.RESET_COLOR, totals.CommentLines)
-	fmt.Fprintf(cmd.OutOrStdout(), "%sComment Percentage:%s %.2f%%\n",
+	fmt.Fprintf(w, "%sComment Percentage:%s %.2f%%\n",
 		utils.BLUE, utils.RESET_COLOR, totals.CommentPercentage)
 }
 
+// watchDirectory redraws the percentage of comments as the files of the
+// directory change.
+func watchDirectory(cmd *cobra.Command) error {
+	session := &watch.Session[analyzer.PercentResult]{
+		Title:   string(utils.COMMENT_PERCENTAGE),
+		Dir:     utils.DirectoryPath,
+		Analyze: countPercentAnalyzer.CountPercentByFilePath,
+		Summarize: func(results map[string]analyzer.PercentResult) []watch.Metric {
+			var total analyzer.PercentResult
+			for _, result := range results {
+				total.TotalLines += result.TotalLines
+				total.CommentLines += result.CommentLines
+			}
+			if total.TotalLines > 0 {
+				total.CommentPercentage = float64(total.CommentLines) / float64(total.TotalLines) * 100
+			}
+			return []watch.Metric{
+				{Name: "Lines", Value: float64(total.TotalLines)},
+				{Name: "Comment lines", Value: float64(total.CommentLines)},
+				{Name: "Comment %", Value: total.CommentPercentage},
+			}
+		},
+	}
+
+	return session.Watch(cmd.Context(), cmd.OutOrStdout())
+}
+
 func init() {
 	// Inicializa a implementação correta
 	countPercentAnalyzer = &analyzer.CountPercentAnalyzerImpl{}
 
 	CountPercentCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file (must be a single file, not a directory)")
 	CountPercentCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files")
-	CountPercentCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output HTML report file (only for directory analysis)")
+	CountPercentCmd.Flags().StringVar(&utils.ChangedSince, "changed-since", "", "Only analyze the files of the directory changed since the common ancestor of this git ref and HEAD, including uncommitted and untracked files")
+	CountPercentCmd.Flags().BoolVar(&utils.Staged, "staged", false, "Only analyze the files of the directory staged in git")
+	CountPercentCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (HTML by default)")
+	CountPercentCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+	CountPercentCmd.Flags().BoolVar(&utils.Watch, "watch", false, watch.FlagUsage)
 }
@@ -1,71 +1,80 @@
 package dependencies
 
 import (
-	"encoding/json"
 	"fmt"
 	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
-	"os"
 
 	"github.com/spf13/cobra"
 )
 
+var columns = []report.Column{
+    {Key: "file", Title: "File"},
+    {Key: "total_dependencies", Title: "Dependencies"},
+    {Key: "dependencies", Title: "External modules"},
+    {Key: "native_modules", Title: "Native modules"},
+}
+
 var DependenciesAnalyzerCmd = &cobra.Command{
     Use:   "dependencies",
     Short: "Analyze external dependencies in JavaScript files",
-    Run: func(cmd *cobra.Command, args []string) {
+    SilenceUsage:  true,
+    SilenceErrors: true,
+    RunE: func(cmd *cobra.Command, args []string) error {
 
-        if utils.FilePath == "" && utils.DirectoryPath == "" {
-            fmt.Println("Error: You must specify either a file path or a directory path")
-            cmd.Help()
-            return
+        if err := policies.ValidateUserInput(); err != nil {
+            return err
         }
 
-        analyzer := &analyzer.CountDependenciesAnalyzerImpl{}
+        dependenciesAnalyzer := &analyzer.CountDependenciesAnalyzerImpl{}
         var results interface{}
+        var files analyzer.DependenciesMap
         var err error
 
         if utils.FilePath != "" {
-            results, err = analyzer.CountDependenciesByFilePath(utils.FilePath)
+            var file analyzer.DependencyResult
+            file, err = dependenciesAnalyzer.CountDependenciesByFilePath(utils.FilePath)
+            results = file
+            files = analyzer.DependenciesMap{utils.FilePath: file}
         } else {
-            results, err = analyzer.CountDependenciesByDirectory(utils.DirectoryPath)
+            files, err = dependenciesAnalyzer.CountDependenciesByDirectory(utils.DirectoryPath)
+            results = files
         }
 
-        if err != nil {
-            fmt.Printf("Error analyzing dependencies: %v\n", err)
-            return
+        skipped, fatal := analyzer.Skipped(err)
+        if fatal != nil {
+            return fmt.Errorf("error analyzing dependencies: %w", fatal)
         }
 
-        if utils.OutputFilePath != "" {
-            file, err := os.Create(utils.OutputFilePath)
-            if err != nil {
-                fmt.Printf("Error creating output file: %v\n", err)
-                return
-            }
-            defer file.Close()
-
-            encoder := json.NewEncoder(file)
-            encoder.SetIndent("", "  ")
-            if err := encoder.Encode(results); err != nil {
-                fmt.Printf("Error writing JSON to file: %v\n", err)
-                return
-            }
-
-            fmt.Printf("Results written to %s\n", utils.OutputFilePath)
-        } else {
-            jsonData, err := json.MarshalIndent(results, "", "  ")
-            if err != nil {
-                fmt.Printf("Error formatting results: %v\n", err)
-                return
-            }
+        rows := make([][]interface{}, 0, len(files))
+        for _, path := range report.SortedKeys(files) {
+            dependencies := files[path]
+            rows = append(rows, []interface{}{
+                path,
+                dependencies.TotalDependencies,
+                dependencies.Dependencies,
+                dependencies.NativeModules,
+            })
+        }
 
-            fmt.Println(string(jsonData))
+        if printErr := report.Print(cmd.OutOrStdout(), report.Report{
+            Title:   string(utils.DEPENDENCIES),
+            Columns: columns,
+            Rows:    rows,
+            Data:    results,
+            Skipped: skipped,
+        }, report.JSON); printErr != nil {
+            return printErr
         }
+        return err
     },
 }
 
 func init() {
     DependenciesAnalyzerCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file")
     DependenciesAnalyzerCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files")
-    DependenciesAnalyzerCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file")
-}
\ No newline at end of file
+    DependenciesAnalyzerCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (JSON by default)")
+    DependenciesAnalyzerCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+}
@@ -0,0 +1,191 @@
+package diff
+
+import (
+	"fmt"
+	"go-cli-tool/internal/comparison"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/utils"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var DiffCmd = &cobra.Command{
+	Use:   "diff <before.json> <after.json>",
+	Short: "Compare two JSON reports written by analyze",
+	Long: `Compare two JSON reports written by analyze, such as the reports of the
+base and the head of a pull request, and show what changed: the totals and,
+for detailed reports (--detailed), every added, removed or modified file, with
+the change of lines, comments, functions, classes, methods and average
+function size, and the dependencies added or removed.
+
+Use --format markdown to get a comment ready to be posted on the pull request.`,
+	Args:          cobra.ExactArgs(2),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		before, err := comparison.Load(args[0])
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		after, err := comparison.Load(args[1])
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[1], err)
+		}
+
+		return report.Print(cmd.OutOrStdout(), newReport(comparison.Compare(before, after)), report.Markdown)
+	},
+}
+
+// fileMetrics are the metrics shown for each changed file; the methods
+// column adds up the public and private methods.
+var fileMetrics = []string{"lines", "comments", "functions", "classes", "methods", "average_function_size"}
+
+func newReport(result comparison.Result) report.Report {
+	columns := []report.Column{
+		{Key: "file", Title: "File"},
+		{Key: "status", Title: "Status"},
+	}
+	for _, metric := range comparison.ComparedMetrics {
+		columns = append(columns, report.Column{Key: metric.Key, Title: metric.Title})
+	}
+
+	rows := make([][]interface{}, 0, len(result.Files))
+	for _, file := range result.Files {
+		row := []interface{}{file.File, string(file.Status)}
+		for _, delta := range file.Deltas {
+			row = append(row, delta.Change)
+		}
+		rows = append(rows, row)
+	}
+
+	summary := make([]report.Field, 0, len(result.Totals)+2)
+	for i, delta := range result.Totals {
+		metric := comparison.ComparedMetrics[i]
+		summary = append(summary, report.Field{Key: metric.Key, Title: metric.Title, Value: report.FormatChange(delta.Change)})
+	}
+	summary = append(summary,
+		report.Field{Key: "added_dependencies", Title: "Added dependencies", Value: result.AddedDependencies},
+		report.Field{Key: "removed_dependencies", Title: "Removed dependencies", Value: result.RemovedDependencies},
+	)
+
+	return report.Report{
+		Title:    string(utils.DIFF),
+		Columns:  columns,
+		Rows:     rows,
+		Summary:  summary,
+		Data:     result,
+		Text:     func(w io.Writer) { writeText(w, result) },
+		Markdown: func(w io.Writer) error { return writeMarkdown(w, result) },
+	}
+}
+
+// fileChanges returns the changes of a file in the order of fileMetrics.
+func fileChanges(file comparison.FileChange) []string {
+	changes := make(map[string]float64, len(file.Deltas)+1)
+	for _, delta := range file.Deltas {
+		changes[delta.Metric] = delta.Change
+	}
+	changes["methods"] = changes["public_methods"] + changes["private_methods"]
+
+	cells := make([]string, len(fileMetrics))
+	for i, key := range fileMetrics {
+		cells[i] = report.FormatChange(changes[key])
+	}
+	return cells
+}
+
+func writeText(w io.Writer, result comparison.Result) {
+	fmt.Fprintf(w, "%s=== %s ===%s\n", utils.BLUE, utils.DIFF, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Before: %s\nAfter:  %s\n\n", result.Before, result.After)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Metric\tBefore\tAfter\tChange")
+	for i, delta := range result.Totals {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", comparison.ComparedMetrics[i].Title,
+			report.FormatNumber(delta.Before), report.FormatNumber(delta.After), report.FormatChange(delta.Change))
+	}
+	table.Flush()
+
+	fmt.Fprintf(w, "\n%s=== Files ===%s\n", utils.BLUE, utils.RESET_COLOR)
+	switch {
+	case !result.PerFile:
+		fmt.Fprintln(w, "Per-file changes need two reports written with --detailed.")
+	case len(result.Files) == 0:
+		fmt.Fprintln(w, "No file changed.")
+	default:
+		table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "File\tStatus\tLines\tComments\tFunctions\tClasses\tMethods\tAverage function size")
+		for _, file := range result.Files {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", file.File, file.Status, strings.Join(fileChanges(file), "\t"))
+		}
+		table.Flush()
+	}
+
+	fmt.Fprintf(w, "\n%s=== Dependencies ===%s\n", utils.BLUE, utils.RESET_COLOR)
+	if len(result.AddedDependencies)+len(result.RemovedDependencies)+len(result.AddedNativeModules)+len(result.RemovedNativeModules) == 0 {
+		fmt.Fprintln(w, "No dependency added or removed.")
+	}
+	for _, name := range result.AddedDependencies {
+		fmt.Fprintf(w, "%s+ %s%s\n", utils.GREEN, name, utils.RESET_COLOR)
+	}
+	for _, name := range result.AddedNativeModules {
+		fmt.Fprintf(w, "%s+ %s (native)%s\n", utils.GREEN, name, utils.RESET_COLOR)
+	}
+	for _, name := range result.RemovedDependencies {
+		fmt.Fprintf(w, "%s- %s%s\n", utils.RED, name, utils.RESET_COLOR)
+	}
+	for _, name := range result.RemovedNativeModules {
+		fmt.Fprintf(w, "%s- %s (native)%s\n", utils.RED, name, utils.RESET_COLOR)
+	}
+}
+
+func writeMarkdown(w io.Writer, result comparison.Result) error {
+	fmt.Fprintf(w, "## %s\n\n", utils.DIFF)
+	if !result.Changed() {
+		fmt.Fprintln(w, "No metric changed.")
+		return nil
+	}
+
+	fmt.Fprintln(w, "| Metric | Before | After | Change |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: |")
+	for i, delta := range result.Totals {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n", comparison.ComparedMetrics[i].Title,
+			report.FormatNumber(delta.Before), report.FormatNumber(delta.After), report.FormatChange(delta.Change))
+	}
+
+	if len(result.Files) > 0 {
+		fmt.Fprintf(w, "\n<details>\n<summary>%d file(s) changed</summary>\n\n", len(result.Files))
+		fmt.Fprintln(w, "| File | Status | Lines | Comments | Functions | Classes | Methods | Average function size |")
+		fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |")
+		for _, file := range result.Files {
+			fmt.Fprintf(w, "| `%s` | %s | %s |\n", file.File, file.Status, strings.Join(fileChanges(file), " | "))
+		}
+		fmt.Fprintln(w, "\n</details>")
+	}
+
+	var dependencies []string
+	for _, name := range result.AddedDependencies {
+		dependencies = append(dependencies, fmt.Sprintf("- :heavy_plus_sign: `%s`", name))
+	}
+	for _, name := range result.AddedNativeModules {
+		dependencies = append(dependencies, fmt.Sprintf("- :heavy_plus_sign: `%s` (native)", name))
+	}
+	for _, name := range result.RemovedDependencies {
+		dependencies = append(dependencies, fmt.Sprintf("- :heavy_minus_sign: `%s`", name))
+	}
+	for _, name := range result.RemovedNativeModules {
+		dependencies = append(dependencies, fmt.Sprintf("- :heavy_minus_sign: `%s` (native)", name))
+	}
+	if len(dependencies) > 0 {
+		fmt.Fprintf(w, "\n### Dependencies\n\n%s\n", strings.Join(dependencies, "\n"))
+	}
+	return nil
+}
+
+func init() {
+	DiffCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (markdown by default)")
+	DiffCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+}
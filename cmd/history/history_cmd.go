@@ -0,0 +1,310 @@
+package history
+
+import (
+	"fmt"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/history"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/utils"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// timeLayout is how the time of a run is displayed.
+const timeLayout = "2006-01-02 15:04"
+
+var (
+	// limit keeps only the most recent runs.
+	limit int
+	// pathFilter keeps only the runs of an analyzed file or directory.
+	pathFilter string
+	// partial keeps the runs restricted with --changed-since or --staged
+	// in the trend and in the default diff.
+	partial bool
+)
+
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the recorded analyses and how their metrics evolved",
+	Long: `Every analyze run records its summary, with the time and the git commit
+it ran on, in .gocli/history.jsonl next to the project configuration file
+(or at the top of the git repository).
+
+Without a subcommand, history lists the recorded runs. Use "history diff" to
+compare two runs and "history trend" to follow the main metrics from run to
+run.
+
+The runs restricted to the changed files with --changed-since or --staged
+are listed with their scope, and left out of the trend and of the default
+diff unless --partial is given.`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runs, err := loadRuns(true)
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%sNo analysis recorded yet, run analyze first.%s\n", utils.BLUE, utils.RESET_COLOR)
+			return nil
+		}
+
+		return report.Print(cmd.OutOrStdout(), listReport(runs), report.JSON)
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [from] [to]",
+	Short: "Show the change of every metric between two runs",
+	Long: `Show the change of every metric between two recorded runs, given by the
+ids listed by the history command. Without ids the two most recent runs are
+compared; with a single id that run is compared to the most recent one.`,
+	Args:          cobra.MaximumNArgs(2),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// the runs given by id are compared even when they are partial
+		runs, err := loadRuns(partial || len(args) > 0)
+		if err != nil {
+			return err
+		}
+		if len(runs) < 2 {
+			return fmt.Errorf("at least two recorded runs are needed, %d found", len(runs))
+		}
+
+		from, to := runs[len(runs)-2], runs[len(runs)-1]
+		if len(args) > 0 {
+			if from, err = findRun(runs, args[0]); err != nil {
+				return err
+			}
+		}
+		if len(args) > 1 {
+			if to, err = findRun(runs, args[1]); err != nil {
+				return err
+			}
+		}
+
+		return report.Print(cmd.OutOrStdout(), diffReport(from, to), report.JSON)
+	},
+}
+
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Follow lines, comments, functions, complexity and dependencies across runs",
+	Long: `Print a table with a row per recorded run and the value of the lines,
+comments, functions, average complexity and dependencies, each followed by
+its change since the previous run.`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runs, err := loadRuns(partial)
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%sNo analysis recorded yet, run analyze first.%s\n", utils.BLUE, utils.RESET_COLOR)
+			return nil
+		}
+
+		return report.Print(cmd.OutOrStdout(), trendReport(runs), report.JSON)
+	},
+}
+
+// loadRuns reads the history of the project of the working directory and
+// applies the --path and --limit filters. The partial runs are left out
+// unless withPartial is set.
+func loadRuns(withPartial bool) ([]history.Run, error) {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := history.NewStore(config.StateDir(workingDirectory)).Runs()
+	if err != nil {
+		return nil, err
+	}
+
+	if pathFilter != "" {
+		path, err := utils.ExpandPath(pathFilter)
+		if err == nil {
+			path, err = filepath.Abs(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var matching []history.Run
+		for _, run := range runs {
+			if run.Path == path {
+				matching = append(matching, run)
+			}
+		}
+		runs = matching
+	}
+
+	if !withPartial {
+		runs = history.Complete(runs)
+	}
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+	return runs, nil
+}
+
+func findRun(runs []history.Run, arg string) (history.Run, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return history.Run{}, fmt.Errorf("invalid run id %q", arg)
+	}
+	return history.Find(runs, id)
+}
+
+func listReport(runs []history.Run) report.Report {
+	rows := make([][]interface{}, 0, len(runs))
+	for _, run := range runs {
+		rows = append(rows, []interface{}{
+			run.ID,
+			run.Timestamp.Local().Format(timeLayout),
+			run.ShortCommit(),
+			run.Branch,
+			run.Path,
+			run.Scope(),
+			run.Metrics.Files,
+			run.Metrics.Lines,
+			run.Metrics.Functions,
+			run.Metrics.AverageComplexity,
+			run.Metrics.Dependencies,
+		})
+	}
+
+	return report.Report{
+		Title: string(utils.HISTORY),
+		Columns: []report.Column{
+			{Key: "id", Title: "Run"},
+			{Key: "timestamp", Title: "Date"},
+			{Key: "commit", Title: "Commit"},
+			{Key: "branch", Title: "Branch"},
+			{Key: "path", Title: "Path"},
+			{Key: "scope", Title: "Scope"},
+			{Key: "files", Title: "Files"},
+			{Key: "lines", Title: "Lines"},
+			{Key: "functions", Title: "Functions"},
+			{Key: "average_complexity", Title: "Average complexity"},
+			{Key: "dependencies", Title: "Dependencies"},
+		},
+		Rows:    rows,
+		Summary: []report.Field{{Key: "runs", Title: "Runs", Value: len(runs)}},
+		Data:    map[string]interface{}{"runs": runs},
+	}
+}
+
+func diffReport(from, to history.Run) report.Report {
+	deltas := history.Compare(from, to)
+
+	rows := make([][]interface{}, 0, len(deltas))
+	for i, delta := range deltas {
+		rows = append(rows, []interface{}{
+			history.TrackedMetrics[i].Title,
+			report.FormatNumber(delta.From),
+			report.FormatNumber(delta.To),
+			report.FormatChange(delta.Change),
+		})
+	}
+
+	return report.Report{
+		Title: fmt.Sprintf("%s: run %d to run %d", utils.HISTORY, from.ID, to.ID),
+		Columns: []report.Column{
+			{Key: "metric", Title: "Metric"},
+			{Key: "from", Title: runTitle(from)},
+			{Key: "to", Title: runTitle(to)},
+			{Key: "change", Title: "Change"},
+		},
+		Rows: rows,
+		Data: map[string]interface{}{
+			"from":   from,
+			"to":     to,
+			"deltas": deltas,
+		},
+	}
+}
+
+// trendMetrics are the metrics of the trend table.
+var trendMetrics = []string{"lines", "comments", "functions", "average_complexity", "dependencies"}
+
+func trendReport(runs []history.Run) report.Report {
+	columns := []report.Column{
+		{Key: "id", Title: "Run"},
+		{Key: "timestamp", Title: "Date"},
+		{Key: "commit", Title: "Commit"},
+	}
+	metrics := make([]history.Metric, 0, len(trendMetrics))
+	for _, metric := range history.TrackedMetrics {
+		for _, key := range trendMetrics {
+			if metric.Key == key {
+				metrics = append(metrics, metric)
+				columns = append(columns, report.Column{Key: metric.Key, Title: metric.Title})
+			}
+		}
+	}
+
+	rows := make([][]interface{}, 0, len(runs))
+	data := make([]map[string]interface{}, 0, len(runs))
+	for i, run := range runs {
+		row := []interface{}{run.ID, run.Timestamp.Local().Format(timeLayout), run.ShortCommit()}
+		values := make(map[string]interface{}, len(metrics))
+		changes := make(map[string]interface{}, len(metrics))
+
+		for _, metric := range metrics {
+			value := metric.Value(run.Metrics)
+			cell := report.FormatNumber(value)
+			values[metric.Key] = value
+			if i > 0 {
+				change := value - metric.Value(runs[i-1].Metrics)
+				cell += " (" + report.FormatChange(change) + ")"
+				changes[metric.Key] = change
+			}
+			row = append(row, cell)
+		}
+
+		rows = append(rows, row)
+		data = append(data, map[string]interface{}{
+			"id":        run.ID,
+			"timestamp": run.Timestamp,
+			"commit":    run.Commit,
+			"values":    values,
+			"changes":   changes,
+		})
+	}
+
+	return report.Report{
+		Title:   fmt.Sprintf("%s trend", utils.HISTORY),
+		Columns: columns,
+		Rows:    rows,
+		Data:    map[string]interface{}{"runs": data},
+	}
+}
+
+func runTitle(run history.Run) string {
+	title := "Run " + strconv.Itoa(run.ID)
+	if commit := run.ShortCommit(); commit != "" {
+		title += " (" + commit + ")"
+	}
+	return title
+}
+
+func init() {
+	HistoryCmd.PersistentFlags().IntVarP(&limit, "limit", "n", 0, "Only use the n most recent runs")
+	HistoryCmd.PersistentFlags().StringVar(&pathFilter, "path", "", "Only use the runs of this analyzed file or directory")
+	HistoryCmd.PersistentFlags().BoolVar(&partial, "partial", false, "Also use the runs restricted with --changed-since or --staged")
+	HistoryCmd.PersistentFlags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (JSON by default)")
+	HistoryCmd.PersistentFlags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+
+	HistoryCmd.AddCommand(diffCmd)
+	HistoryCmd.AddCommand(trendCmd)
+}
@@ -0,0 +1,131 @@
+package hotspots
+
+import (
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/git"
+	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/utils"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var hotspotAnalyzer analyzer.HotspotAnalyzer
+
+var since string
+var top int
+
+var columns = []report.Column{
+	{Key: "file", Title: "File"},
+	{Key: "score", Title: "Score"},
+	{Key: "commits", Title: "Commits"},
+	{Key: "lines_added", Title: "Lines added"},
+	{Key: "lines_removed", Title: "Lines removed"},
+	{Key: "authors", Title: "Authors"},
+	{Key: "lines", Title: "Lines"},
+	{Key: "complexity", Title: "Complexity"},
+	{Key: "comment_percentage", Title: "Comment percentage"},
+}
+
+var HotspotsCmd = &cobra.Command{
+	Use:   "hotspots",
+	Short: "Rank the files that change often and are hard to change",
+	Long: `Rank the hotspots of a directory: the files that changed the most in the
+git history over a period and are large, complex and poorly commented, where
+a bug is the most likely to be introduced next.
+
+The churn of each file (commits, lines added and removed, distinct authors)
+comes from the local git log. The score of a file is its number of commits
+times its cyclomatic complexity, doubled for a file without comments and
+decreasing to no penalty at 20% of comment lines.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if utils.DirectoryPath == "" {
+			utils.DirectoryPath = "."
+		}
+		if !policies.ValidateDirectoryPath(utils.DirectoryPath) {
+			return fmt.Errorf("please provide a valid directory path")
+		}
+		directoryPath, err := utils.ExpandPath(utils.DirectoryPath)
+		if err != nil {
+			return err
+		}
+
+		churn, err := git.Churn(directoryPath, since)
+		if err != nil {
+			return err
+		}
+
+		hotspots, err := hotspotAnalyzer.HotspotsByDirectory(directoryPath, churn)
+		skipped, fatal := analyzer.Skipped(err)
+		if fatal != nil {
+			return fatal
+		}
+		if top > 0 && len(hotspots) > top {
+			hotspots = hotspots[:top]
+		}
+
+		hotspotsReport := newReport(hotspots)
+		hotspotsReport.Skipped = skipped
+		if printErr := report.Print(cmd.OutOrStdout(), hotspotsReport, report.HTML); printErr != nil {
+			return printErr
+		}
+		return err
+	},
+}
+
+func newReport(hotspots []analyzer.Hotspot) report.Report {
+	rows := make([][]interface{}, 0, len(hotspots))
+	for _, hotspot := range hotspots {
+		rows = append(rows, []interface{}{hotspot.File, hotspot.Score, hotspot.Commits, hotspot.LinesAdded,
+			hotspot.LinesRemoved, hotspot.Authors, hotspot.Lines, hotspot.Complexity, hotspot.CommentPercentage})
+	}
+
+	return report.Report{
+		Title:   string(utils.HOTSPOTS),
+		Columns: columns,
+		Rows:    rows,
+		Summary: []report.Field{
+			{Key: "since", Title: "Since", Value: window()},
+			{Key: "hotspots", Title: "Hotspots", Value: len(hotspots)},
+		},
+		Text: func(w io.Writer) { writeText(w, hotspots) },
+	}
+}
+
+// window describes the analyzed period of the history.
+func window() string {
+	if since == "" {
+		return "the first commit"
+	}
+	return since
+}
+
+func writeText(w io.Writer, hotspots []analyzer.Hotspot) {
+	fmt.Fprintf(w, "%s=== %s since %s ===%s\n", utils.BLUE, utils.HOTSPOTS, window(), utils.RESET_COLOR)
+	if len(hotspots) == 0 {
+		fmt.Fprintln(w, "No JavaScript file changed in this period.")
+		return
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "#\tFile\tScore\tCommits\t+/-\tAuthors\tLines\tComplexity\tComments")
+	for i, hotspot := range hotspots {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%d\t+%d/-%d\t%d\t%d\t%d\t%.2f%%\n", i+1, hotspot.File, report.FormatNumber(hotspot.Score),
+			hotspot.Commits, hotspot.LinesAdded, hotspot.LinesRemoved, hotspot.Authors, hotspot.Lines, hotspot.Complexity, hotspot.CommentPercentage)
+	}
+	table.Flush()
+}
+
+func init() {
+	hotspotAnalyzer = &analyzer.HotspotAnalyzerImpl{}
+	HotspotsCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files, inside a git repository (the current directory by default)")
+	HotspotsCmd.Flags().StringVar(&since, "since", "90 days ago", "Start of the analyzed period, in any format git log --since accepts such as \"6 months ago\" or 2024-01-31; empty for the whole history")
+	HotspotsCmd.Flags().IntVarP(&top, "top", "t", 10, "Number of hotspots to list, 0 for all")
+	HotspotsCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (HTML by default)")
+	HotspotsCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+}
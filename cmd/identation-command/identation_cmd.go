@@ -1,64 +1,74 @@
 package identation
 
 import (
-	"encoding/json"
 	"fmt"
 	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
 	"go-cli-tool/internal/utils"
-	"os"
 
 	"github.com/spf13/cobra"
 )
 
+var columns = []report.Column{
+    {Key: "file", Title: "File"},
+    {Key: "max_indent_level", Title: "Max indent"},
+    {Key: "average_indent_level", Title: "Average indent"},
+    {Key: "uses_spaces", Title: "Spaces"},
+    {Key: "uses_tabs", Title: "Tabs"},
+    {Key: "mixed_indentation", Title: "Mixed"},
+    {Key: "max_nesting_depth", Title: "Max nesting"},
+    {Key: "max_cognitive_complexity", Title: "Max cognitive complexity"},
+}
+
 var IdentationAnalyzerCmd = &cobra.Command{
     Use:   "identation",
     Short: "Check identation in JavaScript files",
-    Run: func(cmd *cobra.Command, args []string) {
-        if utils.FilePath == "" && utils.DirectoryPath == "" {
-            fmt.Println("Error: You must specify either a file path or a directory path")
-            cmd.Help()
-            return
+    SilenceUsage:  true,
+    SilenceErrors: true,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if err := policies.ValidateUserInput(); err != nil {
+            return err
         }
         
         identationAnalyzer := &analyzer.IdentationAnalyzerImpl{}
         results, err := identationAnalyzer.IdentationByFilePath()
-        if err != nil {
-            fmt.Printf("Error analyzing indentation: %v\n", err)
-            return
+        skipped, fatal := analyzer.Skipped(err)
+        if fatal != nil {
+            return fmt.Errorf("error analyzing indentation: %w", fatal)
         }
-        
-        if utils.OutputFilePath != "" {
-
-            file, err := os.Create(utils.OutputFilePath)
-            if err != nil {
-                fmt.Printf("Error creating output file: %v\n", err)
-                return
-            }
-            defer file.Close()
 
-            encoder := json.NewEncoder(file)
-            encoder.SetIndent("", "  ")
-            if err := encoder.Encode(results); err != nil {
-                fmt.Printf("Error writing JSON to file: %v\n", err)
-                return
-            }
-
-            fmt.Printf("Results written to %s\n", utils.OutputFilePath)
-        } else {
+        rows := make([][]interface{}, 0, len(results.Files))
+        for _, file := range results.Files {
+            stats := file.Stats
+            rows = append(rows, []interface{}{
+                file.Path,
+                stats.MaxIndentLevel,
+                stats.AverageIndentLevel,
+                stats.UsesSpaces,
+                stats.UsesTabs,
+                stats.MixedIndentation,
+                stats.MaxNestingDepth,
+                stats.MaxCognitiveComplexity,
+            })
+        }
 
-            jsonData, err := json.MarshalIndent(results, "", "  ")
-            if err != nil {
-                fmt.Printf("Error formatting results: %v\n", err)
-                return
-            }
-            
-            fmt.Println(string(jsonData))
+        if printErr := report.Print(cmd.OutOrStdout(), report.Report{
+            Title:   string(utils.INDENTATION),
+            Columns: columns,
+            Rows:    rows,
+            Data:    results,
+            Skipped: skipped,
+        }, report.JSON); printErr != nil {
+            return printErr
         }
+        return err
     },
 }
 
 func init() {
     IdentationAnalyzerCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file (must be a single file, not a directory)")
     IdentationAnalyzerCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files. The tool will automatically expand the provided path.")
-    IdentationAnalyzerCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file. The expected output is a json file.")
-}
\ No newline at end of file
+    IdentationAnalyzerCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into. JSON is used when the extension does not name a format.")
+    IdentationAnalyzerCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+}
@@ -0,0 +1,141 @@
+package ownership
+
+import (
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/git"
+	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/utils"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var ownershipAnalyzer analyzer.OwnershipAnalyzer
+
+var columns = []report.Column{
+	{Key: "directory", Title: "Directory"},
+	{Key: "author", Title: "Author"},
+	{Key: "lines", Title: "Lines"},
+	{Key: "functions", Title: "Functions"},
+	{Key: "comment_lines", Title: "Comment lines"},
+	{Key: "comment_density", Title: "Comment density"},
+}
+
+var OwnershipCmd = &cobra.Command{
+	Use:   "ownership",
+	Short: "Attribute lines, functions and comments to their authors with git blame",
+	Long: `Attribute the code to its owners using git blame on the local repository:
+for each author, the non-blank lines they last changed, the functions whose
+declaration they wrote and the density of comments in their lines, for the
+whole analysis and rolled up per directory.
+
+Lines not committed yet are attributed to "Not Committed Yet". The teams
+assigned by the CODEOWNERS file are shown next to each file in the detailed
+report of analyze (--detailed).`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := policies.ValidateUserInput(); err != nil {
+			return err
+		}
+
+		dir := utils.DirectoryPath
+		if utils.FilePath != "" {
+			dir = filepath.Dir(utils.FilePath)
+		} else if !policies.ValidateDirectoryPath(dir) {
+			return fmt.Errorf("please provide a valid directory path")
+		}
+		if _, err := git.TopLevel(dir); err != nil {
+			return fmt.Errorf("ownership needs a git repository: %w", err)
+		}
+
+		if utils.FilePath != "" {
+			owners, err := ownershipAnalyzer.OwnershipByFilePath(utils.FilePath)
+			if err != nil {
+				return err
+			}
+			result := analyzer.OwnershipResult{
+				Files:       analyzer.OwnershipMap{utils.FilePath: owners},
+				Directories: analyzer.OwnershipMap{".": owners},
+			}
+			return report.Print(cmd.OutOrStdout(), newReport(result), report.HTML)
+		}
+
+		result, err := ownershipAnalyzer.OwnershipByDirectory(utils.DirectoryPath)
+		skipped, fatal := analyzer.Skipped(err)
+		if fatal != nil {
+			return fatal
+		}
+		if len(result.Files) == 0 && len(skipped) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%sNo JavaScript files found in the provided directory.%s\n", utils.RED, utils.RESET_COLOR)
+			return nil
+		}
+
+		ownershipReport := newReport(result)
+		ownershipReport.Skipped = skipped
+		if printErr := report.Print(cmd.OutOrStdout(), ownershipReport, report.HTML); printErr != nil {
+			return printErr
+		}
+		return err
+	},
+}
+
+func newReport(result analyzer.OwnershipResult) report.Report {
+	directories := report.SortedKeys(result.Directories)
+
+	rows := [][]interface{}{}
+	for _, directory := range directories {
+		for _, owner := range result.Directories[directory] {
+			rows = append(rows, []interface{}{directory, owner.Author, owner.Lines, owner.Functions, owner.CommentLines, owner.CommentDensity})
+		}
+	}
+
+	return report.Report{
+		Title:   string(utils.OWNERSHIP),
+		Columns: columns,
+		Rows:    rows,
+		Summary: []report.Field{
+			{Key: "authors", Title: "Authors", Value: len(result.Directories["."])},
+			{Key: "files", Title: "Files", Value: len(result.Files)},
+		},
+		Data: result,
+		Text: func(w io.Writer) { writeText(w, directories, result) },
+	}
+}
+
+func writeText(w io.Writer, directories []string, result analyzer.OwnershipResult) {
+	fmt.Fprintf(w, "%s=== Authors ===%s\n", utils.BLUE, utils.RESET_COLOR)
+	writeOwners(w, result.Directories["."])
+
+	if len(directories) < 2 {
+		return
+	}
+	for _, directory := range directories {
+		if directory == "." {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s=== %s ===%s\n", utils.BLUE, directory, utils.RESET_COLOR)
+		writeOwners(w, result.Directories[directory])
+	}
+}
+
+func writeOwners(w io.Writer, owners []analyzer.AuthorOwnership) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Author\tLines\tFunctions\tComment lines\tComment density")
+	for _, owner := range owners {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%.2f%%\n", owner.Author, owner.Lines, owner.Functions, owner.CommentLines, owner.CommentDensity)
+	}
+	table.Flush()
+}
+
+func init() {
+	ownershipAnalyzer = &analyzer.OwnershipAnalyzerImpl{}
+	OwnershipCmd.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file")
+	OwnershipCmd.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files, inside a git repository")
+	OwnershipCmd.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Path to the output file, or a directory to write report.<format> into (HTML by default)")
+	OwnershipCmd.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage)
+}
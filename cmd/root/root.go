@@ -2,6 +2,10 @@ package root
 
 import (
 	"fmt"
+	cache_command "go-cli-tool/cmd/cache-command"
+	"go-cli-tool/cmd/check"
+	"go-cli-tool/cmd/complexity"
+	config_command "go-cli-tool/cmd/config-command"
 	count_average_function_size "go-cli-tool/cmd/count-average-function"
 	count_class_and_functions "go-cli-tool/cmd/count-class-and-functions"
 	count_comments "go-cli-tool/cmd/count-comments"
@@ -9,10 +13,19 @@ import (
 	count_methods "go-cli-tool/cmd/count-methods"
 	count_percent "go-cli-tool/cmd/count-percent-lines"
 	dependencies "go-cli-tool/cmd/dependencies"
+	"go-cli-tool/cmd/diff"
+	history "go-cli-tool/cmd/history"
+	"go-cli-tool/cmd/hotspots"
 	identation "go-cli-tool/cmd/identation-command"
+	"go-cli-tool/cmd/ownership"
 	run_all_commands "go-cli-tool/cmd/run-all-commands"
 	send_metrics "go-cli-tool/cmd/send-metrics"
 	"go-cli-tool/cmd/version"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/git"
+	"go-cli-tool/internal/utils"
+	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
@@ -40,16 +53,96 @@ Simplify your code analysis. Use go-cli-tool!`,
 		"\033[32m", "\033[0m", // Indentation analysis
 		"\033[32m", "\033[0m", // Average function size
 	),
+	PersistentPreRunE: loadConfig,
+}
+
+// loadConfig makes the project configuration found from the working
+// directory upward the active one. Flags given on the command line take
+// precedence over the settings of the configuration.
+func loadConfig(cmd *cobra.Command, args []string) error {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	settings, _, err := config.Discover(workingDirectory)
+	if err != nil {
+		return err
+	}
+	config.Active = settings
+	config.Active.Jobs = utils.Jobs
+
+	flags := cmd.Flags()
+	if flags.Changed("include") {
+		config.Active.Include = utils.Include
+	}
+	if flags.Changed("exclude") {
+		// the globs of the file are replaced, the default exclusions are kept
+		config.Active.Exclude = append(slices.Clone(config.DefaultExclude), utils.Exclude...)
+	}
+	if flags.Changed("ext") {
+		config.Active.Extensions = config.NormalizeExtensions(utils.Extensions)
+	}
+	if flags.Changed("tab-width") {
+		if utils.TabWidth <= 0 {
+			return fmt.Errorf("--tab-width must be a positive number, got %d", utils.TabWidth)
+		}
+		config.Active.TabWidth = utils.TabWidth
+	}
+
+	if utils.ChangedSince != "" || utils.Staged {
+		files, err := changedFiles()
+		if err != nil {
+			return err
+		}
+		config.Active.Files = files
+	}
+
+	if flag := flags.Lookup("output"); flag != nil && !flag.Changed && settings.Output.Path != "" {
+		flags.Set("output", settings.Output.Path)
+	}
+	if flag := flags.Lookup("detailed"); flag != nil && !flag.Changed && settings.Output.Detailed {
+		flags.Set("detailed", "true")
+	}
+
+	return nil
+}
+
+// changedFiles resolves the files selected by --changed-since or --staged
+// in the git repository of the analyzed directory.
+func changedFiles() ([]string, error) {
+	if utils.ChangedSince != "" && utils.Staged {
+		return nil, fmt.Errorf("--changed-since and --staged cannot be used together")
+	}
+
+	directory := utils.DirectoryPath
+	if directory == "" {
+		directory = "."
+	}
+	directory, err := utils.ExpandPath(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.Staged {
+		return git.StagedFiles(directory)
+	}
+	return git.ChangedFiles(directory, utils.ChangedSince)
 }
 
 func RootCommand() {
 	if err := RootCmd.Execute(); err != nil {
-		fmt.Fprintf(RootCmd.OutOrStdout(), "%v\n", err)
-		return
+		fmt.Fprintf(RootCmd.OutOrStderr(), "%v\n", err)
+		os.Exit(1)
 	}
 }
 
 func init() {
+	RootCmd.PersistentFlags().IntVarP(&utils.Jobs, "jobs", "j", 0, "Number of files analyzed in parallel (one per CPU by default)")
+	RootCmd.PersistentFlags().StringSliceVar(&utils.Include, "include", nil, "Analyze only the files matching these globs (overrides include of the configuration file)")
+	RootCmd.PersistentFlags().StringSliceVar(&utils.Exclude, "exclude", nil, "Skip the files and directories matching these globs (overrides exclude of the configuration file)")
+	RootCmd.PersistentFlags().StringSliceVar(&utils.Extensions, "ext", nil, "Analyze only the files with these extensions, e.g. js,ts (overrides extensions of the configuration file)")
+	RootCmd.PersistentFlags().IntVar(&utils.TabWidth, "tab-width", 0, "Number of spaces a tab counts for in the indentation analysis (overrides tabWidth of the configuration file)")
 	RootCmd.AddCommand(count_methods.CountMethodsAnalyzer)
 	RootCmd.AddCommand(count_lines.CountLinesAnalyzer)
 	RootCmd.AddCommand(count_comments.CountCommentsCmd)
@@ -61,4 +154,12 @@ func init() {
 	RootCmd.AddCommand(version.VersionCommand())
 	RootCmd.AddCommand(count_average_function_size.CountAverageFunctionSizeCmd)
 	RootCmd.AddCommand(send_metrics.SendMetricsCmd)
+	RootCmd.AddCommand(complexity.ComplexityCmd)
+	RootCmd.AddCommand(config_command.ConfigCmd)
+	RootCmd.AddCommand(check.CheckCmd)
+	RootCmd.AddCommand(history.HistoryCmd)
+	RootCmd.AddCommand(diff.DiffCmd)
+	RootCmd.AddCommand(hotspots.HotspotsCmd)
+	RootCmd.AddCommand(ownership.OwnershipCmd)
+	RootCmd.AddCommand(cache_command.CacheCmd)
 }
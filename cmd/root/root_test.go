@@ -3,6 +3,11 @@ package root_test
 import (
 	"bytes"
 	"go-cli-tool/cmd/root"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/tests"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -62,4 +67,51 @@ func TestRootCommandWithError(t *testing.T) {
         t.Logf("Actual Output: %q", actualOutput)
         t.Logf("Expected Output: %q", expectedOutput)
     }
-}
\ No newline at end of file
+}
+func TestRootCommandFlagsOverrideConfigFile(t *testing.T) {
+	tests.ResetGlobals()
+
+	// a configuration file in the working directory
+	dir := t.TempDir()
+	content := "tabWidth: 2\nextensions: [js]\ninclude: [\"lib/**\"]\nexclude: [fixtures]\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gocli.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(workingDirectory)
+
+	rootCmd := root.RootCmd
+	var stdout bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stdout)
+	rootCmd.SetArgs([]string{"config", "show", "--tab-width", "8", "--ext", "TS", "--include", "src/**", "--exclude", "generated"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("RootCommand() error = %v, want nil", err)
+	}
+
+	// the flags beat the values of the file
+	settings := config.Active
+	if settings.TabWidth != 8 {
+		t.Errorf("TabWidth = %d, want 8", settings.TabWidth)
+	}
+	if !slices.Equal(settings.Extensions, []string{".ts"}) {
+		t.Errorf("Extensions = %v, want [.ts]", settings.Extensions)
+	}
+	if !slices.Equal(settings.Include, []string{"src/**"}) {
+		t.Errorf("Include = %v, want [src/**]", settings.Include)
+	}
+	wantExclude := append(slices.Clone(config.DefaultExclude), "generated")
+	if !slices.Equal(settings.Exclude, wantExclude) {
+		t.Errorf("Exclude = %v, want %v", settings.Exclude, wantExclude)
+	}
+	if !strings.Contains(stdout.String(), "tabWidth: 8") {
+		t.Errorf("config show printed %q, want the overridden tabWidth", stdout.String())
+	}
+}
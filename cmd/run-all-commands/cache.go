@@ -0,0 +1,32 @@
+package run_all_commands
+
+import (
+	"fmt"
+	"go-cli-tool/cmd/version"
+	"go-cli-tool/internal/cache"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/utils"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// noCache disables the cache of the results of the files.
+var noCache bool
+
+// openCache returns the cache of the project, keyed by the version of the
+// tool and the configuration in effect, or nil when it is disabled. Without
+// a cache the files are all analyzed, so failing to open it is reported
+// without failing the analysis.
+func openCache(cmd *cobra.Command) *cache.Cache {
+	if noCache {
+		return nil
+	}
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%sWarning: the cache is disabled: %s%s\n", utils.RED, err, utils.RESET_COLOR)
+		return nil
+	}
+	return cache.New(config.StateDir(workingDirectory), version.Version, config.Active)
+}
@@ -0,0 +1,171 @@
+package run_all_commands
+
+import (
+	"fmt"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/results"
+	"go-cli-tool/internal/utils"
+	"go-cli-tool/templates"
+	"sort"
+	"strconv"
+)
+
+// functionSizeBuckets are the upper bounds, in lines, of the bars of the
+// function size histogram; the last bar holds the longer functions.
+var functionSizeBuckets = []int{5, 10, 20, 50, 100}
+
+// newDashboard returns the HTML dashboard of the analysis, with a drill-down
+// for each analyzed file.
+func newDashboard(params AnalysisParams, summary []report.Field) templates.Dashboard {
+	dashboard := templates.Dashboard{Title: string(utils.ANALYSIS), Skipped: report.SkippedItems(params.Skipped)}
+	for _, field := range summary {
+		dashboard.Summary = append(dashboard.Summary, templates.SummaryItem{Title: field.Title, Value: report.FormatValue(field.Value)})
+	}
+
+	dashboard.Path = params.FilePath
+	if dashboard.Path == "" {
+		dashboard.Path = params.DirectoryPath
+	}
+
+	for i, file := range params.Report.Files {
+		dashboardFile := newDashboardFile(file)
+		dashboardFile.ID = "file-" + strconv.Itoa(i+1)
+		dashboard.Files = append(dashboard.Files, dashboardFile)
+	}
+
+	dashboard.IndentLevels = indentLevelBars(params.Report.Files)
+	dashboard.FunctionSizes = functionSizeBars(dashboard.Files)
+	dashboard.Dependencies = dashboardDependencies(dashboard.Files)
+	return dashboard
+}
+
+// newDashboardFile returns the metrics and the functions of a file of the
+// report.
+func newDashboardFile(file results.File) templates.DashboardFile {
+	dashboardFile := templates.DashboardFile{
+		Name:                   file.Path,
+		Lines:                  file.Lines,
+		Comments:               file.CommentLines,
+		CommentPercentage:      file.CommentPercentage,
+		Classes:                file.Classes,
+		FunctionCount:          file.FunctionCount,
+		PublicMethods:          file.PublicMethods,
+		PrivateMethods:         file.PrivateMethods,
+		AverageComplexity:      file.AverageComplexity,
+		MaxComplexity:          file.MaxComplexity,
+		MaxNestingDepth:        file.Indentation.MaxNestingDepth,
+		MaxCognitiveComplexity: file.Indentation.MaxCognitiveComplexity,
+		Maintainability:        file.MaintainabilityIndex,
+		Dependencies:           file.Dependencies,
+		NativeModules:          file.NativeModules,
+	}
+
+	switch {
+	case file.Indentation.Mixed:
+		dashboardFile.Indentation = "mixed"
+	case file.Indentation.UsesTabs:
+		dashboardFile.Indentation = "tabs"
+	case file.Indentation.UsesSpaces:
+		dashboardFile.Indentation = "spaces"
+	}
+
+	for _, function := range file.Functions {
+		dashboardFile.Functions = append(dashboardFile.Functions, templates.DashboardFunction{
+			Name:                function.Name,
+			StartLine:           function.StartLine,
+			Lines:               function.EndLine - function.StartLine + 1,
+			Params:              function.Params,
+			Complexity:          function.Complexity,
+			NestingDepth:        function.NestingDepth,
+			CognitiveComplexity: function.CognitiveComplexity,
+			Volume:              function.Volume,
+		})
+	}
+	return dashboardFile
+}
+
+// indentLevelBars sums the indentation distribution of the analyzed files.
+func indentLevelBars(files []results.File) []templates.Bar {
+	counts := make(map[int]int)
+	for _, file := range files {
+		for _, level := range file.Indentation.Distribution {
+			counts[level.Level] += level.Count
+		}
+	}
+
+	levels := make([]int, 0, len(counts))
+	for level := range counts {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	labels := make([]string, len(levels))
+	values := make([]int, len(levels))
+	for i, level := range levels {
+		labels[i] = strconv.Itoa(level)
+		values[i] = counts[level]
+	}
+	return templates.NewBars(labels, values)
+}
+
+// functionSizeBars is the histogram of the function lengths, empty when no
+// function was found.
+func functionSizeBars(files []templates.DashboardFile) []templates.Bar {
+	labels := make([]string, 0, len(functionSizeBuckets)+1)
+	lower := 1
+	for _, upper := range functionSizeBuckets {
+		labels = append(labels, fmt.Sprintf("%d-%d", lower, upper))
+		lower = upper + 1
+	}
+	labels = append(labels, fmt.Sprintf(">%d", functionSizeBuckets[len(functionSizeBuckets)-1]))
+
+	counts := make([]int, len(labels))
+	total := 0
+	for _, file := range files {
+		for _, function := range file.Functions {
+			bucket := sort.SearchInts(functionSizeBuckets, function.Lines)
+			counts[bucket]++
+			total++
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+	return templates.NewBars(labels, counts)
+}
+
+// dashboardDependencies lists the imported modules with the files importing
+// them, external modules first.
+func dashboardDependencies(files []templates.DashboardFile) []templates.Dependency {
+	byName := make(map[string]*templates.Dependency)
+	add := func(name string, native bool, file string) {
+		dependency, ok := byName[name]
+		if !ok {
+			dependency = &templates.Dependency{Name: name, Native: native}
+			byName[name] = dependency
+		}
+		dependency.Files = append(dependency.Files, file)
+	}
+
+	for _, file := range files {
+		for _, name := range file.Dependencies {
+			add(name, false, file.Name)
+		}
+		for _, name := range file.NativeModules {
+			add(name, true, file.Name)
+		}
+	}
+
+	dependencies := make([]templates.Dependency, 0, len(byName))
+	for _, dependency := range byName {
+		dependencies = append(dependencies, *dependency)
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Native != dependencies[j].Native {
+			return !dependencies[i].Native
+		}
+		return dependencies[i].Name < dependencies[j].Name
+	})
+	return dependencies
+}
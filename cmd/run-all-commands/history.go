@@ -0,0 +1,87 @@
+package run_all_commands
+
+import (
+	"fmt"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/git"
+	"go-cli-tool/internal/history"
+	"go-cli-tool/internal/utils"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// noHistory disables the recording of the analysis in the history.
+var noHistory bool
+
+// recordHistory appends the summary of the analysis to the history of the
+// project, with the git commit it ran on. Failing to record it is reported
+// without failing the analysis.
+func recordHistory(cmd *cobra.Command, params AnalysisParams) {
+	if noHistory {
+		return
+	}
+
+	run, err := newHistoryRun(params)
+	if err == nil {
+		var workingDirectory string
+		workingDirectory, err = os.Getwd()
+		if err == nil {
+			_, err = history.NewStore(config.StateDir(workingDirectory)).Append(run)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%sWarning: the analysis was not recorded in the history: %s%s\n", utils.RED, err, utils.RESET_COLOR)
+	}
+}
+
+func newHistoryRun(params AnalysisParams) (history.Run, error) {
+	path := params.FilePath
+	if path == "" {
+		path = params.DirectoryPath
+	}
+	path, err := utils.ExpandPath(path)
+	if err == nil {
+		path, err = filepath.Abs(path)
+	}
+	if err != nil {
+		return history.Run{}, err
+	}
+
+	directory := path
+	if params.FilePath != "" {
+		directory = filepath.Dir(path)
+	}
+
+	run := history.Run{
+		Timestamp:    time.Now().UTC(),
+		Path:         path,
+		Metrics:      historyMetrics(params),
+		ChangedSince: utils.ChangedSince,
+		Staged:       utils.Staged,
+	}
+	// outside of a repository the run is recorded without a commit
+	run.Commit, _ = git.Head(directory)
+	run.Branch, _ = git.Branch(directory)
+	return run, nil
+}
+
+func historyMetrics(params AnalysisParams) history.Metrics {
+	totals := params.Report.Totals
+	return history.Metrics{
+		Files:               totals.Files,
+		Lines:               totals.Lines,
+		Comments:            totals.CommentLines,
+		CommentPercentage:   totals.CommentPercentage,
+		Classes:             totals.Classes,
+		Functions:           totals.Functions,
+		AverageFunctionSize: totals.AverageFunctionSize,
+		AverageComplexity:   totals.AverageComplexity,
+		MaxComplexity:       totals.MaxComplexity,
+		Maintainability:     totals.MaintainabilityIndex,
+		Dependencies:        len(totals.Dependencies),
+	}
+}
@@ -1,36 +1,37 @@
 package run_all_commands
 
 import (
-	"encoding/json"
 	"fmt"
+	"go-cli-tool/cmd/version"
 	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/results"
+	"go-cli-tool/internal/sarif"
 	"go-cli-tool/internal/utils"
+	"go-cli-tool/internal/watch"
+	"go-cli-tool/templates"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+// AnalysisParams holds an analysis of a file or a directory, as the
+// outputs of the command read it.
 type AnalysisParams struct {
-	FilePath              string
-	DirectoryPath         string
-	OutputFilePath        string
-	Detailed              bool
-	LineCount             int64
-	CommentCount          int
-	Classes               int
-	Functions             int
-	CommentPercentage     float64
-	AverageFunctionSize   float64
-	OverallAverageSize    float64
-	IndentResults         map[string]interface{}
-	DependenciesResults   map[string]interface{}
-	MethodCountResult     analyzer.MethodCountResult
-	TotalMethodCount      analyzer.MethodCountResult
-	LineResults           analyzer.FilesNameCountLineMap
-	CommentResults        analyzer.CommentsMap
-	ClassFuncResults      analyzer.ClassesAndFunctionsMap
-	PercentResults        analyzer.PercentResult
-	MethodCountResults    analyzer.MethodCountMap
+	FilePath       string
+	DirectoryPath  string
+	OutputFilePath string
+	Detailed       bool
+	Report         results.Report
+	// Skipped lists the files of the directory that could not be read,
+	// with their path as the walk found them.
+	Skipped []analyzer.SkippedFile
 }
 
 var RunAllCommand = &cobra.Command{
@@ -46,408 +47,440 @@ This command performs multiple analyses simultaneously including:
 - Code Comment Percentage Analysis
 - Method count analysis (public/private)
 - Average Function Size Analysis
+- Cyclomatic complexity analysis
+- Halstead metrics and maintainability index (0-100 per file)
 - Dependency analysis
 
-Results are presented in terminal or json output, providing a complete overview
-of your JavaScript codebase. Use flags to customize the analysis and output format.`,
-	Run: func(cmd *cobra.Command, args []string) {
+Results are presented in the terminal or written with -o, in any of the formats
+selected with --format (text, json, csv, markdown, html or yaml), providing a
+complete overview of your JavaScript codebase. The html format is a self-contained
+dashboard with charts, sortable per-file tables and a drill-down into each file.
+The json and yaml formats write the versioned report described by the JSON Schema
+in pkg/analysis/report.schema.json.
+
+With --format sarif the findings (mixed indentation, oversized functions, low
+comment density and banned dependencies) are written as a SARIF 2.1.0 log for
+code-scanning platforms.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if utils.FilePath == "" && utils.DirectoryPath == "" {
-			fmt.Fprintf(cmd.OutOrStderr(), "%sError: You must provide either a file path (-f) or directory path (-d).%s\n",
-				utils.RED, utils.RESET_COLOR)
-			return
+			return fmt.Errorf("you must provide either a file path (-f) or directory path (-d)")
 		}
 
-		lineAnalyzer, commentAnalyzer, classFuncAnalyzer, indentationAnalyzer, dependenciesAnalyzer, methodCountAnalyzer, averageFunctionAnalyzer := initializeAnalyzers()
-		percentAnalyzer := &analyzer.CountPercentAnalyzerImpl{}
+		if utils.Watch {
+			return watchDirectory(cmd)
+		}
+
+		if isSARIFOutput() {
+			return handleSARIFOutput(cmd)
+		}
+		if utils.OutputFormat != "" {
+			if _, err := report.ParseFormat(utils.OutputFormat); err != nil {
+				return err
+			}
+		}
 
+		directoryAnalyzer := &analyzer.DirectoryAnalyzerImpl{Cache: openCache(cmd)}
 		if utils.FilePath != "" {
-			handleFileAnalysis(cmd, lineAnalyzer, commentAnalyzer, classFuncAnalyzer, indentationAnalyzer, dependenciesAnalyzer, percentAnalyzer, methodCountAnalyzer, averageFunctionAnalyzer)
-		} else {
-			handleDirectoryAnalysis(cmd, lineAnalyzer, commentAnalyzer, classFuncAnalyzer, indentationAnalyzer, dependenciesAnalyzer, percentAnalyzer, methodCountAnalyzer, averageFunctionAnalyzer)
+			return handleFileAnalysis(cmd, directoryAnalyzer)
 		}
+		return handleDirectoryAnalysis(cmd, directoryAnalyzer)
 	},
 }
 
-func initializeAnalyzers() (*analyzer.CountLinesAnalyzerImpl, *analyzer.CountCommentsAnalyzerImpl, *analyzer.CountClassAndFunctionsImpl, *analyzer.IdentationAnalyzerImpl, *analyzer.CountDependenciesAnalyzerImpl, *analyzer.MethodCountAnalyzerImpl, *analyzer.AverageFunctionAnalyzerImpl) {
-	return &analyzer.CountLinesAnalyzerImpl{},
-		&analyzer.CountCommentsAnalyzerImpl{},
-		&analyzer.CountClassAndFunctionsImpl{},
-		&analyzer.IdentationAnalyzerImpl{},
-		&analyzer.CountDependenciesAnalyzerImpl{},
-		&analyzer.MethodCountAnalyzerImpl{},
-		&analyzer.AverageFunctionAnalyzerImpl{}
-}
-
-func handleFileAnalysis(cmd *cobra.Command, lineAnalyzer *analyzer.CountLinesAnalyzerImpl, commentAnalyzer *analyzer.CountCommentsAnalyzerImpl, classFuncAnalyzer *analyzer.CountClassAndFunctionsImpl, indentationAnalyzer *analyzer.IdentationAnalyzerImpl, dependenciesAnalyzer *analyzer.CountDependenciesAnalyzerImpl, percentAnalyzer *analyzer.CountPercentAnalyzerImpl, methodCountAnalyzer *analyzer.MethodCountAnalyzerImpl, averageFunctionAnalyzer *analyzer.AverageFunctionAnalyzerImpl) {
-	lineCount := lineAnalyzer.CountLinesByFilePath(utils.FilePath)
-	commentCount := commentAnalyzer.CountCommentsByFilePath(utils.FilePath)
-	classAndFunctionResult := classFuncAnalyzer.CountClassesAndFunctionsByFilePath(utils.FilePath)
-	percentResult := percentAnalyzer.CountPercentByFilePath(utils.FilePath)
-	methodCountResult := methodCountAnalyzer.AnalyzeFile(utils.FilePath)
-	averageFunctionSize := averageFunctionAnalyzer.CalculateAverageFunctionSize(utils.FilePath)
-
-	tempFilePath := utils.FilePath
-	tempDirPath := utils.DirectoryPath
-	utils.DirectoryPath = ""
-	indentResults, _ := indentationAnalyzer.IdentationByFilePath()
-	utils.FilePath = tempFilePath
-	utils.DirectoryPath = tempDirPath
-
-	dependencieResultMap, _ := dependenciesAnalyzer.CountDependenciesByFilePath(utils.FilePath)
+// handleFileAnalysis runs all the analyzers over the file.
+func handleFileAnalysis(cmd *cobra.Command, directoryAnalyzer *analyzer.DirectoryAnalyzerImpl) error {
+	filePath, err := filepath.Abs(utils.FilePath)
+	if err != nil {
+		return err
+	}
+	file, err := directoryAnalyzer.AnalyzeFile(filePath)
+	if err != nil {
+		return err
+	}
 
 	params := AnalysisParams{
-		FilePath:            utils.FilePath,
-		DirectoryPath:       utils.DirectoryPath,
-		OutputFilePath:      utils.OutputFilePath,
-		Detailed:            utils.Detailed,
-		LineCount:           int64(lineCount.TotalLines),
-		CommentCount:        commentCount.CommentLines,
-		Classes:             classAndFunctionResult.Classes,
-		Functions:           classAndFunctionResult.Functions,
-		CommentPercentage:   percentResult.CommentPercentage,
-		IndentResults:       indentResults,
-		DependenciesResults: dependencieResultMap,
-		MethodCountResult:   methodCountResult,
-		AverageFunctionSize: averageFunctionSize,
+		FilePath:       utils.FilePath,
+		OutputFilePath: utils.OutputFilePath,
+		Detailed:       utils.Detailed,
+		Report:         results.New(filepath.Dir(filePath), map[string]analyzer.FileAnalysis{filepath.Base(filePath): file}, nil),
 	}
 
-	if utils.OutputFilePath == "" {
-		printFileResults(cmd, params)
-	} else {
-		generateJSONOutput(cmd, params)
+	if err := outputAnalysis(cmd, params); err != nil {
+		return err
 	}
+	recordHistory(cmd, params)
+	return nil
 }
 
-func handleDirectoryAnalysis(cmd *cobra.Command, lineAnalyzer *analyzer.CountLinesAnalyzerImpl, commentAnalyzer *analyzer.CountCommentsAnalyzerImpl, classFuncAnalyzer *analyzer.CountClassAndFunctionsImpl, indentationAnalyzer *analyzer.IdentationAnalyzerImpl, dependenciesAnalyzer *analyzer.CountDependenciesAnalyzerImpl, percentAnalyzer *analyzer.CountPercentAnalyzerImpl, methodCountAnalyzer *analyzer.MethodCountAnalyzerImpl, averageFunctionAnalyzer *analyzer.AverageFunctionAnalyzerImpl) {
-	lineResults, totalLines := lineAnalyzer.CountLinesByDirectory(utils.DirectoryPath)
-	commentResults, totalComments := commentAnalyzer.CountCommentsByDirectory(utils.DirectoryPath)
-	classFuncResults, totalClassesAndFunctions := classFuncAnalyzer.CountClassesAndFunctionsByDirectory(utils.DirectoryPath)
-	_, percentResults := percentAnalyzer.CountCommentsByDirectory(utils.DirectoryPath)
-	methodCountResults, totalMethodCount := methodCountAnalyzer.AnalyzeDirectory(utils.DirectoryPath)
-	_, overallAverage := averageFunctionAnalyzer.CalculateAverageFunctionSizeByDirectory(utils.DirectoryPath)
-
-	tempFilePath := utils.FilePath
-	tempDirPath := utils.DirectoryPath
-	utils.FilePath = ""
-	indentResults, _ := indentationAnalyzer.IdentationByFilePath()
-	utils.FilePath = tempFilePath
-	utils.DirectoryPath = tempDirPath
-
-	dependencieResultMap, _ := dependenciesAnalyzer.CountDependenciesByDirectory(utils.DirectoryPath)
+// handleDirectoryAnalysis runs all the analyzers over the directory in a
+// single concurrent walk. The files that cannot be read are listed in the
+// report, and make the command fail once it is written.
+func handleDirectoryAnalysis(cmd *cobra.Command, directoryAnalyzer analyzer.DirectoryAnalyzer) error {
+	directory, err := directoryAnalyzer.AnalyzeDirectory(utils.DirectoryPath)
+	skipped, fatal := analyzer.Skipped(err)
+	if fatal != nil {
+		return fatal
+	}
+	root, fatal := utils.ExpandPath(utils.DirectoryPath)
+	if fatal != nil {
+		return fatal
+	}
 
 	params := AnalysisParams{
-		DirectoryPath:       utils.DirectoryPath,
-		OutputFilePath:      utils.OutputFilePath,
-		Detailed:            utils.Detailed,
-		LineCount:           int64(totalLines.TotalLines),
-		CommentCount:        totalComments.TotalComments,
-		Classes:             totalClassesAndFunctions.Classes,
-		Functions:           totalClassesAndFunctions.Functions,
-		CommentPercentage:   percentResults.CommentPercentage,
-		IndentResults:       indentResults,
-		DependenciesResults: dependencieResultMap,
-		TotalMethodCount:    totalMethodCount,
-		OverallAverageSize:  overallAverage,
-		LineResults:         lineResults,
-		CommentResults:      commentResults,
-		ClassFuncResults:    classFuncResults,
-		PercentResults:      percentResults,
-		MethodCountResults:  methodCountResults,
+		DirectoryPath:  utils.DirectoryPath,
+		OutputFilePath: utils.OutputFilePath,
+		Detailed:       utils.Detailed,
+		Report:         results.New(root, directory.Files, skipped),
+		Skipped:        skipped,
 	}
 
+	// the owning teams are only part of the detailed report
 	if utils.Detailed {
-		generateDetailedJSONOutput(cmd, params)
-	} else if utils.OutputFilePath == "" {
-		printDirectoryResults(cmd, params)
-	} else {
-		generateJSONOutput(cmd, params)
-	}
-}
-
-func consolidateDependencies(dependenciesResults map[string]interface{}) map[string]interface{} {
-	if dependenciesResults != nil {
-		consolidatedDeps := map[string]interface{}{
-			"total_dependencies": 0,
-			"dependencies":       []string{},
-			"native_modules":     []string{},
-		}
-
-		uniqueDeps := make(map[string]struct{})
-		uniqueNativeModules := make(map[string]struct{})
-
-		for _, fileDeps := range dependenciesResults {
-			if deps, ok := fileDeps.(map[string]interface{}); ok {
-				if depList, exists := deps["dependencies"].([]string); exists {
-					for _, dep := range depList {
-						uniqueDeps[dep] = struct{}{}
-					}
-				}
-				if nativeList, exists := deps["native_modules"].([]string); exists {
-					for _, native := range nativeList {
-						uniqueNativeModules[native] = struct{}{}
-					}
-				}
-			}
-		}
-
-		for dep := range uniqueDeps {
-			consolidatedDeps["dependencies"] = append(consolidatedDeps["dependencies"].([]string), dep)
+		ownershipAnalyzer := &analyzer.OwnershipAnalyzerImpl{}
+		codeOwners, ownersErr := ownershipAnalyzer.CodeOwnersByDirectory(utils.DirectoryPath)
+		if _, ownersErr = analyzer.Skipped(ownersErr); ownersErr != nil {
+			fmt.Fprintf(cmd.OutOrStderr(), "%sWarning: could not read CODEOWNERS: %s%s\n", utils.RED, ownersErr, utils.RESET_COLOR)
 		}
-		for native := range uniqueNativeModules {
-			consolidatedDeps["native_modules"] = append(consolidatedDeps["native_modules"].([]string), native)
+		for i, file := range params.Report.Files {
+			params.Report.Files[i].Owners = codeOwners[file.Path]
 		}
+	}
 
-		consolidatedDeps["total_dependencies"] = len(uniqueDeps)
-		return consolidatedDeps
+	if outputErr := outputAnalysis(cmd, params); outputErr != nil {
+		return outputErr
 	}
-	return nil
+	recordHistory(cmd, params)
+	return err
 }
 
-func outputJSON(cmd *cobra.Command, outputPath string, data interface{}) {
-	if outputPath != "" {
-		fileInfo, err := os.Stat(outputPath)
-		if err == nil && fileInfo.IsDir() {
-			outputPath = fmt.Sprintf("%s/analysis_report.json", outputPath)
-		}
-
-		file, err := os.Create(outputPath)
-		if err != nil {
-			fmt.Fprintf(cmd.OutOrStderr(), "Error creating JSON file: %s\n", err)
-			return
-		}
-		defer file.Close()
-
-		encoder := json.NewEncoder(file)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(data); err != nil {
-			fmt.Fprintf(cmd.OutOrStderr(), "Error writing JSON to file: %s\n", err)
-			return
-		}
+// isSARIFOutput reports whether the findings are requested as a SARIF log,
+// with --format sarif or a .sarif output path.
+func isSARIFOutput() bool {
+	if utils.OutputFormat != "" {
+		return strings.EqualFold(utils.OutputFormat, "sarif")
+	}
+	return strings.EqualFold(filepath.Ext(utils.OutputFilePath), ".sarif")
+}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "JSON report saved to %s\n", outputPath)
-	} else {
-		formattedJSON, err := json.MarshalIndent(data, "", "  ")
-		if err != nil {
-			fmt.Fprintf(cmd.OutOrStderr(), "Error formatting JSON: %s\n", err)
-			return
-		}
+var analysisColumns = []report.Column{
+	{Key: "file", Title: "File"},
+	{Key: "lines", Title: "Lines"},
+	{Key: "comments", Title: "Comments"},
+	{Key: "classes", Title: "Classes"},
+	{Key: "functions", Title: "Functions"},
+	{Key: "max_complexity", Title: "Max complexity"},
+	{Key: "maintainability_index", Title: "Maintainability index"},
+}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", string(formattedJSON))
+// outputAnalysis writes the analysis in the format chosen with --format,
+// or JSON for an output path without a known extension. The json and yaml
+// formats encode the versioned report; the functions of the files of a
+// directory are only part of it with --detailed. With --detailed a
+// directory analysis is written as JSON in the terminal too, unless
+// another format is requested.
+func outputAnalysis(cmd *cobra.Command, params AnalysisParams) error {
+	totals := params.Report.Totals
+	summary := []report.Field{
+		{Key: "lines", Title: "Total lines", Value: totals.Lines},
+		{Key: "comments", Title: "Comment lines", Value: totals.CommentLines},
+		{Key: "comment_percentage", Title: "Comment percentage", Value: totals.CommentPercentage},
+		{Key: "classes", Title: "Classes", Value: totals.Classes},
+		{Key: "functions", Title: "Functions", Value: totals.Functions},
+		{Key: "average_complexity", Title: "Average complexity", Value: totals.AverageComplexity},
+		{Key: "max_complexity", Title: "Max complexity", Value: totals.MaxComplexity},
+		{Key: "maintainability_index", Title: "Maintainability index", Value: totals.MaintainabilityIndex},
 	}
-}
 
-func generateJSONOutput(cmd *cobra.Command, params AnalysisParams) {
-	if params.IndentResults != nil {
-		delete(params.IndentResults, "path")
-		delete(params.IndentResults, "filename")
+	output := report.Report{
+		Title:   string(utils.ANALYSIS),
+		Columns: analysisColumns,
+		Rows:    analysisRows(params.Report),
+		Summary: summary,
+		Data:    params.Report,
+		Text: func(w io.Writer) {
+			if params.FilePath != "" {
+				printFileResults(w, params)
+			} else {
+				printDirectoryResults(w, params.Report)
+			}
+		},
+		HTML: func(w io.Writer) error {
+			return templates.RenderDashboard(w, newDashboard(params, summary))
+		},
+		Skipped: params.Skipped,
 	}
 
-	summaryData := map[string]interface{}{
-		"lines":                 params.LineCount,
-		"comments":              params.CommentCount,
-		"comment_percentage":    fmt.Sprintf("%.2f%%", params.CommentPercentage),
-		"classes":               params.Classes,
-		"functions":             params.Functions,
-		"public_methods":        params.MethodCountResult.Public,
-		"private_methods":       params.MethodCountResult.Private,
-		"average_function_size": fmt.Sprintf("%.4f", params.AverageFunctionSize),
-		"dependencies":          consolidateDependencies(params.DependenciesResults),
-		"indentation":           params.IndentResults,
+	options := report.Options{
+		Format:     utils.OutputFormat,
+		Path:       params.OutputFilePath,
+		FileFormat: report.JSON,
+		FileName:   "analysis_report",
 	}
 
-	result := map[string]interface{}{
-		"directory": params.FilePath,
-		"summary":   summaryData,
+	if params.FilePath == "" {
+		if params.Detailed {
+			options.FileName = "detailed_analysis"
+			if options.Format == "" && options.Path == "" {
+				options.Format = string(report.JSON)
+			}
+		} else {
+			output.Data = withoutFunctions(params.Report)
+		}
 	}
 
-	if params.DirectoryPath != "" && params.FilePath == "" {
-		result["directory"] = params.DirectoryPath
-		summaryData["public_methods"] = params.TotalMethodCount.Public
-		summaryData["private_methods"] = params.TotalMethodCount.Private
-		summaryData["average_function_size"] = fmt.Sprintf("%.4f", params.OverallAverageSize)
+	return report.Output(cmd.OutOrStdout(), output, options)
+}
+
+// withoutFunctions returns a copy of the report without the functions of
+// its files.
+func withoutFunctions(result results.Report) results.Report {
+	result.Files = slices.Clone(result.Files)
+	for i := range result.Files {
+		result.Files[i].Functions = nil
 	}
+	return result
+}
 
-	outputJSON(cmd, params.OutputFilePath, result)
+// analysisRows returns the table row of each analyzed file.
+func analysisRows(result results.Report) [][]interface{} {
+	rows := make([][]interface{}, 0, len(result.Files))
+	for _, file := range result.Files {
+		rows = append(rows, []interface{}{
+			file.Path,
+			file.Lines,
+			file.CommentLines,
+			file.Classes,
+			file.FunctionCount,
+			file.MaxComplexity,
+			file.MaintainabilityIndex,
+		})
+	}
+	return rows
 }
 
-func generateDetailedJSONOutput(cmd *cobra.Command, params AnalysisParams) {
-	fileIndentData := make(map[string]interface{})
-	if files, ok := params.IndentResults["files"].([]map[string]interface{}); ok {
-		for _, fileData := range files {
-			if filename, ok := fileData["filename"].(string); ok {
-				fileIndentData[filename] = fileData["stats"]
-			}
-		}
+// handleSARIFOutput writes the findings of the analyzed file or directory
+// as a SARIF log, to the output path or to the terminal. The files that
+// cannot be read are left out of the log, and make the command fail once it
+// is written.
+func handleSARIFOutput(cmd *cobra.Command) error {
+	findingsAnalyzer := &analyzer.FindingsAnalyzerImpl{}
+
+	var findings []analyzer.Finding
+	var root string
+	var err error
+	if utils.FilePath != "" {
+		findings, err = findingsAnalyzer.FindingsByFilePath(utils.FilePath, config.Active)
+		root, _ = os.Getwd()
+	} else {
+		findings, err = findingsAnalyzer.FindingsByDirectory(utils.DirectoryPath, config.Active)
+		root, _ = utils.ExpandPath(utils.DirectoryPath)
+	}
+	if _, fatal := analyzer.Skipped(err); fatal != nil {
+		return fatal
 	}
 
-	fileDetails := make([]map[string]interface{}, 0, len(params.LineResults))
-	for filename, lineResult := range params.LineResults {
-		fileInfo := map[string]interface{}{
-			"filename": filename,
-			"metrics": map[string]interface{}{
-				"lines":           lineResult.TotalLines,
-				"comments":        params.CommentResults[filename].CommentLines,
-				"classes":         params.ClassFuncResults[filename].Classes,
-				"functions":       params.ClassFuncResults[filename].Functions,
-				"public_methods":  params.MethodCountResults[filename].Public,
-				"private_methods": params.MethodCountResults[filename].Private,
-			},
-		}
+	log := sarif.NewLog(findings, root, version.Version)
 
-		if indentData, ok := fileIndentData[filename]; ok {
-			fileInfo["indentation"] = indentData
+	outputPath := utils.OutputFilePath
+	if outputPath == "" {
+		if writeErr := log.Write(cmd.OutOrStdout()); writeErr != nil {
+			return fmt.Errorf("error writing SARIF: %w", writeErr)
 		}
-
-		fileDetails = append(fileDetails, fileInfo)
+		return err
 	}
 
-	totalLines := 0
-	totalComments := 0
-	totalClasses := 0
-	totalFunctions := 0
+	if fileInfo, err := os.Stat(outputPath); err == nil && fileInfo.IsDir() {
+		outputPath = filepath.Join(outputPath, "report.sarif")
+	}
 
-	for filePath := range params.LineResults {
-		totalLines += int(params.LineResults[filePath].TotalLines)
-		totalComments += params.CommentResults[filePath].CommentLines
-		totalClasses += params.ClassFuncResults[filePath].Classes
-		totalFunctions += params.ClassFuncResults[filePath].Functions
+	file, createErr := os.Create(outputPath)
+	if createErr != nil {
+		return fmt.Errorf("error creating SARIF file: %w", createErr)
 	}
+	defer file.Close()
 
-	detailedResult := map[string]interface{}{
-		"directory_path": params.DirectoryPath,
-		"summary": map[string]interface{}{
-			"total_files":       len(params.LineResults),
-			"total_lines":       totalLines,
-			"total_comments":    totalComments,
-			"total_classes":     totalClasses,
-			"total_functions":   totalFunctions,
-			"total_public_methods":  params.TotalMethodCount.Public,
-			"total_private_methods": params.TotalMethodCount.Private,
-		},
-		"dependencies": consolidateDependencies(params.DependenciesResults),
-		"files":        fileDetails,
+	if writeErr := log.Write(file); writeErr != nil {
+		return fmt.Errorf("error writing SARIF to file: %w", writeErr)
 	}
 
-	if params.OutputFilePath != "" {
-		outputPath := params.OutputFilePath
+	fmt.Fprintf(cmd.OutOrStdout(), "\033[1;34mReport generated successfully at %s%s (%d findings)\n", outputPath, utils.RESET_COLOR, len(findings))
+	return err
+}
 
-		fileInfo, err := os.Stat(outputPath)
-		if err == nil && fileInfo.IsDir() {
-			outputPath = fmt.Sprintf("%s/detailed_analysis.json", outputPath)
-		}
+func printFileResults(w io.Writer, params AnalysisParams) {
+	file := params.Report.Files[0]
+	fmt.Fprintf(w, "\n%s=== Analysis Results for %s ===%s\n",
+		utils.BLUE, params.FilePath, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Total Lines: %s%d%s\n", utils.GREEN, file.Lines, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Comment Lines: %s%d%s\n", utils.GREEN, file.CommentLines, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Comment Percentage: %s%.2f%%%s\n", utils.GREEN, file.CommentPercentage, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Classes: %s%d%s\n", utils.GREEN, file.Classes, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Functions: %s%d%s\n", utils.GREEN, file.FunctionCount, utils.RESET_COLOR)
+	printTypeDeclarations(w, file.Interfaces, file.Enums, file.TypeAliases)
+	fmt.Fprintf(w, "Public Methods: %s%d%s\n", utils.GREEN, file.PublicMethods, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Private Methods: %s%d%s\n", utils.GREEN, file.PrivateMethods, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Average Function Size: %s%.2f%s\n", utils.GREEN, file.AverageFunctionSize, utils.RESET_COLOR)
+
+	indentation := file.Indentation
+	fmt.Fprintf(w, "\n%s=== Indentation Analysis ===%s\n", utils.BLUE, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Max Indent Level: %s%d%s\n", utils.GREEN, indentation.MaxLevel, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Average Indent Level: %s%.2f%s\n", utils.GREEN, indentation.AverageLevel, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Uses Spaces: %s%t%s\n", utils.GREEN, indentation.UsesSpaces, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Uses Tabs: %s%t%s\n", utils.GREEN, indentation.UsesTabs, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Mixed Indentation: %s%t%s\n", utils.GREEN, indentation.Mixed, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Max Nesting Depth: %s%d%s\n", utils.GREEN, indentation.MaxNestingDepth, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Max Cognitive Complexity: %s%d%s\n", utils.GREEN, indentation.MaxCognitiveComplexity, utils.RESET_COLOR)
+	printDeepestFunctions(w, file.Functions)
+
+	printComplexity(w, params.Report)
+	printMaintainability(w, file.MaintainabilityIndex, file.Halstead)
+
+	fmt.Fprintf(w, "\n%s=== Dependencies Analysis ===%s\n", utils.BLUE, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Total Dependencies: %s%d%s\n", utils.GREEN, len(file.Dependencies), utils.RESET_COLOR)
+	fmt.Fprintf(w, "Dependencies: %s%v%s\n", utils.GREEN, file.Dependencies, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Native Modules: %s%v%s\n", utils.GREEN, file.NativeModules, utils.RESET_COLOR)
+}
 
-		file, err := os.Create(outputPath)
-		if err != nil {
-			fmt.Fprintf(cmd.OutOrStderr(), "Error creating JSON file: %s\n", err)
-			return
-		}
-		defer file.Close()
+// printComplexity prints the complexity of the functions of the report,
+// with the most complex ones.
+func printComplexity(w io.Writer, result results.Report) {
+	fmt.Fprintf(w, "\n%s=== Complexity Analysis ===%s\n", utils.BLUE, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Average Complexity: %s%.2f%s\n", utils.GREEN, result.Totals.AverageComplexity, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Max Complexity: %s%d%s\n", utils.GREEN, result.Totals.MaxComplexity, utils.RESET_COLOR)
 
-		encoder := json.NewEncoder(file)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(detailedResult); err != nil {
-			fmt.Fprintf(cmd.OutOrStderr(), "Error writing JSON to file: %s\n", err)
-			return
+	type located struct {
+		results.Function
+		file string
+	}
+	var worst []located
+	for _, file := range result.Files {
+		for _, function := range file.Functions {
+			worst = append(worst, located{function, file.Path})
 		}
+	}
+	sort.SliceStable(worst, func(i, j int) bool {
+		return worst[i].Complexity > worst[j].Complexity
+	})
 
-		fmt.Fprintf(cmd.OutOrStdout(), "Detailed JSON report saved to %s\n", outputPath)
-	} else {
-		formattedJSON, err := json.MarshalIndent(detailedResult, "", "  ")
-		if err != nil {
-			fmt.Fprintf(cmd.OutOrStderr(), "Error formatting detailed JSON: %s\n", err)
-			return
+	for _, function := range worst[:min(5, len(worst))] {
+		fmt.Fprintf(w, "  %s%d%s %s (%s:%d)\n", utils.GREEN, function.Complexity, utils.RESET_COLOR, function.Name, function.file, function.StartLine)
+	}
+}
+
+func printMaintainability(w io.Writer, maintainabilityIndex float64, halstead results.Halstead) {
+	fmt.Fprintf(w, "\n%s=== Maintainability Analysis ===%s\n", utils.BLUE, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Maintainability Index: %s%.2f%s\n", utils.GREEN, maintainabilityIndex, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Halstead Volume: %s%.2f%s\n", utils.GREEN, halstead.Volume, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Halstead Difficulty: %s%.2f%s\n", utils.GREEN, halstead.Difficulty, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Halstead Effort: %s%.2f%s\n", utils.GREEN, halstead.Effort, utils.RESET_COLOR)
+}
+
+// printDeepestFunctions lists the most deeply nested functions, the ones
+// worth a look first when untangling callbacks and promise chains.
+func printDeepestFunctions(w io.Writer, functions []results.Function) {
+	deepest := slices.Clone(functions)
+	sort.SliceStable(deepest, func(i, j int) bool {
+		if deepest[i].NestingDepth != deepest[j].NestingDepth {
+			return deepest[i].NestingDepth > deepest[j].NestingDepth
 		}
+		return deepest[i].CognitiveComplexity > deepest[j].CognitiveComplexity
+	})
 
-		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", string(formattedJSON))
+	for _, function := range deepest[:min(5, len(deepest))] {
+		if function.NestingDepth == 0 && function.CognitiveComplexity == 0 {
+			break
+		}
+		fmt.Fprintf(w, "  depth %s%d%s, cognitive %s%d%s %s (line %d)\n", utils.GREEN, function.NestingDepth, utils.RESET_COLOR, utils.GREEN, function.CognitiveComplexity, utils.RESET_COLOR, function.Name, function.StartLine)
 	}
 }
 
-func printFileResults(cmd *cobra.Command, params AnalysisParams) {
-	fmt.Fprintf(cmd.OutOrStdout(), "\n%s=== Analysis Results for %s ===%s\n",
-		utils.BLUE, params.FilePath, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Total Lines: %s%d%s\n", utils.GREEN, params.LineCount, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Comment Lines: %s%d%s\n", utils.GREEN, params.CommentCount, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Comment Percentage: %s%.2f%%%s\n", utils.GREEN, params.CommentPercentage, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Classes: %s%d%s\n", utils.GREEN, params.Classes, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Functions: %s%d%s\n", utils.GREEN, params.Functions, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Public Methods: %s%d%s\n", utils.GREEN, params.MethodCountResult.Public, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Private Methods: %s%d%s\n", utils.GREEN, params.MethodCountResult.Private, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Average Function Size: %s%.2f%s\n", utils.GREEN, params.AverageFunctionSize, utils.RESET_COLOR)
-
-	if stats, ok := params.IndentResults["stats"].(analyzer.IndentResult); ok {
-		fmt.Fprintf(cmd.OutOrStdout(), "\n%s=== Indentation Analysis ===%s\n", utils.BLUE, utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Max Indent Level: %s%d%s\n", utils.GREEN, stats.MaxIndentLevel, utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Average Indent Level: %s%.2f%s\n", utils.GREEN, stats.AverageIndentLevel, utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Uses Spaces: %s%t%s\n", utils.GREEN, stats.UsesSpaces, utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Uses Tabs: %s%t%s\n", utils.GREEN, stats.UsesTabs, utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Mixed Indentation: %s%t%s\n", utils.GREEN, stats.MixedIndentation, utils.RESET_COLOR)
+// printTypeDeclarations prints the TypeScript type-only declarations, which
+// plain JavaScript code never has.
+func printTypeDeclarations(w io.Writer, interfaces, enums, typeAliases int) {
+	if interfaces > 0 {
+		fmt.Fprintf(w, "Interfaces: %s%d%s\n", utils.GREEN, interfaces, utils.RESET_COLOR)
 	}
-
-	hasDeps := params.DependenciesResults["total_dependencies"] != nil && params.DependenciesResults["dependencies"] != nil && params.DependenciesResults["native_modules"] != nil
-
-	if hasDeps {
-		fmt.Fprintf(cmd.OutOrStdout(), "\n%s=== Dependencies Analysis ===%s\n", utils.BLUE, utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Total Dependencies: %s%d%s\n", utils.GREEN, params.DependenciesResults["total_dependencies"], utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Dependencies: %s%v%s\n", utils.GREEN, params.DependenciesResults["dependencies"], utils.RESET_COLOR)
-		fmt.Fprintf(cmd.OutOrStdout(), "Native Modules: %s%v%s\n", utils.GREEN, params.DependenciesResults["native_modules"], utils.RESET_COLOR)
+	if enums > 0 {
+		fmt.Fprintf(w, "Enums: %s%d%s\n", utils.GREEN, enums, utils.RESET_COLOR)
+	}
+	if typeAliases > 0 {
+		fmt.Fprintf(w, "Type Aliases: %s%d%s\n", utils.GREEN, typeAliases, utils.RESET_COLOR)
 	}
 }
 
-func printDirectoryResults(cmd *cobra.Command, params AnalysisParams) {
-	fmt.Fprintf(cmd.OutOrStdout(), "\n%s=== Directory Analysis Summary ===%s\n",
+func printDirectoryResults(w io.Writer, result results.Report) {
+	totals := result.Totals
+	fmt.Fprintf(w, "\n%s=== Directory Analysis Summary ===%s\n",
 		utils.BLUE, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Total Lines: %s%d%s\n", utils.GREEN, params.LineCount, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Comment Lines: %s%d%s\n", utils.GREEN, params.CommentCount, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Comment Percentage: %s%.2f%%%s\n", utils.GREEN, params.CommentPercentage, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Classes: %s%d%s\n", utils.GREEN, params.Classes, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Functions: %s%d%s\n", utils.GREEN, params.Functions, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Total Public Methods: %s%d%s\n", utils.GREEN, params.TotalMethodCount.Public, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Total Private Methods: %s%d%s\n", utils.GREEN, params.TotalMethodCount.Private, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "Average function size in directory: %s%.2f lines%s\n", utils.GREEN, params.OverallAverageSize, utils.RESET_COLOR)
-	fmt.Fprintf(cmd.OutOrStdout(), "\n%s=== Indentation Analysis Summary ===%s\n", utils.BLUE, utils.RESET_COLOR)
-
-	if files, ok := params.IndentResults["files"].([]map[string]interface{}); ok && len(files) > 0 {
+	fmt.Fprintf(w, "Total Lines: %s%d%s\n", utils.GREEN, totals.Lines, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Comment Lines: %s%d%s\n", utils.GREEN, totals.CommentLines, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Comment Percentage: %s%.2f%%%s\n", utils.GREEN, totals.CommentPercentage, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Classes: %s%d%s\n", utils.GREEN, totals.Classes, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Functions: %s%d%s\n", utils.GREEN, totals.Functions, utils.RESET_COLOR)
+	printTypeDeclarations(w, totals.Interfaces, totals.Enums, totals.TypeAliases)
+	fmt.Fprintf(w, "Total Public Methods: %s%d%s\n", utils.GREEN, totals.PublicMethods, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Total Private Methods: %s%d%s\n", utils.GREEN, totals.PrivateMethods, utils.RESET_COLOR)
+	fmt.Fprintf(w, "Average function size in directory: %s%.2f lines%s\n", utils.GREEN, totals.AverageFunctionSize, utils.RESET_COLOR)
+	printComplexity(w, result)
+	printMaintainability(w, totals.MaintainabilityIndex, totals.Halstead)
+	fmt.Fprintf(w, "\n%s=== Indentation Analysis Summary ===%s\n", utils.BLUE, utils.RESET_COLOR)
+
+	if len(result.Files) > 0 {
 
 		totalMaxIndent := 0
 		totalAvgIndent := 0.0
 		spacesCount := 0
 		tabsCount := 0
 		mixedCount := 0
-
-		for _, file := range files {
-			if stats, ok := file["stats"].(analyzer.IndentResult); ok {
-				totalMaxIndent += stats.MaxIndentLevel
-				totalAvgIndent += stats.AverageIndentLevel
-				if stats.UsesSpaces {
-					spacesCount++
-				}
-				if stats.UsesTabs {
-					tabsCount++
-				}
-				if stats.MixedIndentation {
-					mixedCount++
-				}
+		totalMaxNesting := 0
+		totalMaxCognitive := 0
+
+		for _, file := range result.Files {
+			indentation := file.Indentation
+			totalMaxIndent += indentation.MaxLevel
+			totalAvgIndent += indentation.AverageLevel
+			if indentation.UsesSpaces {
+				spacesCount++
+			}
+			if indentation.UsesTabs {
+				tabsCount++
+			}
+			if indentation.Mixed {
+				mixedCount++
 			}
+			totalMaxNesting += indentation.MaxNestingDepth
+			totalMaxCognitive += indentation.MaxCognitiveComplexity
 		}
 
-		fileCount := len(files)
-		if fileCount > 0 {
-			fmt.Fprintf(cmd.OutOrStdout(), "Avg Max Indent Level: %s%.2f%s\n",
-				utils.GREEN, float64(totalMaxIndent)/float64(fileCount), utils.RESET_COLOR)
-			fmt.Fprintf(cmd.OutOrStdout(), "Avg Indent Level: %s%.2f%s\n",
-				utils.GREEN, totalAvgIndent/float64(fileCount), utils.RESET_COLOR)
-			fmt.Fprintf(cmd.OutOrStdout(), "Files Using Spaces: %s%d%s\n",
-				utils.GREEN, spacesCount, utils.RESET_COLOR)
-			fmt.Fprintf(cmd.OutOrStdout(), "Files Using Tabs: %s%d%s\n",
-				utils.GREEN, tabsCount, utils.RESET_COLOR)
-			fmt.Fprintf(cmd.OutOrStdout(), "Files With Mixed Indentation: %s%d%s\n",
-				utils.GREEN, mixedCount, utils.RESET_COLOR)
-		}
+		fileCount := len(result.Files)
+		fmt.Fprintf(w, "Avg Max Indent Level: %s%.2f%s\n",
+			utils.GREEN, float64(totalMaxIndent)/float64(fileCount), utils.RESET_COLOR)
+		fmt.Fprintf(w, "Avg Indent Level: %s%.2f%s\n",
+			utils.GREEN, totalAvgIndent/float64(fileCount), utils.RESET_COLOR)
+		fmt.Fprintf(w, "Files Using Spaces: %s%d%s\n",
+			utils.GREEN, spacesCount, utils.RESET_COLOR)
+		fmt.Fprintf(w, "Files Using Tabs: %s%d%s\n",
+			utils.GREEN, tabsCount, utils.RESET_COLOR)
+		fmt.Fprintf(w, "Files With Mixed Indentation: %s%d%s\n",
+			utils.GREEN, mixedCount, utils.RESET_COLOR)
+		fmt.Fprintf(w, "Avg Max Nesting Depth: %s%.2f%s\n",
+			utils.GREEN, float64(totalMaxNesting)/float64(fileCount), utils.RESET_COLOR)
+		fmt.Fprintf(w, "Avg Max Cognitive Complexity: %s%.2f%s\n",
+			utils.GREEN, float64(totalMaxCognitive)/float64(fileCount), utils.RESET_COLOR)
 	}
 }
 
 func init() {
 	RunAllCommand.Flags().StringVarP(&utils.FilePath, "file", "f", "", "Path to the JavaScript file (must be a single file, not a directory)")
 	RunAllCommand.Flags().StringVarP(&utils.DirectoryPath, "directory", "d", "", "Path to the directory containing JavaScript files. The tool will automatically expand the provided path.")
-	RunAllCommand.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Specify the output file path. If omitted, results will be displayed in the terminal.")
-}
\ No newline at end of file
+	RunAllCommand.Flags().StringVar(&utils.ChangedSince, "changed-since", "", "Only analyze the files of the directory changed since the common ancestor of this git ref and HEAD, including uncommitted and untracked files")
+	RunAllCommand.Flags().BoolVar(&utils.Staged, "staged", false, "Only analyze the files of the directory staged in git")
+	RunAllCommand.Flags().StringVarP(&utils.OutputFilePath, "output", "o", "", "Specify the output file path, or a directory to write analysis_report.<format> into (JSON by default). If omitted, results will be displayed in the terminal.")
+	RunAllCommand.Flags().StringVar(&utils.OutputFormat, "format", "", report.FlagUsage+" Use sarif for a SARIF 2.1.0 log of the findings.")
+	RunAllCommand.Flags().BoolVar(&utils.Detailed, "detailed", false, "Generate a detailed report with per-file metrics and function records (directory analysis only), JSON unless --format is given.")
+	RunAllCommand.Flags().BoolVar(&noHistory, "no-history", false, "Do not record the summary of the analysis in the history (.gocli/history.jsonl).")
+	RunAllCommand.Flags().BoolVar(&utils.Watch, "watch", false, watch.FlagUsage)
+	RunAllCommand.Flags().BoolVar(&noCache, "no-cache", false, "Analyze every file again instead of reusing the results of the unchanged files cached in .gocli/cache.")
+}
@@ -0,0 +1,74 @@
+package run_all_commands
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/utils"
+	"go-cli-tool/internal/watch"
+
+	"github.com/spf13/cobra"
+)
+
+// watchDirectory redraws the summary of the analysis as the files of the
+// directory change. The results of the files are cached as in a single
+// analysis, so that a new watch starts from the files already analyzed.
+func watchDirectory(cmd *cobra.Command) error {
+	directoryAnalyzer := &analyzer.DirectoryAnalyzerImpl{Cache: openCache(cmd)}
+	session := &watch.Session[analyzer.FileAnalysis]{
+		Title:     string(utils.ANALYSIS),
+		Dir:       utils.DirectoryPath,
+		Analyze:   directoryAnalyzer.AnalyzeFile,
+		Summarize: summarizeFiles,
+	}
+
+	return session.Watch(cmd.Context(), cmd.OutOrStdout())
+}
+
+// summarizeFiles computes the main totals of the analysis of a directory
+// from the results of its files.
+func summarizeFiles(files map[string]analyzer.FileAnalysis) []watch.Metric {
+	var lines, comments, classes, functions, methods int
+	var complexity, maxComplexity, complexFunctions int
+	var maintainability float64
+	dependencies := make(map[string]bool)
+
+	for _, file := range files {
+		lines += file.Lines.TotalLines
+		comments += file.Comments.CommentLines
+		classes += file.ClassesAndFunctions.Classes
+		functions += file.ClassesAndFunctions.Functions
+		methods += file.Methods.Public + file.Methods.Private
+		for _, function := range file.Complexity.Functions {
+			complexity += function.Complexity
+			maxComplexity = max(maxComplexity, function.Complexity)
+			complexFunctions++
+		}
+		maintainability += file.Maintainability.MaintainabilityIndex
+		for _, dependency := range file.Dependencies.Dependencies {
+			dependencies[dependency] = true
+		}
+	}
+
+	var commentPercentage, averageComplexity float64
+	if lines > 0 {
+		commentPercentage = float64(comments) / float64(lines) * 100
+	}
+	if complexFunctions > 0 {
+		averageComplexity = float64(complexity) / float64(complexFunctions)
+	}
+	if len(files) > 0 {
+		maintainability /= float64(len(files))
+	}
+
+	return []watch.Metric{
+		{Name: "Lines", Value: float64(lines)},
+		{Name: "Comment lines", Value: float64(comments)},
+		{Name: "Comment %", Value: commentPercentage},
+		{Name: "Classes", Value: float64(classes)},
+		{Name: "Functions", Value: float64(functions)},
+		{Name: "Methods", Value: float64(methods)},
+		{Name: "Average complexity", Value: averageComplexity},
+		{Name: "Max complexity", Value: float64(maxComplexity)},
+		{Name: "Maintainability index", Value: maintainability},
+		{Name: "Dependencies", Value: float64(len(dependencies))},
+	}
+}
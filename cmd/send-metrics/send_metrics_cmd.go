@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"go-cli-tool/internal/api"
+	"go-cli-tool/internal/results"
 	"go-cli-tool/internal/utils"
 	"os"
 	"strings"
@@ -46,7 +47,19 @@ var SendMetricsCmd = &cobra.Command{
         }
 
         formatter := &api.MetricsFormatter{}
-        payload := formatter.FormatMetrics(rawData)
+        var payload interface{}
+        // the reports of the versions of analyze before the versioned
+        // report are still accepted
+        if _, versioned := rawData["schema_version"]; versioned {
+            report, err := results.Read(jsonData)
+            if err != nil {
+                fmt.Fprintf(cmd.OutOrStdout(), "%sError parsing metrics JSON: %v%s\n", utils.RED, err, utils.RESET_COLOR)
+                return
+            }
+            payload = formatter.FormatReport(report)
+        } else {
+            payload = formatter.FormatMetrics(rawData)
+        }
 
         client := api.NewAPIClient(finalAPIURL, "")
 
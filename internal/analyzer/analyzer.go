@@ -1,5 +1,7 @@
 package analyzer
 
+import "go-cli-tool/internal/parser"
+
 type LineResult struct {
 	TotalLines int
 }
@@ -12,23 +14,31 @@ type CommentResult struct {
 type ClassFuncResult struct {
 	Functions int
 	Classes   int
+	// TypeScript type-only declarations
+	Interfaces  int
+	Enums       int
+	TypeAliases int
 }
 
 type CountLinesAnalyzer interface {
-	CountLinesByFilePath(filePath string) LineResult
-	CountLinesByDirectory(directoryPath string) (FilesNameCountLineMap, LineResult)
+	CountLinesByFilePath(filePath string) (LineResult, error)
+	CountLinesByDirectory(directoryPath string) (FilesNameCountLineMap, LineResult, error)
 }
 
 type ClassesAndFunctionsMap map[string]ClassFuncResult
 
+type FunctionsMap map[string][]parser.Function
+
 type CountClassesAndFunctionsAnalyzer interface {
-	CountClassesAndFunctionsByFilePath(filePath string) ClassFuncResult
-	CountClassesAndFunctionsByDirectory(directoryPath string) (ClassesAndFunctionsMap, ClassFuncResult)
+	CountClassesAndFunctionsByFilePath(filePath string) (ClassFuncResult, error)
+	CountClassesAndFunctionsByDirectory(directoryPath string) (ClassesAndFunctionsMap, ClassFuncResult, error)
+	ListFunctionsByFilePath(filePath string) ([]parser.Function, error)
+	ListFunctionsByDirectory(directoryPath string) (FunctionsMap, error)
 }
 
 type CountCommentsAnalyzer interface {
-	CountCommentsByFilePath(filePath string) CommentResult
-	CountCommentsByDirectory(directoryPath string) (CommentsMap, CommentResult)
+	CountCommentsByFilePath(filePath string) (CommentResult, error)
+	CountCommentsByDirectory(directoryPath string) (CommentsMap, CommentResult, error)
 }
 
 type CommentsMap map[string]CommentResult
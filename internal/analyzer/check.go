@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"fmt"
+	"go-cli-tool/internal/config"
+	"sort"
+)
+
+// Violation is a threshold exceeded by a file or one of its functions.
+type Violation struct {
+	Rule string `json:"rule"`
+	File string `json:"file"`
+	// Line is the line of the offending function, or 0 for a whole file.
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s: %s", v.File, v.Line, v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", v.File, v.Rule, v.Message)
+}
+
+type QualityGateAnalyzer interface {
+	CheckFile(filePath string, thresholds config.Thresholds) ([]Violation, error)
+	CheckDirectory(directoryPath string, thresholds config.Thresholds) ([]Violation, error)
+}
+
+type QualityGateAnalyzerImpl struct{}
+
+func (a *QualityGateAnalyzerImpl) CheckFile(filePath string, thresholds config.Thresholds) ([]Violation, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	report := func(rule string, line int, format string, args ...interface{}) {
+		violations = append(violations, Violation{
+			Rule:    rule,
+			File:    filePath,
+			Line:    line,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	complexities := cyclomaticComplexities(source.syntax)
+	nestings := functionNestings(source.syntax)
+
+	for i, function := range source.syntax.Functions {
+		name := displayName(function)
+
+		if limit := thresholds.MaxFunctionLines; limit > 0 && function.Lines() > limit {
+			report("function-size", function.StartLine, "%s has %d lines (max %d)", name, function.Lines(), limit)
+		}
+		if limit := thresholds.MaxComplexity; limit > 0 && complexities[i] > limit {
+			report("complexity", function.StartLine, "%s has a cyclomatic complexity of %d (max %d)", name, complexities[i], limit)
+		}
+		if limit := thresholds.MaxCognitiveComplexity; limit > 0 && nestings[i].CognitiveComplexity > limit {
+			report("cognitive-complexity", function.StartLine, "%s has a cognitive complexity of %d (max %d)", name, nestings[i].CognitiveComplexity, limit)
+		}
+		if limit := thresholds.MaxNestingDepth; limit > 0 && nestings[i].NestingDepth > limit {
+			report("nesting-depth", function.StartLine, "%s is nested %d levels deep (max %d)", name, nestings[i].NestingDepth, limit)
+		}
+	}
+
+	if limit := thresholds.MinCommentPercentage; limit > 0 && len(source.lines) > 0 {
+		percentage := float64(source.commentLines()) / float64(len(source.lines)) * 100
+		if percentage < limit {
+			report("comment-percentage", 0, "%.2f%% of the lines are comments (min %.2f%%)", percentage, limit)
+		}
+	}
+
+	if limit := thresholds.MinMaintainability; limit > 0 {
+		index := maintainabilityOf(source).MaintainabilityIndex
+		if index < limit {
+			report("maintainability", 0, "maintainability index of %.2f (min %.2f)", index, limit)
+		}
+	}
+
+	if limit := thresholds.MaxDependencies; limit > 0 {
+		if total := dependenciesOf(source).TotalDependencies; total > limit {
+			report("dependencies", 0, "%d external dependencies (max %d)", total, limit)
+		}
+	}
+
+	if !thresholds.AllowMixedIndentation {
+		indentationAnalyzer := &IdentationAnalyzerImpl{}
+		if indentationAnalyzer.calculateIndentationStats(source).MixedIndentation {
+			report("mixed-indentation", 0, "indentation mixes tabs and spaces")
+		}
+	}
+
+	return violations, nil
+}
+
+func (a *QualityGateAnalyzerImpl) CheckDirectory(directoryPath string, thresholds config.Thresholds) ([]Violation, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+
+	err = analyzeSourceFiles(directoryPath, func(path string) ([]Violation, error) {
+		return a.CheckFile(path, thresholds)
+	}, func(path, relativePath string, fileViolations []Violation) {
+		violations = append(violations, fileViolations...)
+	})
+
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, fatal
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+
+	return violations, err
+}
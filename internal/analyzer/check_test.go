@@ -0,0 +1,74 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFile(t *testing.T) {
+	content := `import express from 'express';
+import lodash from 'lodash';
+
+function route(request) {
+	if (request.a) {
+		if (request.b) {
+			return request.a && request.b || request.c;
+		}
+	}
+	return null;
+}
+
+function small() {
+    return 1;
+}
+`
+	filePath := filepath.Join(t.TempDir(), "route.js")
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	thresholds := config.Thresholds{
+		MaxFunctionLines: 5,
+		MaxComplexity:    3,
+		MaxNestingDepth:  1,
+		MaxDependencies:  1,
+	}
+
+	qualityGateAnalyzer := &analyzer.QualityGateAnalyzerImpl{}
+	violations, err := qualityGateAnalyzer.CheckFile(filePath, thresholds)
+	assert.NoError(t, err)
+
+	rules := make(map[string]analyzer.Violation)
+	for _, violation := range violations {
+		rules[violation.Rule] = violation
+	}
+
+	assert.Len(t, violations, 5)
+	assert.Equal(t, 4, rules["function-size"].Line)
+	assert.Equal(t, "route has a cyclomatic complexity of 5 (max 3)", rules["complexity"].Message)
+	assert.Equal(t, 4, rules["nesting-depth"].Line)
+	assert.Equal(t, "2 external dependencies (max 1)", rules["dependencies"].Message)
+	assert.Equal(t, filePath+": mixed-indentation: indentation mixes tabs and spaces", rules["mixed-indentation"].String())
+
+	thresholds = config.Thresholds{MaxComplexity: 5, AllowMixedIndentation: true}
+	violations, err = qualityGateAnalyzer.CheckFile(filePath, thresholds)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestCheckDirectory(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.js"), []byte("// documented\nconst b = 1;\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.js"), []byte("const a = 1;\nconst c = 2;\n"), 0644))
+
+	qualityGateAnalyzer := &analyzer.QualityGateAnalyzerImpl{}
+	violations, err := qualityGateAnalyzer.CheckDirectory(dir, config.Thresholds{MinCommentPercentage: 25})
+	assert.NoError(t, err)
+
+	assert.Len(t, violations, 1)
+	assert.Equal(t, filepath.Join(dir, "a.js"), violations[0].File)
+	assert.Equal(t, "comment-percentage", violations[0].Rule)
+}
@@ -0,0 +1,322 @@
+package analyzer
+
+import (
+	"go-cli-tool/internal/parser"
+	"go-cli-tool/internal/tokenizer"
+)
+
+// FunctionNesting holds the structural metrics of a single function.
+type FunctionNesting struct {
+	Name      string `json:"name"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	// NestingDepth is the deepest level of nested control structures and
+	// nested functions reached in the function body.
+	NestingDepth        int `json:"nestingDepth"`
+	CognitiveComplexity int `json:"cognitiveComplexity"`
+}
+
+// functionNestings measures the nesting depth and the cognitive complexity
+// of every function of the file, following the SonarSource specification:
+// branches and loops add one plus their nesting level, `else` and
+// sequences of mixed logical operators add one, and nested functions
+// raise the nesting level of the code inside them.
+func functionNestings(syntax *parser.File) []FunctionNesting {
+	bodies := make(map[int]int, len(syntax.Functions))
+	for i, function := range syntax.Functions {
+		bodies[function.BodyStart] = i
+	}
+
+	nestings := make([]FunctionNesting, 0, len(syntax.Functions))
+	for i, function := range syntax.Functions {
+		walker := &cognitiveWalker{syntax: syntax, bodies: bodies, function: i}
+		walker.body(i, 0)
+
+		nestings = append(nestings, FunctionNesting{
+			Name:                displayName(function),
+			StartLine:           function.StartLine,
+			EndLine:             function.EndLine,
+			NestingDepth:        walker.depth,
+			CognitiveComplexity: walker.score,
+		})
+	}
+
+	return nestings
+}
+
+type cognitiveWalker struct {
+	syntax   *parser.File
+	bodies   map[int]int // body start token → function index
+	function int         // index of the function being measured
+	current  int         // index of the function whose body is walked
+	score    int
+	depth    int
+}
+
+func (w *cognitiveWalker) token(k int) tokenizer.Token {
+	return w.syntax.Tokens[k]
+}
+
+func (w *cognitiveWalker) isKeyword(k int, value string) bool {
+	return k < len(w.syntax.Tokens) && w.token(k).Is(tokenizer.Keyword, value)
+}
+
+// body walks the body of the function with the given index at the given
+// nesting level.
+func (w *cognitiveWalker) body(index, nesting int) {
+	enclosing := w.current
+	w.current = index
+	defer func() { w.current = enclosing }()
+
+	function := w.syntax.Functions[index]
+	if w.syntax.MatchingBracket(function.BodyStart) == function.BodyEnd {
+		w.walk(function.BodyStart+1, function.BodyEnd, nesting)
+	} else {
+		// the bare expression body of an arrow function
+		w.walk(function.BodyStart, function.BodyEnd+1, nesting)
+	}
+}
+
+// walk scores the tokens between from and to, exclusive, found at the
+// given nesting level.
+func (w *cognitiveWalker) walk(from, to, nesting int) {
+	w.depth = max(w.depth, nesting)
+	logical := ""
+
+	for k := from; k < to; k++ {
+		token := w.token(k)
+
+		if index, ok := w.bodies[k]; ok && index != w.current {
+			w.body(index, nesting+1)
+			k = w.syntax.Functions[index].BodyEnd
+			logical = ""
+			continue
+		}
+
+		if k > 0 && (w.token(k-1).IsPunctuator(".") || w.token(k-1).IsPunctuator("?.")) {
+			continue
+		}
+
+		switch {
+		case token.Kind == tokenizer.Keyword:
+			switch token.Value {
+			case "if":
+				k = w.branch(k, to, nesting, false)
+			case "for", "while", "switch":
+				w.score += 1 + nesting
+				k = w.loop(k, to, nesting)
+			case "do":
+				w.score += 1 + nesting
+				k = w.do(k, to, nesting)
+			case "catch":
+				w.score += 1 + nesting
+				k = w.loop(k, to, nesting)
+			case "break", "continue":
+				// jumping to a label breaks the linear flow
+				if k+1 < to && w.token(k+1).Kind == tokenizer.Identifier && w.token(k+1).Line == token.Line {
+					w.score++
+				}
+			}
+			logical = ""
+		case token.Kind == tokenizer.Punctuator:
+			switch token.Value {
+			case "&&", "||", "??":
+				if token.Value != logical {
+					w.score++
+				}
+				logical = token.Value
+			case "?":
+				if k+1 >= to || !w.token(k+1).IsPunctuator(":") {
+					w.score += 1 + nesting
+				}
+				logical = ""
+			default:
+				logical = ""
+			}
+		case token.Kind == tokenizer.Identifier:
+			if w.isRecursiveCall(k) {
+				w.score++
+			}
+		}
+	}
+}
+
+// branch scores an if statement starting at index k together with its
+// else clauses and returns the index of its last token.
+func (w *cognitiveWalker) branch(k, to, nesting int, elseIf bool) int {
+	if elseIf {
+		w.score++
+	} else {
+		w.score += 1 + nesting
+	}
+
+	end := w.loop(k, to, nesting)
+	if !w.isKeyword(end+1, "else") || end+1 >= to {
+		return end
+	}
+
+	if w.isKeyword(end+2, "if") {
+		return w.branch(end+2, to, nesting, true)
+	}
+
+	w.score++
+	return w.statement(end+2, to, nesting+1)
+}
+
+// loop walks the parenthesized head of a control structure starting at
+// index k and its body, and returns the index of the last token.
+func (w *cognitiveWalker) loop(k, to, nesting int) int {
+	open := k + 1
+	if w.isKeyword(k, "for") && open < to && w.token(open).Is(tokenizer.Identifier, "await") {
+		open++
+	}
+	if open >= to || !w.token(open).IsPunctuator("(") {
+		// `catch {` without a binding
+		if open < to && w.token(open).IsPunctuator("{") {
+			return w.statement(open, to, nesting+1)
+		}
+		return k
+	}
+
+	close := w.syntax.MatchingBracket(open)
+	if close < 0 || close >= to {
+		return k
+	}
+	w.walk(open+1, close, nesting)
+
+	return w.statement(close+1, to, nesting+1)
+}
+
+// do walks a do-while loop starting at index k and returns the index of
+// its last token.
+func (w *cognitiveWalker) do(k, to, nesting int) int {
+	end := w.statement(k+1, to, nesting+1)
+	if !w.isKeyword(end+1, "while") || end+2 >= to || !w.token(end+2).IsPunctuator("(") {
+		return end
+	}
+
+	close := w.syntax.MatchingBracket(end + 2)
+	if close < 0 || close >= to {
+		return end
+	}
+	w.walk(end+3, close, nesting)
+	return close
+}
+
+// statement walks the statement starting at index k at the given nesting
+// level and returns the index of its last token.
+func (w *cognitiveWalker) statement(k, to, nesting int) int {
+	if k >= to {
+		return to - 1
+	}
+
+	end := w.statementEnd(k, to)
+	if w.token(k).IsPunctuator("{") && end == w.syntax.MatchingBracket(k) {
+		w.walk(k+1, end, nesting)
+	} else {
+		w.walk(k, end+1, nesting)
+	}
+	return end
+}
+
+// statementEnd returns the index of the last token of the statement that
+// starts at index k.
+func (w *cognitiveWalker) statementEnd(k, to int) int {
+	token := w.token(k)
+
+	if token.IsPunctuator("{") {
+		if end := w.syntax.MatchingBracket(k); end > k && end < to {
+			return end
+		}
+		return to - 1
+	}
+
+	if token.Kind == tokenizer.Keyword {
+		switch token.Value {
+		case "if", "for", "while", "with", "switch", "catch":
+			open := k + 1
+			if open < to && w.token(open).Is(tokenizer.Identifier, "await") {
+				open++
+			}
+			close := w.syntax.MatchingBracket(open)
+			if open >= to || !w.token(open).IsPunctuator("(") || close < 0 || close+1 >= to {
+				break
+			}
+			end := w.statementEnd(close+1, to)
+			if token.Value == "if" && w.isKeyword(end+1, "else") && end+2 < to {
+				return w.statementEnd(end+2, to)
+			}
+			return end
+		case "do":
+			end := w.statementEnd(k+1, to)
+			if w.isKeyword(end+1, "while") && end+2 < to {
+				if close := w.syntax.MatchingBracket(end + 2); close > 0 && close < to {
+					end = close
+				}
+			}
+			return end
+		case "try":
+			end := w.statementEnd(k+1, to)
+			for w.isKeyword(end+1, "catch") || w.isKeyword(end+1, "finally") {
+				if end+2 >= to {
+					break
+				}
+				end = w.statementEnd(end+1, to)
+			}
+			return end
+		}
+	}
+
+	// expression statement: up to the semicolon or an automatically
+	// inserted one at a line break
+	for end := k; end < to; end++ {
+		current := w.token(end)
+		if current.IsPunctuator(";") {
+			return end
+		}
+		if current.IsPunctuator(")") || current.IsPunctuator("]") || current.IsPunctuator("}") {
+			return end - 1
+		}
+		if end > k && current.Is(tokenizer.Keyword, "else") {
+			return end - 1
+		}
+		if end > k && current.Line > w.token(end-1).EndLine && endsExpression(w.token(end-1)) && startsStatement(current) {
+			return end - 1
+		}
+		if match := w.syntax.MatchingBracket(end); match > end && match < to {
+			end = match
+		}
+	}
+	return to - 1
+}
+
+// isRecursiveCall reports whether the identifier at index k calls the
+// function being measured.
+func (w *cognitiveWalker) isRecursiveCall(k int) bool {
+	function := w.syntax.Functions[w.function]
+	if function.Name == "" || w.token(k).Value != function.Name {
+		return false
+	}
+	return k+1 < len(w.syntax.Tokens) && w.token(k+1).IsPunctuator("(")
+}
+
+func endsExpression(token tokenizer.Token) bool {
+	switch token.Kind {
+	case tokenizer.Identifier, tokenizer.Number, tokenizer.String, tokenizer.Template, tokenizer.RegExp:
+		return true
+	case tokenizer.Keyword:
+		return token.Value == "this" || token.Value == "null" || token.Value == "true" || token.Value == "false"
+	}
+	return token.IsPunctuator(")") || token.IsPunctuator("]") || token.IsPunctuator("}") ||
+		token.IsPunctuator("++") || token.IsPunctuator("--")
+}
+
+func startsStatement(token tokenizer.Token) bool {
+	switch token.Kind {
+	case tokenizer.Identifier, tokenizer.Number, tokenizer.String, tokenizer.Template:
+		return true
+	case tokenizer.Keyword:
+		return token.Value != "else" && token.Value != "in" && token.Value != "instanceof"
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/utils"
+	"go-cli-tool/tests"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCognitiveComplexity(t *testing.T) {
+	content := `function sumOfPrimes(max) {
+  let total = 0;
+  OUT: for (let i = 1; i <= max; ++i) {
+    for (let j = 2; j < i; ++j) {
+      if (i % j == 0) {
+        continue OUT;
+      }
+    }
+    total += i;
+  }
+  return total;
+}
+
+function load(url) {
+  return fetch(url).then((res) => {
+    if (res.ok && res.status === 200 || res.redirected) {
+      return res.json();
+    } else if (res.status === 404) {
+      return [];
+    } else {
+      throw new Error('bad');
+    }
+  });
+}
+
+function fact(n) {
+  if (n <= 1) return 1
+  else return n * fact(n - 1)
+}
+`
+	filePath := filepath.Join(t.TempDir(), "cognitive.js")
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	tests.ResetGlobals()
+	utils.FilePath = filePath
+	defer tests.ResetGlobals()
+
+	indentationAnalyzer := &analyzer.IdentationAnalyzerImpl{}
+	results, err := indentationAnalyzer.IdentationByFilePath()
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Dir(filePath), results.Directory)
+	assert.Len(t, results.Files, 1)
+	assert.Equal(t, "cognitive.js", results.Files[0].Filename)
+	stats := results.Files[0].Stats
+	assert.Len(t, stats.Functions, 4)
+
+	metrics := make(map[string]analyzer.FunctionNesting)
+	for _, function := range stats.Functions {
+		metrics[function.Name] = function
+	}
+
+	assert.Equal(t, 7, metrics["sumOfPrimes"].CognitiveComplexity)
+	assert.Equal(t, 3, metrics["sumOfPrimes"].NestingDepth)
+	assert.Equal(t, 3, metrics["fact"].CognitiveComplexity)
+
+	// the branches of the callback are nested one level deeper in load
+	assert.Equal(t, 6, metrics["load"].CognitiveComplexity)
+	assert.Equal(t, 2, metrics["load"].NestingDepth)
+	assert.Equal(t, 5, metrics["<anonymous>"].CognitiveComplexity)
+
+	assert.Equal(t, 3, stats.MaxNestingDepth)
+	assert.Equal(t, 7, stats.MaxCognitiveComplexity)
+}
@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"go-cli-tool/internal/parser"
+	"go-cli-tool/internal/tokenizer"
+	"slices"
+	"sort"
+)
+
+// FunctionComplexity is the cyclomatic complexity of a single function.
+type FunctionComplexity struct {
+	Name       string `json:"name"`
+	File       string `json:"file"`
+	StartLine  int    `json:"startLine"`
+	EndLine    int    `json:"endLine"`
+	Complexity int    `json:"complexity"`
+}
+
+type ComplexityResult struct {
+	Functions []FunctionComplexity `json:"functions"`
+	Average   float64              `json:"average"`
+	Max       int                  `json:"max"`
+}
+
+type ComplexityMap map[string]ComplexityResult
+
+type ComplexityAnalyzer interface {
+	CalculateComplexity(filePath string) (ComplexityResult, error)
+	CalculateComplexityByDirectory(directoryPath string) (ComplexityMap, ComplexityResult, error)
+}
+
+type ComplexityAnalyzerImpl struct{}
+
+// Worst returns up to n functions with the highest complexity, the most
+// complex first.
+func (r ComplexityResult) Worst(n int) []FunctionComplexity {
+	worst := slices.Clone(r.Functions)
+	sort.SliceStable(worst, func(i, j int) bool {
+		return worst[i].Complexity > worst[j].Complexity
+	})
+
+	if n >= 0 && len(worst) > n {
+		worst = worst[:n]
+	}
+	return worst
+}
+
+func (a *ComplexityAnalyzerImpl) CalculateComplexity(filePath string) (ComplexityResult, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return ComplexityResult{}, err
+	}
+
+	return complexityOf(source), nil
+}
+
+// complexityOf computes the cyclomatic complexity of every function of a
+// file.
+func complexityOf(source *sourceFile) ComplexityResult {
+	complexities := cyclomaticComplexities(source.syntax)
+
+	functions := make([]FunctionComplexity, 0, len(complexities))
+	for i, function := range source.syntax.Functions {
+		functions = append(functions, FunctionComplexity{
+			Name:       displayName(function),
+			File:       source.path,
+			StartLine:  function.StartLine,
+			EndLine:    function.EndLine,
+			Complexity: complexities[i],
+		})
+	}
+
+	return summarizeComplexity(functions)
+}
+
+func (a *ComplexityAnalyzerImpl) CalculateComplexityByDirectory(directoryPath string) (ComplexityMap, ComplexityResult, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return nil, ComplexityResult{}, err
+	}
+
+	results := make(ComplexityMap)
+	var allFunctions []FunctionComplexity
+
+	err = analyzeSourceFiles(directoryPath, a.CalculateComplexity, func(path, relativePath string, result ComplexityResult) {
+		result = result.inFile(relativePath)
+		results[relativePath] = result
+		allFunctions = append(allFunctions, result.Functions...)
+	})
+
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, ComplexityResult{}, fatal
+	}
+
+	return results, summarizeComplexity(allFunctions), err
+}
+
+// inFile sets the file the functions of the result are reported in.
+func (result ComplexityResult) inFile(path string) ComplexityResult {
+	for i := range result.Functions {
+		result.Functions[i].File = path
+	}
+	return result
+}
+
+// displayName returns the name a function is reported under, qualified by
+// its class.
+func displayName(function parser.Function) string {
+	name := function.Name
+	if name == "" {
+		name = "<anonymous>"
+	}
+	if function.Class != "" {
+		name = function.Class + "." + name
+	}
+	return name
+}
+
+func summarizeComplexity(functions []FunctionComplexity) ComplexityResult {
+	result := ComplexityResult{Functions: functions}
+	if len(functions) == 0 {
+		return result
+	}
+
+	total := 0
+	for _, function := range functions {
+		total += function.Complexity
+		result.Max = max(result.Max, function.Complexity)
+	}
+	result.Average = float64(total) / float64(len(functions))
+
+	return result
+}
+
+// cyclomaticComplexities returns the cyclomatic complexity of every
+// function of the file, in the order of File.Functions: one plus the
+// number of decision points in the function's own body. Decision points
+// inside nested functions count for the nested function only.
+func cyclomaticComplexities(syntax *parser.File) []int {
+	complexities := make([]int, len(syntax.Functions))
+	for i := range complexities {
+		complexities[i] = 1
+	}
+
+	owners := functionOwners(syntax)
+	for k := range syntax.Tokens {
+		if owners[k] >= 0 && isDecisionPoint(syntax.Tokens, k) {
+			complexities[owners[k]]++
+		}
+	}
+
+	return complexities
+}
+
+// functionOwners maps every token of the file to the index of the
+// innermost function whose body contains it, or -1 for top-level code.
+func functionOwners(syntax *parser.File) []int {
+	owners := make([]int, len(syntax.Tokens))
+	for k := range owners {
+		owners[k] = -1
+	}
+
+	// functions are stored in pre-order, so nested bodies are assigned
+	// after the bodies enclosing them
+	for i, function := range syntax.Functions {
+		for k := function.BodyStart; k <= function.BodyEnd && k < len(owners); k++ {
+			owners[k] = i
+		}
+	}
+
+	return owners
+}
+
+// isDecisionPoint reports whether the token at index k adds a path through
+// the code: a branch, a loop, a case clause, a catch, a conditional
+// expression or a short-circuit operator.
+func isDecisionPoint(tokens []tokenizer.Token, k int) bool {
+	token := tokens[k]
+	if k > 0 && (tokens[k-1].IsPunctuator(".") || tokens[k-1].IsPunctuator("?.")) {
+		return false
+	}
+
+	switch token.Kind {
+	case tokenizer.Keyword:
+		switch token.Value {
+		case "if", "for", "while", "case", "catch":
+			return true
+		}
+	case tokenizer.Punctuator:
+		switch token.Value {
+		case "&&", "||", "??", "&&=", "||=", "??=":
+			return true
+		case "?":
+			// `name?: Type` marks an optional TypeScript member
+			return k+1 >= len(tokens) || !tokens[k+1].IsPunctuator(":")
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateComplexity(t *testing.T) {
+	content := `function classify(value) {
+	if (value > 10 && value < 100) {
+		return 'medium';
+	} else if (value >= 100) {
+		return 'large';
+	}
+
+	for (const item of items) {
+		try {
+			handle(item ?? fallback);
+		} catch (error) {
+			report(error);
+		}
+	}
+
+	switch (value) {
+		case 1:
+		case 2:
+			return 'tiny';
+		default:
+			return value ? 'small' : 'zero';
+	}
+}
+
+function straight() {
+	return [1, 2].map(n => n > 1 || n < 0);
+}
+`
+
+	tmpFile := filepath.Join(t.TempDir(), "complexity.js")
+	assert.NoError(t, os.WriteFile(tmpFile, []byte(content), 0644))
+
+	complexityAnalyzer := &analyzer.ComplexityAnalyzerImpl{}
+	result, err := complexityAnalyzer.CalculateComplexity(tmpFile)
+	assert.NoError(t, err)
+
+	if !assert.Len(t, result.Functions, 3) {
+		return
+	}
+
+	// if, &&, else if, for, ??, catch, two cases and the ternary
+	assert.Equal(t, 10, result.Functions[0].Complexity, "Expected complexity 10 for classify")
+	assert.Equal(t, 1, result.Functions[1].Complexity, "Expected the nested arrow not to add to straight")
+	assert.Equal(t, 2, result.Functions[2].Complexity, "Expected complexity 2 for the arrow")
+
+	assert.Equal(t, 10, result.Max)
+	assert.InDelta(t, 13.0/3.0, result.Average, 0.0001)
+
+	worst := result.Worst(1)
+	if assert.Len(t, worst, 1) {
+		assert.Equal(t, "classify", worst[0].Name)
+		assert.Equal(t, 1, worst[0].StartLine)
+	}
+}
+
+func TestCalculateComplexityByDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"a.js": "function a(x) { return x ? 1 : 2; }\n",
+		"b.ts": "export function b(x: number): number {\n\tif (x) { return 1; }\n\twhile (x--) {}\n\treturn 0;\n}\n",
+	}
+	for fileName, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, fileName), []byte(content), 0644))
+	}
+
+	complexityAnalyzer := &analyzer.ComplexityAnalyzerImpl{}
+	results, total, err := complexityAnalyzer.CalculateComplexityByDirectory(tmpDir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, results["a.js"].Max)
+	assert.Equal(t, 3, results["b.ts"].Max)
+	assert.Equal(t, 3, total.Max)
+	assert.InDelta(t, 2.5, total.Average, 0.0001)
+	assert.Len(t, total.Functions, 2)
+}
@@ -0,0 +1,81 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentFilesAreAnalyzed(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"List.jsx": `import React from 'react';
+
+export function List({ items }) {
+	return (
+		<ul className="list">
+			{/* one entry per item */}
+			{items.map(item => <li key={item.id}>{item.name} {"}"}</li>)}
+		</ul>
+	);
+}
+`,
+		"Counter.vue": `<template>
+	<button @click="increment">{{ count }}</button>
+</template>
+
+<script setup lang="ts">
+import { ref } from 'vue';
+// reactive state
+const count = ref<number>(0);
+function increment(): void {
+	count.value++;
+}
+</script>
+`,
+		"Title.svelte": `<script>
+	export let title = '';
+	const shout = () => title.toUpperCase();
+</script>
+
+<h1>{title}</h1>
+`,
+	}
+
+	for fileName, content := range files {
+		err := os.WriteFile(filepath.Join(tmpDir, fileName), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	classFuncAnalyzer := &analyzer.CountClassAndFunctionsImpl{}
+	results, _, err := classFuncAnalyzer.CountClassesAndFunctionsByDirectory(tmpDir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, results["List.jsx"].Functions, "Expected braces in markup not to be counted as functions")
+	assert.Equal(t, 1, results["Counter.vue"].Functions, "Expected the script block of the Vue component to be analyzed")
+	assert.Equal(t, 1, results["Title.svelte"].Functions, "Expected the script block of the Svelte component to be analyzed")
+
+	functions, err := classFuncAnalyzer.ListFunctionsByFilePath(filepath.Join(tmpDir, "Counter.vue"))
+	assert.NoError(t, err)
+	if assert.Len(t, functions, 1) {
+		assert.Equal(t, "increment", functions[0].Name)
+		assert.Equal(t, 9, functions[0].StartLine, "Expected line numbers of the original file")
+		assert.Equal(t, 11, functions[0].EndLine, "Expected line numbers of the original file")
+	}
+
+	commentAnalyzer := &analyzer.CountCommentsAnalyzerImpl{}
+	for _, name := range []string{"List.jsx", "Counter.vue"} {
+		comments, err := commentAnalyzer.CountCommentsByFilePath(filepath.Join(tmpDir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, comments.CommentLines)
+	}
+
+	lineAnalyzer := &analyzer.CountLinesAnalyzerImpl{}
+	lines, err := lineAnalyzer.CountLinesByFilePath(filepath.Join(tmpDir, "Counter.vue"))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, lines.TotalLines, "Expected only script lines to be counted")
+}
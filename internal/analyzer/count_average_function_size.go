@@ -1,137 +1,90 @@
 package analyzer
 
 import (
-	"bufio"
-	"go-cli-tool/internal/policies"
-	"go-cli-tool/internal/utils"
-	"io/fs"
-	"os"
-	"path/filepath"
-	"slices"
-	"strings"
+	"go-cli-tool/internal/parser"
 )
 
 type AverageFunctionAnalyzer interface {
-	CalculateAverageFunctionSize(filePath string) float64
-	CalculateAverageFunctionSizeByDirectory(directoryPath string) (map[string]float64, float64)
+	CalculateAverageFunctionSize(filePath string) (float64, error)
+	CalculateAverageFunctionSizeByDirectory(directoryPath string) (map[string]float64, float64, error)
+	OversizedFunctions(filePath string, maxLines int) ([]parser.Function, error)
 }
 
 type AverageFunctionAnalyzerImpl struct{}
 
-func (a *AverageFunctionAnalyzerImpl) CalculateAverageFunctionSize(filePath string) float64 {
-	file, err := os.Open(filePath)
+func (a *AverageFunctionAnalyzerImpl) CalculateAverageFunctionSize(filePath string) (float64, error) {
+	source, err := loadSourceFile(filePath)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return averageFunctionSize(source), nil
+}
 
-	inFunction := false
-	bracesCount := 0
-	currentFunctionLines := 0
-	totalFunctionLines := 0
-	functionCount := 0
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Verifica início de função
-		if !inFunction && isFunctionStart(line) {
-			inFunction = true
-			bracesCount = strings.Count(line, "{") - strings.Count(line, "}")
-			currentFunctionLines = 1
-			if bracesCount <= 0 {
-				inFunction = false
-				totalFunctionLines += currentFunctionLines
-				functionCount++
-			}
-			continue
-		}
+// averageFunctionSize returns the average number of lines of the
+// functions of a file, or 0 without functions.
+func averageFunctionSize(source *sourceFile) float64 {
+	functions := source.syntax.Functions
 
-		if inFunction {
-			currentFunctionLines++
-			bracesCount += strings.Count(line, "{") - strings.Count(line, "}")
+	if len(functions) == 0 {
+		return 0.0
+	}
 
-			if bracesCount <= 0 {
-				inFunction = false
-				totalFunctionLines += currentFunctionLines
-				functionCount++
-			}
-		}
+	totalFunctionLines := 0
+	for _, function := range functions {
+		totalFunctionLines += function.Lines()
 	}
 
-	if functionCount == 0 {
-		return 0.0
+	return float64(totalFunctionLines) / float64(len(functions))
+}
+
+// OversizedFunctions returns the functions of a file spanning more than
+// maxLines lines.
+func (a *AverageFunctionAnalyzerImpl) OversizedFunctions(filePath string, maxLines int) ([]parser.Function, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return nil, err
 	}
-	return float64(totalFunctionLines) / float64(functionCount)
+
+	return oversizedFunctions(source, maxLines), nil
 }
 
-func (a *AverageFunctionAnalyzerImpl) CalculateAverageFunctionSizeByDirectory(directoryPath string) (map[string]float64, float64) {
-	if directoryPath == "." {
-		var err error
-		directoryPath, err = os.Getwd()
-		if err != nil {
-			panic(err)
-		}
-	} else {
-		var err error
-		directoryPath, err = utils.ExpandPath(directoryPath)
-		if err != nil {
-			panic(err)
+func oversizedFunctions(source *sourceFile, maxLines int) []parser.Function {
+	var oversized []parser.Function
+	for _, function := range source.syntax.Functions {
+		if function.Lines() > maxLines {
+			oversized = append(oversized, function)
 		}
 	}
 
-	results := make(map[string]float64)
-	var totalSum float64
-	var fileCount int
+	return oversized
+}
 
-	// Evita repetição e conflitos entre arquivos
-	var directoryOrFilesToIgnore = []string{
-		"node_modules", ".git", "dist", "build", "coverage", "vendor",
+func (a *AverageFunctionAnalyzerImpl) CalculateAverageFunctionSizeByDirectory(directoryPath string) (map[string]float64, float64, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	err := filepath.WalkDir(directoryPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		fileName := d.Name()
-		if slices.Contains(directoryOrFilesToIgnore, fileName) {
-			if d.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+	results := make(map[string]float64)
+	var totalSum float64
+	var fileCount int
 
-		if !d.IsDir() && policies.IsJSFileExtension(filepath.Ext(fileName)) {
-			average := a.CalculateAverageFunctionSize(path)
-			results[fileName] = average
-			if average > 0 {
-				totalSum += average
-				fileCount++
-			}
+	err = analyzeSourceFiles(directoryPath, a.CalculateAverageFunctionSize, func(path, relativePath string, average float64) {
+		results[relativePath] = average
+		if average > 0 {
+			totalSum += average
+			fileCount++
 		}
-
-		return nil
 	})
 
-	if err != nil {
-		panic(err)
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, 0, fatal
 	}
 
 	var overallAverage float64
 	if fileCount > 0 {
 		overallAverage = totalSum / float64(fileCount)
 	}
-	return results, overallAverage
-}
-
-func isFunctionStart(line string) bool {
-	// suporta function normal, arrow e métodos
-	return strings.HasPrefix(line, "function") ||
-		strings.Contains(line, "=>") ||
-		strings.HasPrefix(line, "async function") ||
-		strings.HasSuffix(line, "{") &&
-			(strings.Contains(line, "(") && strings.Contains(line, ")"))
+	return results, overallAverage, err
 }
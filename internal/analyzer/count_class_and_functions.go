@@ -1,102 +1,91 @@
 package analyzer
 
 import (
-	"bufio"
-	"fmt"
-	"go-cli-tool/internal/policies"
-	"go-cli-tool/internal/utils"
-	"io/fs"
-	"os"
-	"path/filepath"
-	"regexp"
-	"slices"
-	"strings"
+	"go-cli-tool/internal/parser"
 )
 
-const FUNCTIONS_REGEX = `(?m)^\s*(const|let|var)?\s*(\w+\s*=\s*)?(function\s*\w*\s*\([^)]*\)\s*{|\([^)]*\)\s*=>\s*{|\w+\s*\([^)]*\)\s*{)|\([^)]*\)\s*=>\s*{|function\s*\([^)]*\)\s*{`
-
 type CountClassAndFunctionsImpl struct {}
 
-func (a *CountClassAndFunctionsImpl) CountClassesAndFunctionsByFilePath(filePath string) ClassFuncResult {
-	file, err := os.Open(filePath)
+func (a *CountClassAndFunctionsImpl) CountClassesAndFunctionsByFilePath(filePath string) (ClassFuncResult, error) {
+	source, err := loadSourceFile(filePath)
 	if err != nil {
-		panic(err)
-	}
-	defer file.Close()
-
-	var result ClassFuncResult
-	var fileContent strings.Builder
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		fileContent.WriteString(scanner.Text() + "\n")
+		return ClassFuncResult{}, err
 	}
 
-	content := fileContent.String()
+	return countClassesAndFunctions(source), nil
+}
 
-    functionRegex := regexp.MustCompile(FUNCTIONS_REGEX)
+// countClassesAndFunctions counts the functions, classes and TypeScript
+// type declarations of a file.
+func countClassesAndFunctions(source *sourceFile) ClassFuncResult {
+	var result ClassFuncResult
 
-	classRegex := regexp.MustCompile(`(?m)^\s*class\s+\w+\s*{`)
+	result.Functions = len(source.syntax.Functions)
 
-	result.Functions = len(functionRegex.FindAllString(content, -1))
+	result.Classes = len(source.syntax.Classes)
 
-	result.Classes = len(classRegex.FindAllString(content, -1))
+	result.Interfaces = source.syntax.CountTypes(parser.Interface)
+	result.Enums = source.syntax.CountTypes(parser.Enum)
+	result.TypeAliases = source.syntax.CountTypes(parser.TypeAlias)
 
 	return result
 }
 
-func (a *CountClassAndFunctionsImpl) CountClassesAndFunctionsByDirectory(directoryPath string) (ClassesAndFunctionsMap, ClassFuncResult) {
-    if directoryPath == "." {
-        var err error
-        directoryPath, err = os.Getwd()
-        if err != nil {
-            panic(err)
-        }
-    } else {
-        var err error
-        directoryPath, err = utils.ExpandPath(directoryPath)
-        if err != nil {
-            panic(err)
-        }
-    }
-
-    if _, err := os.Stat(directoryPath); os.IsNotExist(err) {
-        panic(fmt.Sprintf("directory %s does not exist", directoryPath))
+func (a *CountClassAndFunctionsImpl) CountClassesAndFunctionsByDirectory(directoryPath string) (ClassesAndFunctionsMap, ClassFuncResult, error) {
+    directoryPath, err := directoryRoot(directoryPath)
+    if err != nil {
+        return nil, ClassFuncResult{}, err
     }
 
     linesByArchive := make(ClassesAndFunctionsMap)
 
-    err := filepath.WalkDir(directoryPath, func(path string, directory fs.DirEntry, err error) error {
-        if err != nil {
-            return err
-        }
-
-        fileOrDirectoryName := directory.Name()
-        fileExtension := filepath.Ext(fileOrDirectoryName)
-
-        if slices.Contains(directoryOrFilesToIgnore, fileOrDirectoryName) {
-            if directory.IsDir() {
-                return filepath.SkipDir
-            }
-            return nil
-        }
-
-        if policies.IsJSFileExtension(fileExtension) {
-            linesByArchive[fileOrDirectoryName] = a.CountClassesAndFunctionsByFilePath(path)
-        }
-
-        return nil
+    err = analyzeSourceFiles(directoryPath, a.CountClassesAndFunctionsByFilePath, func(path, relativePath string, result ClassFuncResult) {
+        linesByArchive[relativePath] = result
     })
 
-    if err != nil {
-        panic(err)
+    if _, fatal := Skipped(err); fatal != nil {
+        return nil, ClassFuncResult{}, fatal
     }
 
     var totalClassesAndFunctions ClassFuncResult
     for _, result := range linesByArchive {
         totalClassesAndFunctions.Classes += result.Classes
         totalClassesAndFunctions.Functions += result.Functions
+        totalClassesAndFunctions.Interfaces += result.Interfaces
+        totalClassesAndFunctions.Enums += result.Enums
+        totalClassesAndFunctions.TypeAliases += result.TypeAliases
     }
 
-    return linesByArchive, totalClassesAndFunctions
-}
\ No newline at end of file
+    return linesByArchive, totalClassesAndFunctions, err
+}
+
+// ListFunctionsByFilePath returns the per-function records of a file.
+func (a *CountClassAndFunctionsImpl) ListFunctionsByFilePath(filePath string) ([]parser.Function, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.syntax.Functions, nil
+}
+
+// ListFunctionsByDirectory returns the per-function records of every
+// JavaScript file in a directory.
+func (a *CountClassAndFunctionsImpl) ListFunctionsByDirectory(directoryPath string) (FunctionsMap, error) {
+    directoryPath, err := directoryRoot(directoryPath)
+    if err != nil {
+        return nil, err
+    }
+
+    functionsByArchive := make(FunctionsMap)
+
+    err = analyzeSourceFiles(directoryPath, a.ListFunctionsByFilePath, func(path, relativePath string, functions []parser.Function) {
+        functionsByArchive[relativePath] = functions
+    })
+
+    if _, fatal := Skipped(err); fatal != nil {
+        return nil, fatal
+    }
+
+    return functionsByArchive, err
+}
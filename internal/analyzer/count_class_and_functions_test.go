@@ -48,7 +48,8 @@ func TestCountClassesAndFunctionsByFilePath(t *testing.T) {
     analyzer := &analyzer.CountClassAndFunctionsImpl{}
 
     // Call the function to test
-    result := analyzer.CountClassesAndFunctionsByFilePath(tmpFile.Name())
+    result, err := analyzer.CountClassesAndFunctionsByFilePath(tmpFile.Name())
+    assert.NoError(t, err)
 
     // Assert the results
     assert.Equal(t, 1, result.Classes, "Expected 1 class")
@@ -93,7 +94,8 @@ func TestCountClassesAndFunctionsByDirectory(t *testing.T) {
     analyzer := &analyzer.CountClassAndFunctionsImpl{}
 
     // Call the function to test
-    linesByArchive, totalClassesAndFunctions := analyzer.CountClassesAndFunctionsByDirectory(tmpDir)
+    linesByArchive, totalClassesAndFunctions, err := analyzer.CountClassesAndFunctionsByDirectory(tmpDir)
+    assert.NoError(t, err)
 
     // Assert the results for each file
     assert.Equal(t, 1, linesByArchive["file1.js"].Classes, "Expected 1 class in file1.js")
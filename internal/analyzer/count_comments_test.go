@@ -0,0 +1,43 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountCommentsByFilePathIgnoresCommentsInsideLiterals(t *testing.T) {
+	content := `// real comment
+const url = 'http://example.com'; // trailing comment on a code line
+const re = /\/\/api/;
+const text = ` + "`" + `
+// not a comment inside a template
+` + "`" + `;
+/**
+ * Block comments count every line.
+ */
+function noop() {}
+`
+
+	tmpFile, err := os.CreateTemp("", "test*.js")
+	assert.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(content)
+	assert.NoError(t, err)
+	tmpFile.Close()
+
+	commentAnalyzer := &analyzer.CountCommentsAnalyzerImpl{}
+	percentAnalyzer := &analyzer.CountPercentAnalyzerImpl{}
+
+	comments, err := commentAnalyzer.CountCommentsByFilePath(tmpFile.Name())
+	assert.NoError(t, err)
+	percent, err := percentAnalyzer.CountPercentByFilePath(tmpFile.Name())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, comments.CommentLines, "Expected 4 comment lines")
+	assert.Equal(t, comments.CommentLines, percent.CommentLines, "count-comments and count-percent must agree")
+	assert.Equal(t, 10, percent.TotalLines, "Expected 10 lines")
+}
@@ -1,37 +1,26 @@
 package analyzer
 
 import (
-	"bufio"
-	"fmt"
-
-	// "fmt"
-	"go-cli-tool/internal/policies"
-	"go-cli-tool/internal/utils"
-	"io/fs"
-	"os"
-	"path/filepath"
-	"slices"
 )
 type FilesNameCountLineMap map[string]LineResult
-var directoryOrFilesToIgnore = []string{".git", "node_modules"}
 
 type CountLinesAnalyzerImpl struct{}
 
-func (a *CountLinesAnalyzerImpl) CountLinesByFilePath(filePath string) LineResult {
-    file, err := os.Open(filePath)
+func (a *CountLinesAnalyzerImpl) CountLinesByFilePath(filePath string) (LineResult, error) {
+    source, err := loadSourceFile(filePath)
 
     if err != nil {
-        panic(err)
+        return LineResult{}, err
     }
 
-    defer file.Close()
+    return countLines(source), nil
+}
 
+// countLines counts the non-blank lines of a file.
+func countLines(source *sourceFile) LineResult {
     var result LineResult
-    scanner := bufio.NewScanner(file)
-
-    for scanner.Scan() {
 
-        line := scanner.Text()
+    for _, line := range source.lines {
 
         if isEmptyLine(line) {
             continue
@@ -43,53 +32,21 @@ func (a *CountLinesAnalyzerImpl) CountLinesByFilePath(filePath string) LineResul
     return result
 }
 
-func (a *CountLinesAnalyzerImpl) CountLinesByDirectory(directoryPath string) (FilesNameCountLineMap, LineResult) {
-    if directoryPath == "." {
-        var err error
-        directoryPath, err = os.Getwd()
-        if err != nil {
-            panic(err)
-        }
-    } else {
-        var err error
-        directoryPath, err = utils.ExpandPath(directoryPath)
-        if err != nil {
-            panic(err)
-        }
-    }
-
-    // Verificar se o diretório existe
-    if _, err := os.Stat(directoryPath); os.IsNotExist(err) {
-        panic(fmt.Sprintf("directory %s does not exist", directoryPath))
+func (a *CountLinesAnalyzerImpl) CountLinesByDirectory(directoryPath string) (FilesNameCountLineMap, LineResult, error) {
+    directoryPath, err := directoryRoot(directoryPath)
+    if err != nil {
+        return nil, LineResult{}, err
     }
 
     linesByArchive := make(FilesNameCountLineMap)
     var totalLinesByDirectory LineResult
 
-    err := filepath.WalkDir(directoryPath, func(path string, directory fs.DirEntry, err error) error {
-        if err != nil {
-            return err
-        }
-
-        fileOrDirectoryName := directory.Name()
-        fileExtension := filepath.Ext(fileOrDirectoryName)
-
-        if slices.Contains(directoryOrFilesToIgnore, fileOrDirectoryName) {
-            if directory.IsDir() {
-                return filepath.SkipDir
-            }
-            return nil
-        }
-
-        if policies.IsJSFileExtension(fileExtension) {
-            linesByArchive[fileOrDirectoryName] = a.CountLinesByFilePath(path)
-        }
-
-        return nil
+    err = analyzeSourceFiles(directoryPath, a.CountLinesByFilePath, func(path, relativePath string, result LineResult) {
+        linesByArchive[relativePath] = result
     })
 
-    if err != nil {
-        panic(err)
+    if _, fatal := Skipped(err); fatal != nil {
+        return nil, LineResult{}, fatal
     }
 
     for result := range linesByArchive {
@@ -97,7 +54,7 @@ func (a *CountLinesAnalyzerImpl) CountLinesByDirectory(directoryPath string) (Fi
         totalLinesByDirectory.TotalLines += file.TotalLines
     }
 
-    return linesByArchive, totalLinesByDirectory
+    return linesByArchive, totalLinesByDirectory, err
 }
 
 func isEmptyLine(line string) bool {
@@ -2,6 +2,7 @@ package analyzer_test
 
 import (
 	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/config"
 	"os"
 	"path/filepath"
 	"testing"
@@ -35,7 +36,8 @@ func TestCountLinesByFilePath(t *testing.T) {
 
     analyzer := &analyzer.CountLinesAnalyzerImpl{}
 
-    result := analyzer.CountLinesByFilePath(tmpFile.Name())
+    result, err := analyzer.CountLinesByFilePath(tmpFile.Name())
+    assert.NoError(t, err)
 
     assert.Equal(t, 7, result.TotalLines, "Expected 1 class")
 
@@ -76,7 +78,8 @@ func TestCountLinesByDirectory(t *testing.T) {
     analyzer := &analyzer.CountLinesAnalyzerImpl{}
 
 
-    linesByArchive, totalLines := analyzer.CountLinesByDirectory(tmpDir)
+    linesByArchive, totalLines, err := analyzer.CountLinesByDirectory(tmpDir)
+    assert.NoError(t, err)
 
     assert.Equal(t, 5, linesByArchive["file1.js"].TotalLines, "Expected 7 lines in file1.js")
     assert.Equal(t, 5, linesByArchive["file2.js"].TotalLines, "Expected 7 lines in file2.js")
@@ -104,7 +107,8 @@ func TestCountLinesByDirectoryToSkipFileNamedWithDirectoryToIgnore(t *testing.T)
 
     analyzer := &analyzer.CountLinesAnalyzerImpl{}
 
-    linesByArchive, totalLines := analyzer.CountLinesByDirectory(tmpDir)
+    linesByArchive, totalLines, err := analyzer.CountLinesByDirectory(tmpDir)
+    assert.NoError(t, err)
 
     assert.Equal(t, 0, linesByArchive[".git"].TotalLines, "Expected 0 lines in .git")
     assert.Equal(t, 0, linesByArchive["node_modules"].TotalLines, "Expected 0 lines in node_modules")
@@ -130,10 +134,75 @@ func TestCountLinesByDirectoryToSkipDirectoryToIgnore(t *testing.T) {
 
     analyzer := &analyzer.CountLinesAnalyzerImpl{}
 
-    linesByArchive, totalLines := analyzer.CountLinesByDirectory(parentDir)
+    linesByArchive, totalLines, err := analyzer.CountLinesByDirectory(parentDir)
+    assert.NoError(t, err)
 
     for _, dirName := range ignoredDirs {
         assert.Equal(t, 0, linesByArchive[dirName].TotalLines, "Expected 0 lines in "+dirName)
     }
     assert.Equal(t, 0, totalLines.TotalLines, "Expected to skip all files")
-}
\ No newline at end of file
+}
+func TestCountLinesByDirectoryHonorsIgnoreFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":            "*.min.js\nbundles/\n",
+		"src/.gocliignore":      "/generated.js\n",
+		"src/app.js":            "const a = 1;\n",
+		"src/generated.js":      "const g = 1;\n",
+		"src/vendor.min.js":     "const v = 1;\n",
+		"bundles/main.js":       "const m = 1;\n",
+		"lib/generated.js":      "const l = 1;\n",
+		"node_modules/dep/a.js": "const d = 1;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	analyzer := &analyzer.CountLinesAnalyzerImpl{}
+	result, total, err := analyzer.CountLinesByDirectory(tmpDir)
+	assert.NoError(t, err)
+
+	assert.Contains(t, result, "src/app.js")
+	assert.Contains(t, result, "lib/generated.js", "Expected lib/generated.js to be analyzed")
+	assert.NotContains(t, result, "src/generated.js")
+	assert.NotContains(t, result, "src/vendor.min.js")
+	assert.NotContains(t, result, "bundles/main.js")
+	assert.NotContains(t, result, "node_modules/dep/a.js")
+	assert.Equal(t, 2, total.TotalLines)
+}
+
+func TestCountLinesByDirectoryRestrictedToSelectedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"src/app.js":       "const a = 1;\nconst b = 2;\n",
+		"src/unchanged.js": "const u = 1;\n",
+		"lib/util.js":      "const l = 1;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	resolved, err := filepath.EvalSymlinks(tmpDir)
+	assert.NoError(t, err)
+
+	defer func(settings config.Config) { config.Active = settings }(config.Active)
+	config.Active.Files = []string{
+		filepath.Join(resolved, "src", "app.js"),
+		filepath.Join(resolved, "deleted.js"),
+		"/elsewhere/other.js",
+	}
+
+	analyzer := &analyzer.CountLinesAnalyzerImpl{}
+	result, total, err := analyzer.CountLinesByDirectory(tmpDir)
+	assert.NoError(t, err)
+
+	assert.Len(t, result, 1)
+	assert.Contains(t, result, "src/app.js")
+	assert.Equal(t, 2, total.TotalLines)
+}
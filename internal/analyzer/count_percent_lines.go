@@ -1,17 +1,12 @@
 package analyzer
 
 import (
-	"bufio"
-	"fmt"
-	"os"
 	"path/filepath"
 )
 
-var ignoreList = []string{"node_modules", ".git", "dist", "build", ".DS_Store"}
-
 type CountPercentAnalyzer interface {
-	CountPercentByFilePath(filePath string) PercentResult
-	CountCommentsByDirectory(directoryPath string) (PercentResultMap, PercentResult)
+	CountPercentByFilePath(filePath string) (PercentResult, error)
+	CountCommentsByDirectory(directoryPath string) (PercentResultMap, PercentResult, error)
 }
 
 type CountPercentAnalyzerImpl struct{}
@@ -24,34 +19,21 @@ type PercentResult struct {
 
 type PercentResultMap map[string]PercentResult
 
-func shouldIgnore(name string) bool {
-	for _, item := range ignoreList {
-		if item == name {
-			return true
-		}
-	}
-	return false
-}
-
-func (a *CountPercentAnalyzerImpl) CountPercentByFilePath(filePath string) PercentResult {
-	file, err := os.Open(filePath)
+func (a *CountPercentAnalyzerImpl) CountPercentByFilePath(filePath string) (PercentResult, error) {
+	source, err := loadSourceFile(filePath)
 	if err != nil {
-		panic(err)
+		return PercentResult{}, err
 	}
-	defer file.Close()
 
-	var result PercentResult
-	scanner := bufio.NewScanner(file)
-	inBlockComment := false
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		result.TotalLines++
+	return commentPercentage(source), nil
+}
 
-		if isComment(line, &inBlockComment) { // Usando a função que já existe no pacote
-			result.CommentLines++
-		}
-	}
+// commentPercentage computes the share of comment lines among all the
+// lines of a file, blank lines included.
+func commentPercentage(source *sourceFile) PercentResult {
+	var result PercentResult
+	result.TotalLines = len(source.lines)
+	result.CommentLines = source.commentLines()
 
 	if result.TotalLines > 0 {
 		result.CommentPercentage = float64(result.CommentLines) / float64(result.TotalLines) * 100
@@ -60,48 +42,25 @@ func (a *CountPercentAnalyzerImpl) CountPercentByFilePath(filePath string) Perce
 	return result
 }
 
-func (a *CountPercentAnalyzerImpl) CountCommentsByDirectory(directoryPath string) (PercentResultMap, PercentResult) {
-	if directoryPath == "." {
-		var err error
-		directoryPath, err = os.Getwd()
-		if err != nil {
-			panic(err)
-		}
+func (a *CountPercentAnalyzerImpl) CountCommentsByDirectory(directoryPath string) (PercentResultMap, PercentResult, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return nil, PercentResult{}, err
 	}
 
 	absPath, err := filepath.Abs(directoryPath)
 	if err != nil {
-		panic(err)
-	}
-
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		panic(fmt.Sprintf("directory %s does not exist", absPath))
+		return nil, PercentResult{}, err
 	}
 
 	linesByArchive := make(PercentResultMap)
 
-	err = filepath.Walk(absPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if shouldIgnore(info.Name()) {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if filepath.Ext(path) == ".js" {
-			result := a.CountPercentByFilePath(path)
-			linesByArchive[path] = result
-		}
-
-		return nil
+	err = analyzeSourceFiles(absPath, a.CountPercentByFilePath, func(path, relativePath string, result PercentResult) {
+		linesByArchive[relativePath] = result
 	})
 
-	if err != nil {
-		panic(err)
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, PercentResult{}, fatal
 	}
 
 	var total PercentResult
@@ -114,5 +73,5 @@ func (a *CountPercentAnalyzerImpl) CountCommentsByDirectory(directoryPath string
 		total.CommentPercentage = float64(total.CommentLines) / float64(total.TotalLines) * 100
 	}
 
-	return linesByArchive, total
+	return linesByArchive, total, err
 }
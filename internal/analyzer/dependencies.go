@@ -1,10 +1,8 @@
 package analyzer
 
 import (
-	"bufio"
-	"os"
-	"path/filepath"
-	"regexp"
+	"go-cli-tool/internal/tokenizer"
+	"sort"
 	"strings"
 )
 
@@ -17,65 +15,124 @@ type DependencyResult struct {
 	NativeModules     []string `json:"native_modules"`
 }
 
-// List of regexes to capture different dependency patterns
-var dependencyRegexes = []*regexp.Regexp{
-	// import defaultExport from 'module-name';
-	regexp.MustCompile(`(?m)^import\s+\w+\s+from\s+['"]([^'"]+)['"]`),
+// DependenciesMap maps the files to their dependencies.
+type DependenciesMap map[string]DependencyResult
 
-	// import * as name from 'module-name';
-	regexp.MustCompile(`(?m)^import\s+\*\s+as\s+\w+\s+from\s+['"]([^'"]+)['"]`),
+func (a *CountDependenciesAnalyzerImpl) CountDependenciesByFilePath(filePath string) (DependencyResult, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return DependencyResult{}, err
+	}
 
-	// import { something } from 'module-name';
-	regexp.MustCompile(`(?m)^import\s+{[^}]+}\s+from\s+['"]([^'"]+)['"]`),
+	return dependenciesOf(source), nil
+}
 
-	// import 'module-name';
-	regexp.MustCompile(`(?m)^import\s+['"]([^'"]+)['"]`),
+// dependenciesOf lists the external dependencies and the native modules a
+// file imports.
+func dependenciesOf(source *sourceFile) DependencyResult {
+	externalDependencies := make(map[string]struct{})
+	nativeModules := make(map[string]struct{})
 
-	// require('module-name') or require('module-name').something()
-	regexp.MustCompile(`(?m)require\(\s*['"]([^'"]+)['"]\s*\)`),
-}
+	for _, specifier := range findModuleSpecifiers(source.code) {
+		normalizedDependency := normalizeModuleName(unquote(specifier))
+		if isNativeModule(normalizedDependency) {
+			nativeModules[normalizedDependency] = struct{}{}
+		} else if isExternalDependency(normalizedDependency) {
+			externalDependencies[normalizedDependency] = struct{}{}
+		}
+	}
 
-func (a *CountDependenciesAnalyzerImpl) CountDependenciesByFilePath(filePath string) (map[string]interface{}, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+	return DependencyResult{
+		TotalDependencies: len(externalDependencies),
+		Dependencies:      mapKeysToSlice(externalDependencies),
+		NativeModules:     mapKeysToSlice(nativeModules),
 	}
-	defer file.Close()
+}
 
-	externalDependencies := make(map[string]struct{})
-	nativeModules := make(map[string]struct{})
+// findModuleSpecifiers returns the string literals naming the modules
+// referenced by static imports, re-exports, dynamic import() and require()
+// calls.
+func findModuleSpecifiers(code []tokenizer.Token) []tokenizer.Token {
+	var specifiers []tokenizer.Token
 
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Check each regex for a match
-		for _, r := range dependencyRegexes {
-			if matches := r.FindStringSubmatch(line); matches != nil {
-				dependency := matches[1]
-				if dependency != "" {
-					normalizedDependency := normalizeModuleName(dependency)
-					if isNativeModule(normalizedDependency) {
-						nativeModules[normalizedDependency] = struct{}{}
-					} else if isExternalDependency(normalizedDependency) {
-						externalDependencies[normalizedDependency] = struct{}{}
-					}
-					break
+	for i, token := range code {
+		if isMemberAccess(code, i) {
+			continue
+		}
+
+		switch {
+		case token.Is(tokenizer.Keyword, "import"), token.Is(tokenizer.Identifier, "require"):
+			// import 'module-name'
+			if specifier, ok := stringAt(code, i+1); ok && token.Kind == tokenizer.Keyword {
+				specifiers = append(specifiers, specifier)
+				continue
+			}
+			// import('module-name') or require('module-name')
+			if i+1 < len(code) && code[i+1].IsPunctuator("(") {
+				if specifier, ok := stringAt(code, i+2); ok {
+					specifiers = append(specifiers, specifier)
+				}
+				continue
+			}
+			// import defaultExport, { something } from 'module-name'
+			if token.Kind == tokenizer.Keyword {
+				if specifier, ok := fromClauseSpecifier(code, i+1); ok {
+					specifiers = append(specifiers, specifier)
 				}
 			}
+		case token.Is(tokenizer.Keyword, "export"):
+			// export { something } from 'module-name' or export * from 'module-name'
+			if specifier, ok := fromClauseSpecifier(code, i+1); ok {
+				specifiers = append(specifiers, specifier)
+			}
+		}
+	}
+
+	return specifiers
+}
+
+// fromClauseSpecifier looks for the `from 'module-name'` that closes an
+// import or export declaration whose clause starts at index i.
+func fromClauseSpecifier(code []tokenizer.Token, i int) (tokenizer.Token, bool) {
+	depth := 0
+	for k := i; k < len(code); k++ {
+		token := code[k]
+		switch {
+		case token.IsPunctuator("{"):
+			depth++
+		case token.IsPunctuator("}"):
+			depth--
+			if depth < 0 {
+				return tokenizer.Token{}, false
+			}
+		case depth > 0:
+			continue
+		case token.Is(tokenizer.Identifier, "from"):
+			return stringAt(code, k+1)
+		case token.IsPunctuator(";"), token.IsPunctuator("="), token.IsPunctuator("("):
+			return tokenizer.Token{}, false
+		case token.Kind == tokenizer.Keyword && token.Value != "default":
+			return tokenizer.Token{}, false
 		}
 	}
+	return tokenizer.Token{}, false
+}
+
+func isMemberAccess(code []tokenizer.Token, i int) bool {
+	return i > 0 && (code[i-1].IsPunctuator(".") || code[i-1].IsPunctuator("?."))
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+// stringAt returns the non-empty string literal at index i.
+func stringAt(code []tokenizer.Token, i int) (tokenizer.Token, bool) {
+	if i >= len(code) || code[i].Kind != tokenizer.String || len(code[i].Value) <= 2 {
+		return tokenizer.Token{}, false
 	}
+	return code[i], true
+}
 
-	return map[string]interface{}{
-		"total_dependencies": len(externalDependencies),
-		"dependencies":       mapKeysToSlice(externalDependencies),
-		"native_modules":     mapKeysToSlice(nativeModules),
-	}, nil
+// unquote returns the value of a string literal without its quotes.
+func unquote(literal tokenizer.Token) string {
+	return literal.Value[1 : len(literal.Value)-1]
 }
 
 func normalizeModuleName(moduleName string) string {
@@ -86,23 +143,17 @@ func normalizeModuleName(moduleName string) string {
 	return moduleName
 }
 
-// CountDependenciesByDirectory analyzes all JavaScript files in a directory for external and native dependencies
-func (a *CountDependenciesAnalyzerImpl) CountDependenciesByDirectory(directoryPath string) (map[string]interface{}, error) {
-	results := make(map[string]interface{})
+// CountDependenciesByDirectory analyzes all JavaScript files in a directory for external and native dependencies,
+// skipping the files that cannot be read
+func (a *CountDependenciesAnalyzerImpl) CountDependenciesByDirectory(directoryPath string) (DependenciesMap, error) {
+	results := make(DependenciesMap)
 
-	err := filepath.Walk(directoryPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.IsDir() && strings.HasSuffix(path, ".js") {
-			result, err := a.CountDependenciesByFilePath(path)
-			if err != nil {
-				return err
-			}
-			results[path] = result
-		}
-		return nil
+	err := analyzeSourceFiles(directoryPath, a.CountDependenciesByFilePath, func(path, relativePath string, result DependencyResult) {
+		results[relativePath] = result
 	})
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, fatal
+	}
 
 	return results, err
 }
@@ -179,5 +230,6 @@ func mapKeysToSlice(m map[string]struct{}) []string {
 	for key := range m {
 		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 	return keys
 }
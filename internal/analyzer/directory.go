@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go-cli-tool/internal/cache"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/parser"
+	"go-cli-tool/internal/utils"
+	"os"
+	"path/filepath"
+)
+
+// DirectoryAnalysis holds the results of all the analyzers for the files of
+// a directory, as their *ByDirectory methods return them: the results of
+// each file are keyed by its slash-separated path relative to the
+// directory.
+type DirectoryAnalysis struct {
+	Lines                      FilesNameCountLineMap
+	TotalLines                 LineResult
+	Comments                   CommentsMap
+	TotalComments              CommentResult
+	ClassesAndFunctions        ClassesAndFunctionsMap
+	TotalClassesAndFunctions   ClassFuncResult
+	Functions                  FunctionsMap
+	Percent                    PercentResultMap
+	TotalPercent               PercentResult
+	Methods                    MethodCountMap
+	TotalMethods               MethodCountResult
+	AverageFunctionSizes       map[string]float64
+	OverallAverageFunctionSize float64
+	Complexity                 ComplexityMap
+	TotalComplexity            ComplexityResult
+	Maintainability            MaintainabilityMap
+	TotalMaintainability       MaintainabilityResult
+	Indentation                IndentationResults
+	Dependencies               DependenciesMap
+	// Files holds the results of every file.
+	Files map[string]FileAnalysis
+}
+
+type DirectoryAnalyzer interface {
+	AnalyzeDirectory(directoryPath string) (DirectoryAnalysis, error)
+}
+
+type DirectoryAnalyzerImpl struct {
+	// Cache, when set, holds the results of the files analyzed by previous
+	// runs, so that only the files that changed are analyzed again.
+	Cache *cache.Cache
+	// Config, when set, replaces the active configuration: it selects the
+	// files of the directory, the tab width and the number of workers.
+	Config *config.Config
+}
+
+// settings returns the configuration of the analysis.
+func (a *DirectoryAnalyzerImpl) settings() config.Config {
+	if a.Config != nil {
+		return *a.Config
+	}
+	return config.Active
+}
+
+// fileAnalysisVersion is part of the cache key of a FileAnalysis, and must
+// change whenever the layout of FileAnalysis or the key itself changes.
+const fileAnalysisVersion = "2"
+
+// FileAnalysis holds the results of all the analyzers for a file. It is
+// stored as is in the cache; the results depending on the path of the file
+// are set by withPath. The functions of Complexity, Maintainability and
+// Indentation are in the order of Functions.
+type FileAnalysis struct {
+	Lines               LineResult
+	Comments            CommentResult
+	ClassesAndFunctions ClassFuncResult
+	Functions           []parser.Function
+	Percent             PercentResult
+	Methods             MethodCountResult
+	AverageFunctionSize float64
+	Complexity          ComplexityResult
+	Maintainability     MaintainabilityResult
+	Indentation         IndentResult
+	Dependencies        DependencyResult
+	// FunctionTokens keeps the fields of Functions that are not encoded.
+	FunctionTokens []functionTokens
+}
+
+// functionTokens are the fields of a parser.Function locating it in the
+// tokens of its file.
+type functionTokens struct {
+	Parent, BodyStart, BodyEnd int
+}
+
+// AnalyzeFile runs all the analyzers over a file, unless the cache already
+// holds their results for the content of the file.
+func (a *DirectoryAnalyzerImpl) AnalyzeFile(path string) (FileAnalysis, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return FileAnalysis{}, err
+	}
+
+	var file FileAnalysis
+	var key string
+	if a.Cache != nil {
+		// the extension selects how the content is parsed
+		prefix := fileAnalysisVersion + "\x00" + filepath.Ext(path) + "\x00"
+		key = a.Cache.Key(append([]byte(prefix), content...))
+		if a.Cache.Get(key, &file) {
+			return file.withPath(path), nil
+		}
+	}
+
+	source := newSourceFile(path, string(content))
+	indentationAnalyzer := &IdentationAnalyzerImpl{TabWidth: a.settings().TabWidth}
+	file = FileAnalysis{
+		Lines:               countLines(source),
+		Comments:            countComments(source),
+		ClassesAndFunctions: countClassesAndFunctions(source),
+		Functions:           source.syntax.Functions,
+		Percent:             commentPercentage(source),
+		Methods:             countMethods(source),
+		AverageFunctionSize: averageFunctionSize(source),
+		Complexity:          complexityOf(source),
+		Maintainability:     maintainabilityOf(source),
+		Indentation:         indentationAnalyzer.calculateIndentationStats(source),
+		Dependencies:        dependenciesOf(source),
+	}
+	if a.Cache != nil {
+		file.FunctionTokens = make([]functionTokens, len(file.Functions))
+		for i, function := range file.Functions {
+			file.FunctionTokens[i] = functionTokens{function.Parent, function.BodyStart, function.BodyEnd}
+		}
+		// a cache that cannot be written only makes the next run slower
+		_ = a.Cache.Put(key, file)
+		file.FunctionTokens = nil
+	}
+	return file, nil
+}
+
+// withPath sets the path of the file in the results, which the cache
+// shares between the files with the same content, and the fields of the
+// functions that are not encoded.
+func (file FileAnalysis) withPath(path string) FileAnalysis {
+	file.Complexity = file.Complexity.inFile(path)
+
+	for i, tokens := range file.FunctionTokens {
+		if i < len(file.Functions) {
+			file.Functions[i].Parent = tokens.Parent
+			file.Functions[i].BodyStart = tokens.BodyStart
+			file.Functions[i].BodyEnd = tokens.BodyEnd
+		}
+	}
+	file.FunctionTokens = nil
+	return file
+}
+
+// AnalyzeDirectory runs all the analyzers over the directory in a single
+// walk: each file is read and parsed once, by one of the workers of the
+// pool, and the results are merged in the order of the walk. The files that
+// cannot be read are left out of the analysis, and returned in a
+// *SkippedFilesError along with the complete results of the other files.
+func (a *DirectoryAnalyzerImpl) AnalyzeDirectory(directoryPath string) (DirectoryAnalysis, error) {
+	return a.AnalyzeDirectoryContext(context.Background(), directoryPath)
+}
+
+// AnalyzeDirectoryContext analyzes the directory as AnalyzeDirectory does,
+// and stops with the error of ctx once ctx is done.
+func (a *DirectoryAnalyzerImpl) AnalyzeDirectoryContext(ctx context.Context, directoryPath string) (DirectoryAnalysis, error) {
+	directoryPath, err := utils.ExpandPath(directoryPath)
+	if err != nil {
+		return DirectoryAnalysis{}, err
+	}
+	if _, err := os.Stat(directoryPath); os.IsNotExist(err) {
+		return DirectoryAnalysis{}, fmt.Errorf("directory %s does not exist", directoryPath)
+	}
+
+	analysis := DirectoryAnalysis{
+		Lines:                make(FilesNameCountLineMap),
+		Comments:             make(CommentsMap),
+		ClassesAndFunctions:  make(ClassesAndFunctionsMap),
+		Functions:            make(FunctionsMap),
+		Percent:              make(PercentResultMap),
+		Methods:              make(MethodCountMap),
+		AverageFunctionSizes: make(map[string]float64),
+		Complexity:           make(ComplexityMap),
+		Maintainability:      make(MaintainabilityMap),
+		Dependencies:         make(DependenciesMap),
+		Files:                make(map[string]FileAnalysis),
+	}
+	indentations := []FileIndentation{}
+	var allFunctions []FunctionComplexity
+	var averages float64
+	var withFunctions int
+
+	err = analyzeSourceFilesWith(ctx, a.settings(), directoryPath, a.AnalyzeFile, func(path, name string, file FileAnalysis) {
+		file = file.withPath(name)
+		analysis.Files[name] = file
+		analysis.Lines[name] = file.Lines
+		analysis.TotalLines.TotalLines += file.Lines.TotalLines
+		analysis.Comments[name] = file.Comments
+		analysis.TotalComments.TotalComments += file.Comments.CommentLines
+
+		analysis.ClassesAndFunctions[name] = file.ClassesAndFunctions
+		analysis.TotalClassesAndFunctions.Classes += file.ClassesAndFunctions.Classes
+		analysis.TotalClassesAndFunctions.Functions += file.ClassesAndFunctions.Functions
+		analysis.TotalClassesAndFunctions.Interfaces += file.ClassesAndFunctions.Interfaces
+		analysis.TotalClassesAndFunctions.Enums += file.ClassesAndFunctions.Enums
+		analysis.TotalClassesAndFunctions.TypeAliases += file.ClassesAndFunctions.TypeAliases
+		analysis.Functions[name] = file.Functions
+
+		analysis.Percent[name] = file.Percent
+		analysis.TotalPercent.CommentLines += file.Percent.CommentLines
+		analysis.TotalPercent.TotalLines += file.Percent.TotalLines
+
+		analysis.Methods[name] = file.Methods
+		analysis.TotalMethods.Public += file.Methods.Public
+		analysis.TotalMethods.Private += file.Methods.Private
+
+		analysis.AverageFunctionSizes[name] = file.AverageFunctionSize
+		if file.AverageFunctionSize > 0 {
+			averages += file.AverageFunctionSize
+			withFunctions++
+		}
+
+		analysis.Complexity[name] = file.Complexity
+		allFunctions = append(allFunctions, file.Complexity.Functions...)
+		analysis.Maintainability[name] = file.Maintainability
+
+		indentations = append(indentations, newFileIndentation(name, file.Indentation))
+		analysis.Dependencies[name] = file.Dependencies
+	})
+	if _, fatal := Skipped(err); fatal != nil {
+		return DirectoryAnalysis{}, fatal
+	}
+
+	if analysis.TotalPercent.TotalLines > 0 {
+		analysis.TotalPercent.CommentPercentage = float64(analysis.TotalPercent.CommentLines) / float64(analysis.TotalPercent.TotalLines) * 100
+	}
+	if withFunctions > 0 {
+		analysis.OverallAverageFunctionSize = averages / float64(withFunctions)
+	}
+	analysis.TotalComplexity = summarizeComplexity(allFunctions)
+	analysis.TotalMaintainability = summarizeMaintainability(analysis.Maintainability)
+	analysis.Indentation = IndentationResults{Directory: directoryPath, Files: indentations}
+
+	return analysis, err
+}
@@ -0,0 +1,214 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/cache"
+	"go-cli-tool/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProject(t *testing.T, files int) string {
+	dir := t.TempDir()
+	for i := 0; i < files; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("pkg%d", i%3), fmt.Sprintf("file%02d.js", i))
+		content := fmt.Sprintf("// file %d\nimport fs from 'fs';\nimport lib%d from 'lib%d';\n", i, i, i) +
+			strings.Repeat("function f(x) {\n\tif (x > 1 && x < 10) {\n\t\treturn x * 2;\n\t}\n\treturn x;\n}\n", i%4+1)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestAnalyzeDirectoryMatchesEachAnalyzer(t *testing.T) {
+	dir := writeProject(t, 12)
+
+	directoryAnalyzer := &analyzer.DirectoryAnalyzerImpl{}
+	analysis, err := directoryAnalyzer.AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+
+	lines, totalLines, err := (&analyzer.CountLinesAnalyzerImpl{}).CountLinesByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, lines, analysis.Lines)
+	assert.Equal(t, totalLines, analysis.TotalLines)
+
+	comments, totalComments, err := (&analyzer.CountCommentsAnalyzerImpl{}).CountCommentsByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, comments, analysis.Comments)
+	assert.Equal(t, totalComments, analysis.TotalComments)
+
+	classFuncAnalyzer := &analyzer.CountClassAndFunctionsImpl{}
+	classesAndFunctions, totalClassesAndFunctions, err := classFuncAnalyzer.CountClassesAndFunctionsByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, classesAndFunctions, analysis.ClassesAndFunctions)
+	assert.Equal(t, totalClassesAndFunctions, analysis.TotalClassesAndFunctions)
+	functions, err := classFuncAnalyzer.ListFunctionsByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, functions, analysis.Functions)
+
+	percent, totalPercent, err := (&analyzer.CountPercentAnalyzerImpl{}).CountCommentsByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, percent, analysis.Percent)
+	assert.Equal(t, totalPercent, analysis.TotalPercent)
+
+	methods, totalMethods, err := (&analyzer.MethodCountAnalyzerImpl{}).AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, methods, analysis.Methods)
+	assert.Equal(t, totalMethods, analysis.TotalMethods)
+
+	averages, overallAverage, err := (&analyzer.AverageFunctionAnalyzerImpl{}).CalculateAverageFunctionSizeByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, averages, analysis.AverageFunctionSizes)
+	assert.Equal(t, overallAverage, analysis.OverallAverageFunctionSize)
+
+	complexity, totalComplexity, err := (&analyzer.ComplexityAnalyzerImpl{}).CalculateComplexityByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, complexity, analysis.Complexity)
+	assert.Equal(t, totalComplexity, analysis.TotalComplexity)
+
+	maintainability, totalMaintainability, err := (&analyzer.MaintainabilityAnalyzerImpl{}).CalculateMaintainabilityByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, maintainability, analysis.Maintainability)
+	assert.Equal(t, totalMaintainability, analysis.TotalMaintainability)
+
+	dependencies, err := (&analyzer.CountDependenciesAnalyzerImpl{}).CountDependenciesByDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, dependencies, analysis.Dependencies)
+	assert.Len(t, analysis.Indentation.Files, 12)
+}
+
+func TestAnalyzeDirectoryIsDeterministic(t *testing.T) {
+	dir := writeProject(t, 40)
+	defer func(settings config.Config) { config.Active = settings }(config.Active)
+
+	directoryAnalyzer := &analyzer.DirectoryAnalyzerImpl{}
+	config.Active.Jobs = 1
+	sequential, err := directoryAnalyzer.AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+
+	config.Active.Jobs = 8
+	for i := 0; i < 5; i++ {
+		parallel, err := directoryAnalyzer.AnalyzeDirectory(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, sequential, parallel)
+	}
+}
+
+func TestAnalyzeDirectoryNotFound(t *testing.T) {
+	directoryAnalyzer := &analyzer.DirectoryAnalyzerImpl{}
+	_, err := directoryAnalyzer.AnalyzeDirectory(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestAnalyzeDirectoryWithCache(t *testing.T) {
+	dir := writeProject(t, 12)
+	stateDir := filepath.Join(t.TempDir(), ".gocli")
+
+	expected, err := (&analyzer.DirectoryAnalyzerImpl{}).AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+
+	first := &analyzer.DirectoryAnalyzerImpl{Cache: cache.New(stateDir, "test", config.Active)}
+	analysis, err := first.AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, analysis)
+	assert.Equal(t, int64(0), first.Cache.Hits())
+
+	second := &analyzer.DirectoryAnalyzerImpl{Cache: cache.New(stateDir, "test", config.Active)}
+	analysis, err = second.AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, analysis)
+	assert.Equal(t, int64(12), second.Cache.Hits())
+
+	// only the changed file is analyzed again
+	changed := filepath.Join(dir, "pkg0", "file00.js")
+	assert.NoError(t, os.WriteFile(changed, []byte("function g() {\n  return 1;\n}\n"), 0644))
+	third := &analyzer.DirectoryAnalyzerImpl{Cache: cache.New(stateDir, "test", config.Active)}
+	analysis, err = third.AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), third.Cache.Hits())
+	assert.Equal(t, int64(1), third.Cache.Misses())
+	assert.Equal(t, 3, analysis.Lines["pkg0/file00.js"].TotalLines)
+}
+
+func TestAnalyzeFileCacheDependsOnTheExtension(t *testing.T) {
+	dir := t.TempDir()
+	content := "<script>\nfunction f() {}\n</script>\n<style></style>\n"
+	component := filepath.Join(dir, "a", "x.vue")
+	script := filepath.Join(dir, "b", "x.js")
+	for _, path := range []string{component, script} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	uncached := &analyzer.DirectoryAnalyzerImpl{}
+	expectedComponent, err := uncached.AnalyzeFile(component)
+	assert.NoError(t, err)
+	expectedScript, err := uncached.AnalyzeFile(script)
+	assert.NoError(t, err)
+	assert.NotEqual(t, expectedComponent.Lines, expectedScript.Lines)
+
+	cached := &analyzer.DirectoryAnalyzerImpl{Cache: cache.New(filepath.Join(t.TempDir(), ".gocli"), "test", config.Active)}
+	file, err := cached.AnalyzeFile(component)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedComponent, file)
+	file, err = cached.AnalyzeFile(script)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedScript, file)
+	assert.Equal(t, int64(0), cached.Cache.Hits())
+}
+
+func TestAnalyzeDirectoryKeepsFilesWithTheSameName(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.js":         "const a = 1;\n",
+		"lib/index.js":     "const b = 1;\nconst c = 2;\n",
+		"lib/sub/index.js": "const d = 1;\nconst e = 2;\nconst f = 3;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	analysis, err := (&analyzer.DirectoryAnalyzerImpl{}).AnalyzeDirectory(dir)
+	assert.NoError(t, err)
+
+	assert.Len(t, analysis.Files, 3)
+	assert.Equal(t, 1, analysis.Lines["index.js"].TotalLines)
+	assert.Equal(t, 2, analysis.Lines["lib/index.js"].TotalLines)
+	assert.Equal(t, 3, analysis.Lines["lib/sub/index.js"].TotalLines)
+	assert.Equal(t, 6, analysis.TotalLines.TotalLines)
+	assert.Len(t, analysis.Indentation.Files, 3)
+}
+
+func TestAnalyzeDirectoryReportsPathsRelativeToARelativeDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "src", "y", "index.js")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte("function f(x) {\n  return x;\n}\n"), 0644))
+
+	workingDirectory, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	defer os.Chdir(workingDirectory)
+
+	analysis, err := (&analyzer.DirectoryAnalyzerImpl{}).AnalyzeDirectory("src")
+	assert.NoError(t, err)
+	assert.Contains(t, analysis.Files, "y/index.js")
+	assert.Equal(t, "y/index.js", analysis.Indentation.Files[0].Path)
+	assert.Equal(t, "y/index.js", analysis.Complexity["y/index.js"].Functions[0].File)
+	assert.Equal(t, "y/index.js", analysis.TotalComplexity.Functions[0].File)
+
+	indentation, err := (&analyzer.IdentationAnalyzerImpl{}).IdentationByPath("src")
+	assert.NoError(t, err)
+	assert.Equal(t, "y/index.js", indentation.Files[0].Path)
+
+	_, totalComplexity, err := (&analyzer.ComplexityAnalyzerImpl{}).CalculateComplexityByDirectory("src")
+	assert.NoError(t, err)
+	assert.Equal(t, "y/index.js", totalComplexity.Functions[0].File)
+}
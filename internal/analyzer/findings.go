@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"fmt"
+	"go-cli-tool/internal/config"
+	"sort"
+	"strings"
+)
+
+// Level is the severity of a finding, named after the SARIF levels.
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Rule describes a kind of finding.
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Level       Level
+}
+
+var (
+	mixedIndentationRule = Rule{
+		ID:          "mixed-indentation",
+		Name:        "MixedIndentation",
+		Description: "The file is indented with both tabs and spaces.",
+		Level:       LevelWarning,
+	}
+	functionSizeRule = Rule{
+		ID:          "function-size",
+		Name:        "OversizedFunction",
+		Description: "The function spans more lines than the configured maximum.",
+		Level:       LevelWarning,
+	}
+	commentPercentageRule = Rule{
+		ID:          "comment-percentage",
+		Name:        "LowCommentDensity",
+		Description: "The share of comment lines in the file is below the configured minimum.",
+		Level:       LevelNote,
+	}
+	bannedDependencyRule = Rule{
+		ID:          "banned-dependency",
+		Name:        "BannedDependency",
+		Description: "The file imports a module listed in bannedDependencies.",
+		Level:       LevelError,
+	}
+)
+
+// Rules are the rules findings are reported for, in a stable order.
+var Rules = []Rule{mixedIndentationRule, functionSizeRule, commentPercentageRule, bannedDependencyRule}
+
+// Finding is a problem located in a file, with 1-based line and column.
+type Finding struct {
+	Rule    string `json:"rule"`
+	Level   Level  `json:"level"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+type FindingsAnalyzer interface {
+	FindingsByFilePath(filePath string, settings config.Config) ([]Finding, error)
+	FindingsByDirectory(directoryPath string, settings config.Config) ([]Finding, error)
+}
+
+type FindingsAnalyzerImpl struct{}
+
+func (a *FindingsAnalyzerImpl) FindingsByFilePath(filePath string, settings config.Config) ([]Finding, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	report := func(rule Rule, line int, column int, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Rule:    rule.ID,
+			Level:   rule.Level,
+			File:    filePath,
+			Line:    line,
+			Column:  column,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	indentationAnalyzer := &IdentationAnalyzerImpl{}
+	indentation := indentationAnalyzer.calculateIndentationStats(source)
+	if indentation.MixedIndentation && !settings.Thresholds.AllowMixedIndentation {
+		report(mixedIndentationRule, indentation.MixedIndentationLine, 1, "indentation mixes tabs and spaces")
+	}
+
+	if limit := settings.Thresholds.MaxFunctionLines; limit > 0 {
+		for _, function := range oversizedFunctions(source, limit) {
+			report(functionSizeRule, function.StartLine, source.lineColumn(function.StartLine),
+				"%s has %d lines (max %d)", displayName(function), function.Lines(), limit)
+		}
+	}
+
+	if limit := settings.Thresholds.MinCommentPercentage; limit > 0 {
+		percent := commentPercentage(source)
+		if percent.TotalLines > 0 && percent.CommentPercentage < limit {
+			report(commentPercentageRule, 1, 1, "%.2f%% of the lines are comments (min %.2f%%)", percent.CommentPercentage, limit)
+		}
+	}
+
+	for _, specifier := range findModuleSpecifiers(source.code) {
+		module := normalizeModuleName(unquote(specifier))
+		if banned, ok := bannedBy(module, settings.BannedDependencies); ok {
+			report(bannedDependencyRule, specifier.Line, specifier.Column, "%s is a banned dependency (%s)", module, banned)
+		}
+	}
+
+	return findings, nil
+}
+
+func (a *FindingsAnalyzerImpl) FindingsByDirectory(directoryPath string, settings config.Config) ([]Finding, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+
+	err = analyzeSourceFiles(directoryPath, func(path string) ([]Finding, error) {
+		return a.FindingsByFilePath(path, settings)
+	}, func(path, relativePath string, fileFindings []Finding) {
+		findings = append(findings, fileFindings...)
+	})
+
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, fatal
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+
+	return findings, err
+}
+
+// bannedBy returns the entry of the banned list matching a module, which is
+// either the module itself or one of its parents.
+func bannedBy(module string, banned []string) (string, bool) {
+	for _, entry := range banned {
+		entry = normalizeModuleName(entry)
+		if module == entry || strings.HasPrefix(module, entry+"/") {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// lineColumn returns the column of the first token on a line, or 1 when the
+// line holds no code.
+func (f *sourceFile) lineColumn(line int) int {
+	for _, token := range f.code {
+		if token.Line == line {
+			return token.Column
+		}
+		if token.Line > line {
+			break
+		}
+	}
+	return 1
+}
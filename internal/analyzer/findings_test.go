@@ -0,0 +1,55 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindingsByFilePath(t *testing.T) {
+	content := "import fp from 'lodash/fp';\n" +
+		"const fs = require('node:fs');\n" +
+		"\n" +
+		"function load(path) {\n" +
+		"    const text = fs.readFileSync(path);\n" +
+		"\treturn fp.trim(text);\n" +
+		"}\n"
+	filePath := filepath.Join(t.TempDir(), "load.js")
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	settings := config.Default()
+	settings.BannedDependencies = []string{"lodash", "fs"}
+	settings.Thresholds.MaxFunctionLines = 3
+	settings.Thresholds.MinCommentPercentage = 10
+
+	findingsAnalyzer := &analyzer.FindingsAnalyzerImpl{}
+	findings, err := findingsAnalyzer.FindingsByFilePath(filePath, settings)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []analyzer.Finding{
+		{Rule: "mixed-indentation", Level: analyzer.LevelWarning, File: filePath, Line: 6, Column: 1, Message: "indentation mixes tabs and spaces"},
+		{Rule: "function-size", Level: analyzer.LevelWarning, File: filePath, Line: 4, Column: 1, Message: "load has 4 lines (max 3)"},
+		{Rule: "comment-percentage", Level: analyzer.LevelNote, File: filePath, Line: 1, Column: 1, Message: "0.00% of the lines are comments (min 10.00%)"},
+		{Rule: "banned-dependency", Level: analyzer.LevelError, File: filePath, Line: 1, Column: 16, Message: "lodash/fp is a banned dependency (lodash)"},
+		{Rule: "banned-dependency", Level: analyzer.LevelError, File: filePath, Line: 2, Column: 20, Message: "fs is a banned dependency (fs)"},
+	}, findings)
+}
+
+func TestFindingsByFilePathWithinLimits(t *testing.T) {
+	content := "// Adds two numbers.\nimport lodashes from 'lodashes';\nfunction add(a, b) {\n    return a + b;\n}\n"
+	filePath := filepath.Join(t.TempDir(), "add.js")
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	settings := config.Default()
+	settings.BannedDependencies = []string{"lodash"}
+	settings.Thresholds.MinCommentPercentage = 10
+
+	findingsAnalyzer := &analyzer.FindingsAnalyzerImpl{}
+	findings, err := findingsAnalyzer.FindingsByFilePath(filePath, settings)
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"go-cli-tool/internal/tokenizer"
+	"math"
+)
+
+// HalsteadMetrics are the software science measures of a piece of code,
+// derived from its operators and operands.
+type HalsteadMetrics struct {
+	DistinctOperators int     `json:"distinctOperators"`
+	DistinctOperands  int     `json:"distinctOperands"`
+	TotalOperators    int     `json:"totalOperators"`
+	TotalOperands     int     `json:"totalOperands"`
+	Vocabulary        int     `json:"vocabulary"`
+	Length            int     `json:"length"`
+	Volume            float64 `json:"volume"`
+	Difficulty        float64 `json:"difficulty"`
+	Effort            float64 `json:"effort"`
+}
+
+// FunctionHalstead is the Halstead metrics of a single function.
+type FunctionHalstead struct {
+	Name      string `json:"name"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	HalsteadMetrics
+}
+
+// halstead measures the given code tokens. Keywords and punctuators are
+// operators, with a pair of brackets counted once at the opening bracket;
+// identifiers and literals are operands. JSX markup is not measured.
+func halstead(tokens []tokenizer.Token) HalsteadMetrics {
+	operators := make(map[string]bool)
+	operands := make(map[string]bool)
+	var metrics HalsteadMetrics
+
+	for _, token := range tokens {
+		switch token.Kind {
+		case tokenizer.Keyword:
+			if isLiteralKeyword(token.Value) {
+				operands[token.Value] = true
+				metrics.TotalOperands++
+			} else {
+				operators[token.Value] = true
+				metrics.TotalOperators++
+			}
+		case tokenizer.Punctuator:
+			if token.Value == ")" || token.Value == "]" || token.Value == "}" {
+				continue
+			}
+			operators[token.Value] = true
+			metrics.TotalOperators++
+		case tokenizer.Identifier, tokenizer.Number, tokenizer.String, tokenizer.Template, tokenizer.RegExp:
+			operands[token.Value] = true
+			metrics.TotalOperands++
+		}
+	}
+
+	metrics.DistinctOperators = len(operators)
+	metrics.DistinctOperands = len(operands)
+	metrics.Vocabulary = metrics.DistinctOperators + metrics.DistinctOperands
+	metrics.Length = metrics.TotalOperators + metrics.TotalOperands
+
+	if metrics.Vocabulary > 0 {
+		metrics.Volume = float64(metrics.Length) * math.Log2(float64(metrics.Vocabulary))
+	}
+	if metrics.DistinctOperands > 0 {
+		metrics.Difficulty = float64(metrics.DistinctOperators) / 2 * float64(metrics.TotalOperands) / float64(metrics.DistinctOperands)
+	}
+	metrics.Effort = metrics.Difficulty * metrics.Volume
+
+	return metrics
+}
+
+// isLiteralKeyword reports whether the keyword stands for a value rather
+// than for an operation.
+func isLiteralKeyword(value string) bool {
+	switch value {
+	case "this", "super", "null", "true", "false":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"go-cli-tool/internal/git"
+	"math"
+	"path/filepath"
+	"sort"
+)
+
+// Hotspot is a source file that changed over the analyzed period, with the
+// measures that make it risky to change again.
+type Hotspot struct {
+	// File is the path of the file relative to the analyzed directory.
+	File         string `json:"file"`
+	Commits      int    `json:"commits"`
+	LinesAdded   int    `json:"linesAdded"`
+	LinesRemoved int    `json:"linesRemoved"`
+	Authors      int    `json:"authors"`
+	Lines        int    `json:"lines"`
+	// Complexity is the cyclomatic complexity of the whole file, which
+	// grows with its size.
+	Complexity        int     `json:"complexity"`
+	CommentPercentage float64 `json:"commentPercentage"`
+	Score             float64 `json:"score"`
+}
+
+type HotspotAnalyzer interface {
+	HotspotsByDirectory(directoryPath string, churn map[string]*git.FileChurn) ([]Hotspot, error)
+}
+
+type HotspotAnalyzerImpl struct{}
+
+// wellCommented is the comment percentage from which a file is no longer
+// penalized for lacking comments.
+const wellCommented = 20.0
+
+// HotspotsByDirectory ranks the source files of the directory changed in
+// churn, keyed by absolute path as git.Churn returns it, from the riskiest
+// to the safest. The score of a file is its number of commits times its
+// complexity, doubled for a file without comments and decreasing linearly
+// to no penalty at 20% of comment lines.
+func (a *HotspotAnalyzerImpl) HotspotsByDirectory(directoryPath string, churn map[string]*git.FileChurn) ([]Hotspot, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// git reports the paths with the symbolic links resolved
+	resolvedPath, err := filepath.Abs(directoryPath)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(resolvedPath); err == nil {
+		resolvedPath = resolved
+	}
+
+	hotspots := []Hotspot{}
+	var unreadable []SkippedFile
+
+	err = walkSourceFiles(directoryPath, func(path, relativePath string) error {
+		changes, ok := churn[filepath.Join(resolvedPath, filepath.FromSlash(relativePath))]
+		if !ok || changes.Commits == 0 {
+			return nil
+		}
+
+		source, err := loadSourceFile(path)
+		if err != nil {
+			unreadable = append(unreadable, newSkippedFile(path, err))
+			return nil
+		}
+
+		hotspot := Hotspot{
+			File:         relativePath,
+			Commits:      changes.Commits,
+			LinesAdded:   changes.LinesAdded,
+			LinesRemoved: changes.LinesRemoved,
+			Authors:      len(changes.Authors),
+			Lines:        countLines(source).TotalLines,
+			Complexity:   maintainabilityOf(source).Complexity,
+		}
+		if hotspot.Lines > 0 {
+			comments := countComments(source).CommentLines
+			hotspot.CommentPercentage = math.Round(float64(comments)/float64(hotspot.Lines)*10000) / 100
+		}
+		commentPenalty := 2 - min(hotspot.CommentPercentage, wellCommented)/wellCommented
+		hotspot.Score = math.Round(float64(hotspot.Commits*hotspot.Complexity)*commentPenalty*100) / 100
+
+		hotspots = append(hotspots, hotspot)
+		return nil
+	})
+
+	skipped, err := Skipped(err)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+		return hotspots[i].File < hotspots[j].File
+	})
+	return hotspots, skippedFiles(append(skipped, unreadable...))
+}
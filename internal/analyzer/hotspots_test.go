@@ -0,0 +1,47 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/git"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotspotsByDirectory(t *testing.T) {
+	dir := t.TempDir()
+	branching := "function f(x) {\n\tif (x > 1 && x < 10) {\n\t\treturn x * 2;\n\t}\n\treturn x;\n}\n"
+	commented := "// adds one\nfunction g(x) {\n\treturn x + 1;\n}\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "complex.js"), []byte(branching), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "commented.js"), []byte(commented), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "unchanged.js"), []byte(branching), 0644))
+
+	resolved, err := filepath.EvalSymlinks(dir)
+	assert.NoError(t, err)
+	churn := map[string]*git.FileChurn{
+		filepath.Join(resolved, "complex.js"):   {Commits: 2, LinesAdded: 10, LinesRemoved: 4, Authors: []string{"Ana", "Bruno"}},
+		filepath.Join(resolved, "commented.js"): {Commits: 5, LinesAdded: 4, Authors: []string{"Ana"}},
+		filepath.Join(resolved, "README.md"):    {Commits: 9},
+	}
+
+	hotspotAnalyzer := &analyzer.HotspotAnalyzerImpl{}
+	hotspots, err := hotspotAnalyzer.HotspotsByDirectory(dir, churn)
+	assert.NoError(t, err)
+
+	assert.Len(t, hotspots, 2)
+
+	// 2 commits, complexity 3 and no comments
+	assert.Equal(t, "complex.js", hotspots[0].File)
+	assert.Equal(t, 2, hotspots[0].Authors)
+	assert.Equal(t, 14, hotspots[0].LinesAdded+hotspots[0].LinesRemoved)
+	assert.Equal(t, 3, hotspots[0].Complexity)
+	assert.Equal(t, 6, hotspots[0].Lines)
+	assert.Equal(t, 12.0, hotspots[0].Score)
+
+	// 5 commits, complexity 1 and well commented
+	assert.Equal(t, "commented.js", hotspots[1].File)
+	assert.Equal(t, 25.0, hotspots[1].CommentPercentage)
+	assert.Equal(t, 5.0, hotspots[1].Score)
+}
@@ -2,11 +2,12 @@ package analyzer
 
 import (
 	"fmt"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/policies"
 	"go-cli-tool/internal/utils"
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 )
 
 // IndentResult represents the indentation statistics for a file
@@ -17,6 +18,13 @@ type IndentResult struct {
     UsesSpaces         bool        `json:"usesSpaces"`
     UsesTabs           bool        `json:"usesTabs"`
     MixedIndentation   bool        `json:"mixedIndentation"`
+    // MixedIndentationLine is the first line indented with the character
+    // the file did not start with, or 0 when the indentation is consistent
+    MixedIndentationLine int `json:"mixedIndentationLine,omitempty"`
+    // Structural nesting, measured on the syntax rather than on whitespace
+    MaxNestingDepth        int               `json:"maxNestingDepth"`
+    MaxCognitiveComplexity int               `json:"maxCognitiveComplexity"`
+    Functions              []FunctionNesting `json:"functions"`
 }
 
 // IndentFreq represents frequency of a particular indentation level
@@ -28,108 +36,118 @@ type IndentFreq struct {
 // FileIndentMap maps filenames to their indentation results
 type FileIndentMap map[string]IndentResult
 
-// IdentationAnalyzerImpl implements indentation analysis functionality
-type IdentationAnalyzerImpl struct{}
+// FileIndentation holds the indentation statistics of a file. In the results
+// of a directory, Path is relative to the directory
+type FileIndentation struct {
+    Filename string       `json:"filename"`
+    Path     string       `json:"path"`
+    Stats    IndentResult `json:"stats"`
+}
+
+// IndentationResults holds the indentation statistics of the analyzed
+// files, and the directory holding them
+type IndentationResults struct {
+    Directory string            `json:"directory"`
+    Files     []FileIndentation `json:"files"`
+}
 
-// IdentationByFilePath analyzes indentation based on provided file or directory path
-func (a *IdentationAnalyzerImpl) IdentationByFilePath() (map[string]interface{}, error) {
-    var path string
-    var results map[string]interface{}
-    var err error
+// IdentationAnalyzerImpl implements indentation analysis functionality
+type IdentationAnalyzerImpl struct {
+    // TabWidth, when positive, replaces the tab width of the active
+    // configuration.
+    TabWidth int
+}
 
-    // Determine which path to use
+// IdentationByFilePath analyzes indentation based on the file or directory
+// path given on the command line
+func (a *IdentationAnalyzerImpl) IdentationByFilePath() (IndentationResults, error) {
     if utils.FilePath != "" {
-        path = utils.FilePath
-        results, err = a.analyzeFileIndentation(path)
-    } else if utils.DirectoryPath != "" {
-        path = utils.DirectoryPath
-        results, err = a.analyzeDirectoryIndentation(path)
-    } else {
-        return nil, fmt.Errorf("no file or directory path provided")
+        return a.IdentationByPath(utils.FilePath)
     }
+    if utils.DirectoryPath != "" {
+        return a.IdentationByPath(utils.DirectoryPath)
+    }
+    return IndentationResults{}, fmt.Errorf("no file or directory path provided")
+}
 
-    if err != nil {
-        return nil, err
+// IdentationByPath analyzes indentation of a file, or of all the files of a
+// directory. The results of a file are in the directory of the file.
+func (a *IdentationAnalyzerImpl) IdentationByPath(path string) (IndentationResults, error) {
+    if info, err := os.Stat(path); err == nil && info.IsDir() {
+        return a.analyzeDirectoryIndentation(path)
     }
 
-    return results, nil
+    file, err := a.analyzeFileIndentation(path)
+    if err != nil {
+        return IndentationResults{}, err
+    }
+    return IndentationResults{Directory: filepath.Dir(path), Files: []FileIndentation{file}}, nil
 }
 
 // analyzeFileIndentation analyzes indentation for a single JavaScript file
-func (a *IdentationAnalyzerImpl) analyzeFileIndentation(filePath string) (map[string]interface{}, error) {
+func (a *IdentationAnalyzerImpl) analyzeFileIndentation(filePath string) (FileIndentation, error) {
     // Check if file is JavaScript
-    if !strings.HasSuffix(filePath, ".js") {
-        return nil, fmt.Errorf("file %s is not a JavaScript file", filePath)
+    if !policies.IsJSFileExtension(filePath) {
+        return FileIndentation{}, fmt.Errorf("file %s is not a JavaScript or TypeScript file", filePath)
     }
     
     // Read file
-    content, err := os.ReadFile(filePath)
+    source, err := loadSourceFile(filePath)
     if err != nil {
-        return nil, fmt.Errorf("error reading file %s: %w", filePath, err)
+        return FileIndentation{}, fmt.Errorf("error reading file %s: %w", filePath, err)
     }
     
-    lines := strings.Split(string(content), "\n")
-    indentationStats := a.calculateIndentationStats(lines)
-    
-    // Create results
-    results := map[string]interface{}{
-        "filename": filepath.Base(filePath),
-        "path":     filePath,
-        "stats":    indentationStats,
-    }
-    
-    return results, nil
+    return newFileIndentation(source.path, a.calculateIndentationStats(source)), nil
+}
+
+// newFileIndentation returns the indentation statistics of a file along
+// with its name and path.
+func newFileIndentation(path string, stats IndentResult) FileIndentation {
+    return FileIndentation{Filename: filepath.Base(path), Path: path, Stats: stats}
 }
 
-// analyzeDirectoryIndentation analyzes indentation for all JavaScript files in a directory
-func (a *IdentationAnalyzerImpl) analyzeDirectoryIndentation(dirPath string) (map[string]interface{}, error) {
-    var allFiles []string
+// analyzeDirectoryIndentation analyzes indentation for all JavaScript files in a directory,
+// skipping the files that cannot be read
+func (a *IdentationAnalyzerImpl) analyzeDirectoryIndentation(dirPath string) (IndentationResults, error) {
+    results := IndentationResults{Directory: dirPath, Files: []FileIndentation{}}
     
-    err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-        if err != nil {
-            return err
-        }
-        if !info.IsDir() && strings.HasSuffix(path, ".js") {
-            allFiles = append(allFiles, path)
-        }
-        return nil
+    err := analyzeSourceFiles(dirPath, a.analyzeFileIndentation, func(path, relativePath string, file FileIndentation) {
+        results.Files = append(results.Files, newFileIndentation(relativePath, file.Stats))
     })
     
-    if err != nil {
-        return nil, fmt.Errorf("error walking directory %s: %w", dirPath, err)
-    }
-    
-    filesResults := make([]map[string]interface{}, 0, len(allFiles))
-    
-    for _, file := range allFiles {
-        fileResults, err := a.analyzeFileIndentation(file)
-        if err != nil {
-            return nil, err
-        }
-        filesResults = append(filesResults, fileResults)
+    if _, fatal := Skipped(err); fatal != nil {
+        return IndentationResults{}, fmt.Errorf("error walking directory %s: %w", dirPath, fatal)
     }
     
-    results := map[string]interface{}{
-        "directory": dirPath,
-        "files":     filesResults,
+    return results, err
+}
+
+// tabWidth returns the number of spaces a tab counts for
+func (a *IdentationAnalyzerImpl) tabWidth() int {
+    if a.TabWidth > 0 {
+        return a.TabWidth
     }
-    
-    return results, nil
+    return config.Active.TabWidth
 }
 
-// calculateIndentationStats calculates indentation statistics for a slice of lines
-func (a *IdentationAnalyzerImpl) calculateIndentationStats(lines []string) IndentResult {
+// calculateIndentationStats calculates indentation statistics for the code lines of a file
+func (a *IdentationAnalyzerImpl) calculateIndentationStats(source *sourceFile) IndentResult {
+    tabWidth := a.tabWidth()
     maxIndent := 0
     totalIndent := 0
     indentCount := 0
     indentDistribution := make(map[int]int)
     usesSpaces := false
     usesTabs := false
+    var firstIndentChar rune
+    mixedLine := 0
     
-    for _, line := range lines {
-        trimmedLine := strings.TrimSpace(line)
-        if trimmedLine == "" || strings.HasPrefix(trimmedLine, "//") {
-            // Skip empty lines and comments
+    // Only lines where code starts carry meaningful indentation; this skips
+    // empty lines, comments and the inside of multi-line strings or templates
+    codeStartLines := source.tokenStartLines()
+
+    for index, line := range source.lines {
+        if !codeStartLines[index+1] {
             continue
         }
         
@@ -139,11 +157,17 @@ func (a *IdentationAnalyzerImpl) calculateIndentationStats(lines []string) Inden
                 indentLevel++
                 usesSpaces = true
             } else if char == '\t' {
-                indentLevel += 4 // Count tabs as 4 spaces (common convention)
+                indentLevel += tabWidth
                 usesTabs = true
             } else {
                 break
             }
+            
+            if firstIndentChar == 0 {
+                firstIndentChar = char
+            } else if char != firstIndentChar && mixedLine == 0 {
+                mixedLine = index + 1
+            }
         }
         
         indentDistribution[indentLevel]++
@@ -172,6 +196,13 @@ func (a *IdentationAnalyzerImpl) calculateIndentationStats(lines []string) Inden
         return distribution[i].Level < distribution[j].Level
     })
     
+    functions := functionNestings(source.syntax)
+    maxNesting, maxCognitive := 0, 0
+    for _, function := range functions {
+        maxNesting = max(maxNesting, function.NestingDepth)
+        maxCognitive = max(maxCognitive, function.CognitiveComplexity)
+    }
+    
     return IndentResult{
         MaxIndentLevel:     maxIndent,
         AverageIndentLevel: avgIndent,
@@ -179,5 +210,9 @@ func (a *IdentationAnalyzerImpl) calculateIndentationStats(lines []string) Inden
         UsesSpaces:         usesSpaces,
         UsesTabs:           usesTabs,
         MixedIndentation:   usesSpaces && usesTabs,
+        MixedIndentationLine: mixedLine,
+        MaxNestingDepth:        maxNesting,
+        MaxCognitiveComplexity: maxCognitive,
+        Functions:              functions,
     }
 }
\ No newline at end of file
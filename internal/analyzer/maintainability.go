@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+)
+
+type MaintainabilityResult struct {
+	Halstead  HalsteadMetrics    `json:"halstead"`
+	Functions []FunctionHalstead `json:"functions"`
+	// Complexity is the cyclomatic complexity of the whole file.
+	Complexity int `json:"complexity"`
+	Lines      int `json:"lines"`
+	// MaintainabilityIndex ranges from 0, hard to maintain, to 100.
+	MaintainabilityIndex float64 `json:"maintainabilityIndex"`
+}
+
+type MaintainabilityMap map[string]MaintainabilityResult
+
+type MaintainabilityAnalyzer interface {
+	CalculateMaintainability(filePath string) (MaintainabilityResult, error)
+	CalculateMaintainabilityByDirectory(directoryPath string) (MaintainabilityMap, MaintainabilityResult, error)
+}
+
+type MaintainabilityAnalyzerImpl struct{}
+
+func (a *MaintainabilityAnalyzerImpl) CalculateMaintainability(filePath string) (MaintainabilityResult, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return MaintainabilityResult{}, err
+	}
+
+	return maintainabilityOf(source), nil
+}
+
+// maintainabilityOf computes the Halstead metrics and the maintainability
+// index of a file and of each of its functions.
+func maintainabilityOf(source *sourceFile) MaintainabilityResult {
+	functions := make([]FunctionHalstead, 0, len(source.syntax.Functions))
+	for _, function := range source.syntax.Functions {
+		functions = append(functions, FunctionHalstead{
+			Name:            displayName(function),
+			StartLine:       function.StartLine,
+			EndLine:         function.EndLine,
+			HalsteadMetrics: halstead(source.code[function.BodyStart : function.BodyEnd+1]),
+		})
+	}
+
+	complexity := 1
+	for k := range source.code {
+		if isDecisionPoint(source.code, k) {
+			complexity++
+		}
+	}
+
+	result := MaintainabilityResult{
+		Halstead:   halstead(source.code),
+		Functions:  functions,
+		Complexity: complexity,
+		Lines:      countLines(source).TotalLines,
+	}
+	result.MaintainabilityIndex = maintainabilityIndex(result.Halstead.Volume, result.Complexity, result.Lines)
+
+	return result
+}
+
+func (a *MaintainabilityAnalyzerImpl) CalculateMaintainabilityByDirectory(directoryPath string) (MaintainabilityMap, MaintainabilityResult, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return nil, MaintainabilityResult{}, err
+	}
+
+	results := make(MaintainabilityMap)
+
+	err = analyzeSourceFiles(directoryPath, a.CalculateMaintainability, func(path, relativePath string, result MaintainabilityResult) {
+		results[relativePath] = result
+	})
+
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, MaintainabilityResult{}, fatal
+	}
+
+	return results, summarizeMaintainability(results), err
+}
+
+// summarizeMaintainability sums the size measures of the files, the
+// volume, effort, complexity and lines, and averages their difficulty and
+// maintainability index.
+func summarizeMaintainability(results MaintainabilityMap) MaintainabilityResult {
+	var summary MaintainabilityResult
+	if len(results) == 0 {
+		return summary
+	}
+
+	// floating-point sums depend on their order, which a map does not keep
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		result := results[name]
+		summary.Halstead.TotalOperators += result.Halstead.TotalOperators
+		summary.Halstead.TotalOperands += result.Halstead.TotalOperands
+		summary.Halstead.Length += result.Halstead.Length
+		summary.Halstead.Volume += result.Halstead.Volume
+		summary.Halstead.Effort += result.Halstead.Effort
+		summary.Halstead.Difficulty += result.Halstead.Difficulty
+		summary.Complexity += result.Complexity
+		summary.Lines += result.Lines
+		summary.MaintainabilityIndex += result.MaintainabilityIndex
+	}
+
+	files := float64(len(results))
+	summary.Halstead.Difficulty /= files
+	summary.MaintainabilityIndex /= files
+
+	return summary
+}
+
+// maintainabilityIndex combines the Halstead volume, the cyclomatic
+// complexity and the line count of a file into a score from 0 to 100,
+// using the normalized formula popularized by Visual Studio.
+func maintainabilityIndex(volume float64, complexity int, lines int) float64 {
+	index := 171 - 5.2*math.Log(max(volume, 1)) - 0.23*float64(complexity) - 16.2*math.Log(float64(max(lines, 1)))
+	return math.Max(0, math.Min(100, index*100/171))
+}
@@ -0,0 +1,59 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateMaintainability(t *testing.T) {
+	content := `function add(a, b) {
+	return a + b;
+}
+`
+	filePath := filepath.Join(t.TempDir(), "add.js")
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	maintainabilityAnalyzer := &analyzer.MaintainabilityAnalyzerImpl{}
+	result, err := maintainabilityAnalyzer.CalculateMaintainability(filePath)
+	assert.NoError(t, err)
+
+	// operators: function ( , { return + ;   operands: add a b a b
+	assert.Equal(t, 7, result.Halstead.DistinctOperators)
+	assert.Equal(t, 7, result.Halstead.TotalOperators)
+	assert.Equal(t, 3, result.Halstead.DistinctOperands)
+	assert.Equal(t, 5, result.Halstead.TotalOperands)
+	assert.InDelta(t, 12*3.3219, result.Halstead.Volume, 0.01)
+	assert.InDelta(t, 7.0/2*5/3, result.Halstead.Difficulty, 0.0001)
+
+	assert.Len(t, result.Functions, 1)
+	assert.Equal(t, "add", result.Functions[0].Name)
+	assert.Equal(t, 4, result.Functions[0].TotalOperators)
+
+	assert.Equal(t, 1, result.Complexity)
+	assert.Equal(t, 3, result.Lines)
+	assert.Greater(t, result.MaintainabilityIndex, 50.0)
+	assert.LessOrEqual(t, result.MaintainabilityIndex, 100.0)
+}
+
+func TestCalculateMaintainabilityByDirectory(t *testing.T) {
+	dir := t.TempDir()
+	small := "const answer = 42;\n"
+	large := strings.Repeat("function f(x) {\n\tif (x > 1 && x < 10) {\n\t\treturn x * 2;\n\t}\n\treturn x ? x - 1 : 0;\n}\n", 20)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "small.js"), []byte(small), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "large.js"), []byte(large), 0644))
+
+	maintainabilityAnalyzer := &analyzer.MaintainabilityAnalyzerImpl{}
+	results, summary, err := maintainabilityAnalyzer.CalculateMaintainabilityByDirectory(dir)
+	assert.NoError(t, err)
+
+	assert.Len(t, results, 2)
+	assert.Greater(t, results["small.js"].MaintainabilityIndex, results["large.js"].MaintainabilityIndex)
+	assert.Equal(t, 61, results["large.js"].Complexity)
+	assert.Equal(t, 121, summary.Lines)
+	assert.InDelta(t, (results["small.js"].MaintainabilityIndex+results["large.js"].MaintainabilityIndex)/2, summary.MaintainabilityIndex, 0.0001)
+}
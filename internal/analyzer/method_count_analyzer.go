@@ -1,13 +1,6 @@
 package analyzer
 
 import (
-	"bufio"
-	"go-cli-tool/internal/utils"
-	"io/fs"
-	"os"
-	"path/filepath"
-	"regexp"
-	"slices"
 	"strings"
 )
 
@@ -19,109 +12,66 @@ type MethodCountResult struct {
 type MethodCountMap map[string]MethodCountResult
 
 type MethodCountAnalyzer interface {
-	AnalyzeFile(filePath string) MethodCountResult
-	AnalyzeDirectory(dirPath string) (MethodCountMap, MethodCountResult)
+	AnalyzeFile(filePath string) (MethodCountResult, error)
+	AnalyzeDirectory(dirPath string) (MethodCountMap, MethodCountResult, error)
 }
 
 type MethodCountAnalyzerImpl struct{}
 
-var filesToIgnore = []string{".git", "node_modules"}
-
-func (a *MethodCountAnalyzerImpl) AnalyzeFile(filePath string) MethodCountResult {
-	file, err := os.Open(filePath)
+func (a *MethodCountAnalyzerImpl) AnalyzeFile(filePath string) (MethodCountResult, error) {
+	source, err := loadSourceFile(filePath)
 	if err != nil {
-		panic(err)
+		return MethodCountResult{}, err
 	}
-	defer file.Close()
-
-	var result MethodCountResult
-	scanner := bufio.NewScanner(file)
 
-	publicMethodRegex := regexp.MustCompile(`^\s*([a-zA-Z_$][a-zA-Z0-9_$]*)\s*\([^)]*\)\s*\{`)
-	privateMethodRegex := regexp.MustCompile(`^\s*([#_][a-zA-Z_$][a-zA-Z0-9_$]*)\s*\([^)]*\)\s*\{`)
-	functionRegex := regexp.MustCompile(`^\s*function\s+([a-zA-Z_$][a-zA-Z0-9_$]*)\s*\([^)]*\)\s*\{`)
-	constFunctionRegex := regexp.MustCompile(`^\s*const\s+([a-zA-Z_$][a-zA-Z0-9_$]*)\s*=\s*function`)
-	arrowFunctionRegex := regexp.MustCompile(`^\s*const\s+([a-zA-Z_$][a-zA-Z0-9_$]*)\s*=\s*\([^)]*\)\s*=>`)
+	return countMethods(source), nil
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+// countMethods counts the public and private named functions of a file.
+func countMethods(source *sourceFile) MethodCountResult {
+	var result MethodCountResult
 
-		// Pula comentários e linhas vazias
-		if strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*") || line == "" {
+	// Apenas funções com nome contam como métodos; callbacks anônimos são ignorados
+	for _, function := range source.syntax.Functions {
+		if function.Name == "" {
 			continue
 		}
 
-		// Verifica métodos privados primeiro (com # ou _)
-		if privateMethodRegex.MatchString(line) {
+		if isPrivateName(function.Name) || function.Access == "private" || function.Access == "protected" {
 			result.Private++
-		} else if publicMethodRegex.MatchString(line) && !strings.Contains(line, "function") {
-			// Métodos de classe públicos (dentro de classes)
+		} else {
 			result.Public++
-		} else if functionRegex.MatchString(line) {
-			// Funções declaradas (públicas por padrão)
-			result.Public++
-		} else if constFunctionRegex.MatchString(line) {
-			// const funcName = function() {}
-			funcName := constFunctionRegex.FindStringSubmatch(line)
-			if len(funcName) > 1 {
-				if strings.HasPrefix(funcName[1], "_") || strings.HasPrefix(funcName[1], "#") {
-					result.Private++
-				} else {
-					result.Public++
-				}
-			}
-		} else if arrowFunctionRegex.MatchString(line) {
-			// const funcName = () => {}
-			funcName := arrowFunctionRegex.FindStringSubmatch(line)
-			if len(funcName) > 1 {
-				if strings.HasPrefix(funcName[1], "_") || strings.HasPrefix(funcName[1], "#") {
-					result.Private++
-				} else {
-					result.Public++
-				}
-			}
 		}
 	}
 
 	return result
 }
 
-func (a *MethodCountAnalyzerImpl) AnalyzeDirectory(dirPath string) (MethodCountMap, MethodCountResult) {
-	if dirPath == "." {
-		var err error
-		dirPath, err = os.Getwd()
-		if err != nil {
-			panic(err)
-		}
-	} else {
-		var err error
-		dirPath, err = utils.ExpandPath(dirPath)
-		if err != nil {
-			panic(err)
-		}
+func (a *MethodCountAnalyzerImpl) AnalyzeDirectory(dirPath string) (MethodCountMap, MethodCountResult, error) {
+	dirPath, err := directoryRoot(dirPath)
+	if err != nil {
+		return nil, MethodCountResult{}, err
 	}
 
 	results := make(MethodCountMap)
 	var total MethodCountResult
 
-	filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if slices.Contains(filesToIgnore, d.Name()) && d.IsDir() {
-			return filepath.SkipDir
-		}
+	err = analyzeSourceFiles(dirPath, a.AnalyzeFile, func(path, relativePath string, count MethodCountResult) {
+		results[relativePath] = count
+		total.Public += count.Public
+		total.Private += count.Private
+	})
 
-		if filepath.Ext(path) == ".js" || filepath.Ext(path) == ".mjs" {
-			count := a.AnalyzeFile(path)
-			results[d.Name()] = count
-			total.Public += count.Public
-			total.Private += count.Private
-		}
+	if _, fatal := Skipped(err); fatal != nil {
+		return nil, MethodCountResult{}, fatal
+	}
 
-		return nil
-	})
+	return results, total, err
+}
 
-	return results, total
+// isPrivateName reports whether a method name follows the private naming
+// conventions (#private fields or the _underscore prefix). TypeScript
+// members are also private when declared private or protected.
+func isPrivateName(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasPrefix(name, "#")
 }
@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"go-cli-tool/internal/codeowners"
+	"go-cli-tool/internal/git"
+	"go-cli-tool/internal/utils"
+	"math"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// AuthorOwnership is the part of the code last changed by an author, as
+// git blame reports it.
+type AuthorOwnership struct {
+	Author string `json:"author"`
+	// Lines are the non-blank lines owned by the author.
+	Lines int `json:"lines"`
+	// Functions are the functions whose declaration line the author owns.
+	Functions    int `json:"functions"`
+	CommentLines int `json:"commentLines"`
+	// CommentDensity is the percentage of comment lines among the lines of
+	// the author.
+	CommentDensity float64 `json:"commentDensity"`
+}
+
+// OwnershipMap holds the ownership of each file or directory, from the
+// largest owner to the smallest.
+type OwnershipMap map[string][]AuthorOwnership
+
+// OwnershipResult is the ownership of the files of a directory.
+type OwnershipResult struct {
+	// Files are keyed by path relative to the analyzed directory.
+	Files OwnershipMap `json:"files"`
+	// Directories are keyed by path relative to the analyzed directory, "."
+	// being the directory itself, and add up all the files below them.
+	Directories OwnershipMap `json:"directories"`
+}
+
+type OwnershipAnalyzer interface {
+	OwnershipByFilePath(filePath string) ([]AuthorOwnership, error)
+	OwnershipByDirectory(directoryPath string) (OwnershipResult, error)
+	CodeOwnersByDirectory(directoryPath string) (CodeOwnersMap, error)
+}
+
+// CodeOwnersMap holds the owners the CODEOWNERS file assigns to each file.
+type CodeOwnersMap map[string][]string
+
+type OwnershipAnalyzerImpl struct{}
+
+// OwnershipByFilePath attributes the lines, functions and comments of the
+// file to their authors. The lines of a file git does not track yet are
+// attributed to git.NotCommitted.
+func (a *OwnershipAnalyzerImpl) OwnershipByFilePath(filePath string) ([]AuthorOwnership, error) {
+	source, err := loadSourceFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// an untracked file has no blame, all its lines are not committed yet
+	authors, _ := git.Blame(filePath)
+	authorOf := func(line int) string {
+		if line < 1 || line > len(authors) {
+			return git.NotCommitted
+		}
+		return authors[line-1]
+	}
+
+	owned := make(map[string]*AuthorOwnership)
+	owner := func(author string) *AuthorOwnership {
+		if owned[author] == nil {
+			owned[author] = &AuthorOwnership{Author: author}
+		}
+		return owned[author]
+	}
+
+	comments := source.commentLineSet()
+	for i, line := range source.lines {
+		if isEmptyLine(line) {
+			continue
+		}
+		ownership := owner(authorOf(i + 1))
+		ownership.Lines++
+		if comments[i+1] {
+			ownership.CommentLines++
+		}
+	}
+	for _, function := range source.syntax.Functions {
+		owner(authorOf(function.StartLine)).Functions++
+	}
+
+	return sortedOwnership(owned), nil
+}
+
+func (a *OwnershipAnalyzerImpl) OwnershipByDirectory(directoryPath string) (OwnershipResult, error) {
+	directoryPath, err := directoryRoot(directoryPath)
+	if err != nil {
+		return OwnershipResult{}, err
+	}
+
+	result := OwnershipResult{Files: make(OwnershipMap), Directories: make(OwnershipMap)}
+	directories := make(map[string]map[string]*AuthorOwnership)
+
+	err = analyzeSourceFiles(directoryPath, a.OwnershipByFilePath, func(filePath, relativePath string, owners []AuthorOwnership) {
+		result.Files[relativePath] = owners
+
+		for dir := path.Dir(relativePath); ; dir = path.Dir(dir) {
+			if directories[dir] == nil {
+				directories[dir] = make(map[string]*AuthorOwnership)
+			}
+			for _, ownership := range owners {
+				total := directories[dir][ownership.Author]
+				if total == nil {
+					total = &AuthorOwnership{Author: ownership.Author}
+					directories[dir][ownership.Author] = total
+				}
+				total.Lines += ownership.Lines
+				total.Functions += ownership.Functions
+				total.CommentLines += ownership.CommentLines
+			}
+			if dir == "." {
+				break
+			}
+		}
+	})
+
+	if _, fatal := Skipped(err); fatal != nil {
+		return OwnershipResult{}, fatal
+	}
+
+	for dir, owned := range directories {
+		result.Directories[dir] = sortedOwnership(owned)
+	}
+	return result, err
+}
+
+// CodeOwnersByDirectory returns the owners of the files of the directory,
+// keyed by relative path as the other directory analyses, from the CODEOWNERS
+// file of the repository. Files without owners are left out, and the map is
+// nil when the repository has no CODEOWNERS file.
+func (a *OwnershipAnalyzerImpl) CodeOwnersByDirectory(directoryPath string) (CodeOwnersMap, error) {
+	directoryPath, err := utils.ExpandPath(directoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := codeowners.Load(directoryPath)
+	if err != nil || owners == nil {
+		return nil, err
+	}
+	directoryPath, err = filepath.Abs(directoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(CodeOwnersMap)
+	err = walkSourceFiles(directoryPath, func(filePath, relativePath string) error {
+		if fileOwners := owners.Of(filePath); len(fileOwners) > 0 {
+			results[relativePath] = fileOwners
+		}
+		return nil
+	})
+	return results, err
+}
+
+// sortedOwnership computes the comment density of each author and sorts
+// them by owned lines, then by name.
+func sortedOwnership(owned map[string]*AuthorOwnership) []AuthorOwnership {
+	ownership := make([]AuthorOwnership, 0, len(owned))
+	for _, author := range owned {
+		if author.Lines > 0 {
+			author.CommentDensity = math.Round(float64(author.CommentLines)/float64(author.Lines)*10000) / 100
+		}
+		ownership = append(ownership, *author)
+	}
+
+	sort.Slice(ownership, func(i, j int) bool {
+		if ownership[i].Lines != ownership[j].Lines {
+			return ownership[i].Lines > ownership[j].Lines
+		}
+		return ownership[i].Author < ownership[j].Author
+	})
+	return ownership
+}
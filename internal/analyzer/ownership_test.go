@@ -0,0 +1,56 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/git"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func commitAs(t *testing.T, dir string, author string) {
+	_, err := git.Run(dir, "add", "-A")
+	assert.NoError(t, err)
+	_, err = git.Run(dir, "-c", "user.name="+author, "-c", "user.email="+author+"@example.com", "commit", "-q", "-m", "change")
+	assert.NoError(t, err)
+}
+
+func TestOwnershipByDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "api"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "src", "api", "users.js"), []byte("// lists the users\nfunction list() {\n\treturn [];\n}\n"), 0644))
+	commitAs(t, dir, "Ana")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "src", "api", "users.js"), []byte("// lists the users\nfunction list() {\n\treturn [];\n}\n\nfunction add(user) {\n\treturn user;\n}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "src", "index.js"), []byte("const x = 1;\n"), 0644))
+	commitAs(t, dir, "Bruno")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "draft.js"), []byte("// draft\n"), 0644))
+
+	ownershipAnalyzer := &analyzer.OwnershipAnalyzerImpl{}
+	result, err := ownershipAnalyzer.OwnershipByDirectory(dir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []analyzer.AuthorOwnership{
+		{Author: "Ana", Lines: 4, Functions: 1, CommentLines: 1, CommentDensity: 25},
+		{Author: "Bruno", Lines: 3, Functions: 1},
+	}, result.Files["src/api/users.js"])
+
+	assert.Equal(t, []analyzer.AuthorOwnership{
+		{Author: "Ana", Lines: 4, Functions: 1, CommentLines: 1, CommentDensity: 25},
+		{Author: "Bruno", Lines: 4, Functions: 1},
+	}, result.Directories["src"], "ties are sorted by author")
+
+	// untracked files are not committed yet
+	assert.Equal(t, []analyzer.AuthorOwnership{
+		{Author: "Ana", Lines: 4, Functions: 1, CommentLines: 1, CommentDensity: 25},
+		{Author: "Bruno", Lines: 4, Functions: 1},
+		{Author: git.NotCommitted, Lines: 1, CommentLines: 1, CommentDensity: 100},
+	}, result.Directories["."])
+}
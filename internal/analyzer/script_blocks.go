@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	scriptBlockRegex = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+	scriptLangRegex  = regexp.MustCompile(`(?i)\blang\s*=\s*["']?([a-z]+)`)
+)
+
+// extractScriptBlocks keeps only the contents of the <script> blocks of a
+// Vue or Svelte single-file component. Everything else is blanked while
+// line breaks are preserved, so line numbers still point into the original
+// file. It also returns the lowercased lang attribute of the blocks, if any.
+func extractScriptBlocks(content string) (string, string) {
+	var builder strings.Builder
+	builder.Grow(len(content))
+
+	lang := ""
+	last := 0
+	for _, match := range scriptBlockRegex.FindAllStringSubmatchIndex(content, -1) {
+		builder.WriteString(blankExceptNewlines(content[last:match[4]]))
+		builder.WriteString(content[match[4]:match[5]])
+		last = match[5]
+
+		if attributes := scriptLangRegex.FindStringSubmatch(content[match[2]:match[3]]); attributes != nil {
+			lang = strings.ToLower(attributes[1])
+		}
+	}
+	builder.WriteString(blankExceptNewlines(content[last:]))
+
+	return builder.String(), lang
+}
+
+func blankExceptNewlines(text string) string {
+	return strings.Repeat("\n", strings.Count(text, "\n"))
+}
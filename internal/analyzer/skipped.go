@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// SkippedFile is a file a directory analysis could not analyze.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+func newSkippedFile(path string, err error) SkippedFile {
+	// unwrap a *fs.PathError: Path already names the file, so the reason
+	// only keeps the underlying error, e.g. "permission denied"
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return SkippedFile{Path: path, Reason: err.Error()}
+}
+
+// SkippedFilesError is returned by the directory analyses that skipped some
+// files. Their results are complete for all the other files.
+type SkippedFilesError struct {
+	Files []SkippedFile
+}
+
+func (e *SkippedFilesError) Error() string {
+	if len(e.Files) == 1 {
+		return fmt.Sprintf("%s was skipped: %s", e.Files[0].Path, e.Files[0].Reason)
+	}
+	return fmt.Sprintf("%d files were skipped, the first is %s: %s", len(e.Files), e.Files[0].Path, e.Files[0].Reason)
+}
+
+// skippedFiles returns the error reporting the skipped files, or nil when
+// no file was skipped.
+func skippedFiles(files []SkippedFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+	return &SkippedFilesError{Files: files}
+}
+
+// Skipped splits the error of a directory analysis into the files it
+// skipped, and the error that stopped it, which is nil when the analysis
+// went through.
+func Skipped(err error) ([]SkippedFile, error) {
+	var skipped *SkippedFilesError
+	if errors.As(err, &skipped) {
+		return skipped.Files, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,74 @@
+package analyzer_test
+
+import (
+	"errors"
+	"go-cli-tool/internal/analyzer"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeUnreadableFile adds a file the analyses cannot read to the
+// directory: a link to a file that does not exist, which cannot be read
+// even by root.
+func writeUnreadableFile(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "locked.js")
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "removed.js"), path))
+	return path
+}
+
+func TestAnalyzeDirectorySkipsUnreadableFiles(t *testing.T) {
+	dir := writeProject(t, 3)
+	locked := writeUnreadableFile(t, dir)
+
+	analysis, err := (&analyzer.DirectoryAnalyzerImpl{}).AnalyzeDirectory(dir)
+
+	skipped, fatal := analyzer.Skipped(err)
+	assert.NoError(t, fatal)
+	assert.Equal(t, []analyzer.SkippedFile{{Path: locked, Reason: "no such file or directory"}}, skipped)
+	assert.Len(t, analysis.Lines, 3)
+	assert.NotContains(t, analysis.Lines, "locked.js")
+}
+
+func TestCountLinesByDirectorySkipsUnreadableFiles(t *testing.T) {
+	dir := writeProject(t, 2)
+	locked := writeUnreadableFile(t, dir)
+
+	lines, _, err := (&analyzer.CountLinesAnalyzerImpl{}).CountLinesByDirectory(dir)
+
+	var skippedErr *analyzer.SkippedFilesError
+	assert.True(t, errors.As(err, &skippedErr))
+	assert.Equal(t, locked+" was skipped: no such file or directory", err.Error())
+	assert.Len(t, lines, 2)
+}
+
+func TestCountLinesByFilePathReturnsReadErrors(t *testing.T) {
+	_, err := (&analyzer.CountLinesAnalyzerImpl{}).CountLinesByFilePath(filepath.Join(t.TempDir(), "missing.js"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDirectoryAnalysesFailOnMissingDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+
+	_, err := (&analyzer.DirectoryAnalyzerImpl{}).AnalyzeDirectory(dir)
+	assert.EqualError(t, err, "directory "+dir+" does not exist")
+
+	skipped, fatal := analyzer.Skipped(err)
+	assert.Empty(t, skipped)
+	assert.Equal(t, err, fatal)
+
+	_, _, err = (&analyzer.CountCommentsAnalyzerImpl{}).CountCommentsByDirectory(dir)
+	assert.EqualError(t, err, "directory "+dir+" does not exist")
+}
+
+func TestSkippedFilesErrorMessage(t *testing.T) {
+	err := &analyzer.SkippedFilesError{Files: []analyzer.SkippedFile{
+		{Path: "a.js", Reason: "permission denied"},
+		{Path: "b.js", Reason: "is a directory"},
+	}}
+
+	assert.Equal(t, "2 files were skipped, the first is a.js: permission denied", err.Error())
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"go-cli-tool/internal/parser"
+	"go-cli-tool/internal/policies"
+	"go-cli-tool/internal/tokenizer"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sourceFile is the tokenized view of a file shared by all analyzers, so
+// that every metric is derived from the same understanding of the code.
+type sourceFile struct {
+	path   string
+	lines  []string
+	tokens []tokenizer.Token
+	// code holds the tokens without comments
+	code   []tokenizer.Token
+	syntax *parser.File
+}
+
+func loadSourceFile(filePath string) (*sourceFile, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSourceFile(filePath, string(content)), nil
+}
+
+func newSourceFile(filePath string, content string) *sourceFile {
+	dialect := parser.JavaScript
+	options := tokenizer.Options{JSX: true}
+
+	switch {
+	case policies.IsComponentFileExtension(filePath):
+		var lang string
+		content, lang = extractScriptBlocks(content)
+		if lang == "ts" || lang == "typescript" || lang == "tsx" {
+			dialect = parser.TypeScript
+			options.JSX = lang == "tsx"
+		}
+	case policies.IsTypeScriptFileExtension(filePath):
+		dialect = parser.TypeScript
+		options.JSX = filepath.Ext(filePath) == ".tsx"
+	}
+
+	tokens := tokenizer.TokenizeWith(content, options)
+	syntax := parser.Parse(tokens, dialect)
+
+	return &sourceFile{
+		path:   filePath,
+		lines:  splitLines(content),
+		tokens: tokens,
+		code:   syntax.Tokens,
+		syntax: syntax,
+	}
+}
+
+// splitLines splits content the same way bufio.ScanLines does: a trailing
+// newline does not produce an extra empty line and "\r\n" is handled.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines
+}
+
+// codeLines returns the set of lines that contain at least part of a
+// non-comment token.
+func (f *sourceFile) codeLines() map[int]bool {
+	lines := make(map[int]bool)
+	for _, token := range f.code {
+		for line := token.Line; line <= token.EndLine; line++ {
+			lines[line] = true
+		}
+	}
+	return lines
+}
+
+// commentLines returns the number of lines holding only comments, so a
+// trailing `// note` after code or a `//` inside a string never counts.
+func (f *sourceFile) commentLines() int {
+	return len(f.commentLineSet())
+}
+
+// commentLineSet returns the set of lines holding only comments.
+func (f *sourceFile) commentLineSet() map[int]bool {
+	code := f.codeLines()
+	comments := make(map[int]bool)
+
+	for _, token := range f.tokens {
+		if !token.IsComment() {
+			continue
+		}
+		for line := token.Line; line <= token.EndLine; line++ {
+			if !code[line] {
+				comments[line] = true
+			}
+		}
+	}
+
+	return comments
+}
+
+// tokenStartLines returns the set of lines on which a non-comment token
+// starts. Continuation lines of multi-line strings or templates are not
+// included.
+func (f *sourceFile) tokenStartLines() map[int]bool {
+	lines := make(map[int]bool)
+	for _, token := range f.code {
+		lines[token.Line] = true
+	}
+	return lines
+}
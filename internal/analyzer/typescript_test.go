@@ -0,0 +1,72 @@
+package analyzer_test
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeScriptFilesAreAnalyzed(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"service.ts": `import { Observable } from 'rxjs';
+
+export interface Options {
+	retries: number;
+	onError?: (error: Error) => void;
+}
+
+export enum Mode { Fast, Safe }
+
+export type Callback = (value: string) => void;
+
+export class Service {
+	constructor(private readonly options: Options) {}
+
+	public run(mode: Mode): void {
+		this.retry(mode);
+	}
+
+	private retry(mode: Mode): void {}
+
+	protected log(message: string): void {}
+}
+`,
+		"view.tsx": `export function View(props: { title: string }): string {
+	return props.title;
+}
+`,
+	}
+
+	for fileName, content := range files {
+		err := os.WriteFile(filepath.Join(tmpDir, fileName), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	classFuncAnalyzer := &analyzer.CountClassAndFunctionsImpl{}
+	results, total, err := classFuncAnalyzer.CountClassesAndFunctionsByDirectory(tmpDir)
+	assert.NoError(t, err)
+
+	assert.Len(t, results, 2, "Expected both TypeScript files to be analyzed")
+	assert.Equal(t, 5, total.Functions, "Expected 5 functions in total")
+	assert.Equal(t, 1, total.Classes, "Expected 1 class in total")
+	assert.Equal(t, 1, total.Interfaces, "Expected 1 interface in total")
+	assert.Equal(t, 1, total.Enums, "Expected 1 enum in total")
+	assert.Equal(t, 1, total.TypeAliases, "Expected 1 type alias in total")
+
+	methodAnalyzer := &analyzer.MethodCountAnalyzerImpl{}
+	methods, err := methodAnalyzer.AnalyzeFile(filepath.Join(tmpDir, "service.ts"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, methods.Public, "Expected constructor and run to be public")
+	assert.Equal(t, 2, methods.Private, "Expected private and protected methods to be private")
+
+	dependenciesAnalyzer := &analyzer.CountDependenciesAnalyzerImpl{}
+	dependencies, err := dependenciesAnalyzer.CountDependenciesByDirectory(tmpDir)
+	assert.NoError(t, err)
+	assert.Contains(t, dependencies, "service.ts", "Expected service.ts to be scanned for dependencies")
+}
@@ -0,0 +1,266 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/ignore"
+	"go-cli-tool/internal/utils"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// walkSourceFiles walks the directory tree rooted at root and calls visit
+// for every source file, with its path and its slash-separated path relative
+// to root, skipping the entries excluded by the active
+// configuration or by the .gitignore, .eslintignore and .gocliignore files
+// of the project. Every directory analysis goes through it so that all the
+// commands agree on which files make up a project.
+//
+// When the configuration restricts the analysis to a set of files, as with
+// --changed-since, only these files are visited and only the directories
+// leading to them are walked.
+//
+// The entries below root that cannot be read are skipped, and returned in a
+// *SkippedFilesError once the walk is over.
+func walkSourceFiles(root string, visit func(path, relativePath string) error) error {
+	return walkSourceFilesWith(config.Active, root, visit)
+}
+
+// walkSourceFilesWith walks the directory tree as walkSourceFiles does,
+// with the given settings in place of the active configuration.
+func walkSourceFilesWith(settings config.Config, root string, visit func(path, relativePath string) error) error {
+	ignored := ignore.NewMatcher(root)
+	selection := newFileSelection(root, settings.Files)
+	var skipped []SkippedFile
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			skipped = append(skipped, newSkippedFile(path, err))
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		if relativePath == "." {
+			ignored.LoadDir(path)
+			return nil
+		}
+		relativePath = filepath.ToSlash(relativePath)
+
+		if settings.Excludes(relativePath) || ignored.Ignored(path, d.IsDir()) || !selection.selects(relativePath, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			ignored.LoadDir(path)
+		}
+
+		if d.IsDir() || !settings.Includes(relativePath) {
+			return nil
+		}
+		return visit(path, relativePath)
+	})
+	if err != nil {
+		return err
+	}
+	return skippedFiles(skipped)
+}
+
+// SourceFiles returns the paths of the source files of the directory tree
+// rooted at root, in the order every directory analysis visits them.
+// The paths are returned along with a *SkippedFilesError when some entries
+// could not be read.
+func SourceFiles(root string) ([]string, error) {
+	var paths []string
+	err := walkSourceFiles(root, func(path, relativePath string) error {
+		paths = append(paths, path)
+		return nil
+	})
+	return paths, err
+}
+
+// analyzeSourceFiles walks the directory tree rooted at root as
+// walkSourceFiles does, and calls analyze for every source file on a pool of
+// config.Active.Jobs workers. The results are then handed to merge in the
+// order of the walk, so that the outcome does not depend on which worker
+// finished first. A panic in analyze is raised again in the caller.
+//
+// The directory analyses key the results of each file by the relative path
+// merge receives, so that the files with the same name in different
+// directories are reported apart.
+//
+// The files analyze fails on are not merged: the analysis goes on with the
+// other files, and the failed ones are returned in a *SkippedFilesError,
+// along with the entries the walk could not read.
+func analyzeSourceFiles[T any](root string, analyze func(path string) (T, error), merge func(path, relativePath string, result T)) error {
+	return analyzeSourceFilesWith(context.Background(), config.Active, root, analyze, merge)
+}
+
+// analyzeSourceFilesWith analyzes the source files as analyzeSourceFiles
+// does, with the given settings in place of the active configuration. Once
+// ctx is done, the files not analyzed yet are left out and the error of ctx
+// is returned.
+func analyzeSourceFilesWith[T any](ctx context.Context, settings config.Config, root string, analyze func(path string) (T, error), merge func(path, relativePath string, result T)) error {
+	type entry struct {
+		path         string
+		relativePath string
+	}
+
+	var entries []entry
+	err := walkSourceFilesWith(settings, root, func(path, relativePath string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entries = append(entries, entry{path, relativePath})
+		return nil
+	})
+	skipped, err := Skipped(err)
+	if err != nil {
+		return err
+	}
+
+	results := make([]T, len(entries))
+	errs := make([]error, len(entries))
+	indexes := make(chan int)
+	var failure interface{}
+	var failed sync.Once
+	var workers sync.WaitGroup
+
+	for n := min(jobs(settings), len(entries)); n > 0; n-- {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					failed.Do(func() { failure = r })
+					// keep draining so that the walk is not blocked
+					for range indexes {
+					}
+				}
+			}()
+			for i := range indexes {
+				results[i], errs[i] = analyze(entries[i].path)
+			}
+		}()
+	}
+
+dispatch:
+	for i := range entries {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	workers.Wait()
+
+	if failure != nil {
+		panic(failure)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i, entry := range entries {
+		if errs[i] != nil {
+			skipped = append(skipped, newSkippedFile(entry.path, errs[i]))
+			continue
+		}
+		merge(entry.path, entry.relativePath, results[i])
+	}
+	return skippedFiles(skipped)
+}
+
+// directoryRoot resolves the directory of a directory analysis: "." is the
+// working directory and "~/" the home directory.
+func directoryRoot(directoryPath string) (string, error) {
+	var err error
+	if directoryPath == "." {
+		directoryPath, err = os.Getwd()
+	} else {
+		directoryPath, err = utils.ExpandPath(directoryPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(directoryPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("directory %s does not exist", directoryPath)
+	}
+	return directoryPath, nil
+}
+
+// jobs returns the number of workers of the analyses.
+func jobs(settings config.Config) int {
+	if settings.Jobs > 0 {
+		return settings.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// fileSelection holds the files an analysis is restricted to, and the
+// directories containing them, relative to the analyzed directory.
+type fileSelection struct {
+	files       map[string]bool
+	directories map[string]bool
+}
+
+// newFileSelection returns the selection of the given absolute paths under
+// root, or nil when the analysis is not restricted.
+func newFileSelection(root string, files []string) *fileSelection {
+	if files == nil {
+		return nil
+	}
+
+	// git reports the paths with the symbolic links resolved
+	base, err := filepath.Abs(root)
+	if err == nil {
+		if resolved, err := filepath.EvalSymlinks(base); err == nil {
+			base = resolved
+		}
+	}
+
+	selection := &fileSelection{files: make(map[string]bool), directories: make(map[string]bool)}
+	for _, file := range files {
+		relativePath, err := filepath.Rel(base, file)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			continue
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		selection.files[relativePath] = true
+		for dir := path.Dir(relativePath); dir != "."; dir = path.Dir(dir) {
+			selection.directories[dir] = true
+		}
+	}
+	return selection
+}
+
+// selects tells whether the walk visits a file, or descends into a
+// directory, given by its slash-separated path relative to the root.
+func (s *fileSelection) selects(relativePath string, isDir bool) bool {
+	if s == nil {
+		return true
+	}
+	if isDir {
+		return s.directories[relativePath]
+	}
+	return s.files[relativePath]
+}
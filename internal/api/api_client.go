@@ -23,7 +23,8 @@ func NewAPIClient(baseURL, token string) *APIClient {
     }
 }
 
-func (c *APIClient) SendMetrics(payload map[string]interface{}) error {
+// SendMetrics posts the payload, encoded as JSON, to the API.
+func (c *APIClient) SendMetrics(payload interface{}) error {
 
     jsonData, err := json.Marshal(payload)
     if err != nil {
@@ -2,6 +2,9 @@ package api
 
 import (
 	"fmt"
+	"go-cli-tool/internal/models"
+	"go-cli-tool/internal/results"
+	"path"
 	"time"
 )
 
@@ -52,4 +55,47 @@ func (f *MetricsFormatter) FormatMetrics(rawData map[string]interface{}) map[str
 
     result["recorded_at"] = time.Now().UTC().Format(time.RFC3339)
     return result
-}
\ No newline at end of file
+}
+
+// FormatReport converts a versioned analyze report into the payload of the
+// metrics API.
+func (f *MetricsFormatter) FormatReport(report results.Report) models.CreateMetricDtoWithStringDate {
+    totals := report.Totals
+    payload := models.CreateMetricDtoWithStringDate{
+        RecordedAt:        time.Now().UTC().Format(time.RFC3339),
+        Lines:             totals.Lines,
+        Functions:         totals.Functions,
+        Classes:           totals.Classes,
+        Comments:          totals.CommentLines,
+        CommentPercentage: fmt.Sprintf("%.2f%%", totals.CommentPercentage),
+        Dependencies: models.DependencyDto{
+            Dependencies:      totals.Dependencies,
+            NativeModules:     totals.NativeModules,
+            TotalDependencies: len(totals.Dependencies),
+        },
+        Indentation: models.IdentationDto{
+            Directory: report.Root,
+            Files:     make([]models.IndentationFileDto, 0, len(report.Files)),
+        },
+    }
+
+    for _, file := range report.Files {
+        distribution := make([]models.IndentDistributionDto, 0, len(file.Indentation.Distribution))
+        for _, level := range file.Indentation.Distribution {
+            distribution = append(distribution, models.IndentDistributionDto{Level: level.Level, Count: level.Count})
+        }
+        payload.Indentation.Files = append(payload.Indentation.Files, models.IndentationFileDto{
+            Filename: path.Base(file.Path),
+            Path:     file.Path,
+            Stats: models.IndentationFileStatsDto{
+                MaxIndentLevel:     file.Indentation.MaxLevel,
+                AverageIndentLevel: file.Indentation.AverageLevel,
+                IndentDistribution: distribution,
+                UsesSpaces:         file.Indentation.UsesSpaces,
+                UsesTabs:           file.Indentation.UsesTabs,
+                MixedIndentation:   file.Indentation.Mixed,
+            },
+        })
+    }
+    return payload
+}
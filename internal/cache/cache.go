@@ -0,0 +1,146 @@
+// Package cache stores the results of the analysis of files on disk, so
+// that a new analysis only analyzes again the files that changed.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-cli-tool/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// DirName is the directory of the cache in the state directory.
+const DirName = "cache"
+
+// Cache holds results keyed by the content of the analyzed file, the
+// version of the tool and the configuration, so that upgrading the tool or
+// changing the configuration never reuses stale results.
+type Cache struct {
+	Dir       string
+	namespace []byte
+	hits      atomic.Int64
+	misses    atomic.Int64
+}
+
+// New returns the cache of the state directory for the given version of
+// the tool and configuration.
+func New(stateDir string, version string, settings config.Config) *Cache {
+	hash := sha256.New()
+	hash.Write([]byte(version))
+	hash.Write([]byte{0})
+	// the fields set from the command line are not encoded
+	settingsJSON, _ := json.Marshal(settings)
+	hash.Write(settingsJSON)
+
+	return &Cache{Dir: filepath.Join(stateDir, DirName), namespace: hash.Sum(nil)}
+}
+
+// Key returns the key of the results of a file with the given content.
+func (c *Cache) Key(content []byte) string {
+	hash := sha256.New()
+	hash.Write(c.namespace)
+	hash.Write(content)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key[:2], key+".json")
+}
+
+// Get decodes the results stored under key into value, and reports
+// whether they were found.
+func (c *Cache) Get(key string, value interface{}) bool {
+	content, err := os.ReadFile(c.path(key))
+	if err == nil && json.Unmarshal(content, value) == nil {
+		c.hits.Add(1)
+		return true
+	}
+	c.misses.Add(1)
+	return false
+}
+
+// Put stores value under key. Concurrent writers of the same key are safe,
+// the entry is written to a temporary file first and then renamed.
+func (c *Cache) Put(key string, value interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// Hits returns the number of results found in the cache so far.
+func (c *Cache) Hits() int64 {
+	return c.hits.Load()
+}
+
+// Misses returns the number of results not found in the cache so far.
+func (c *Cache) Misses() int64 {
+	return c.misses.Load()
+}
+
+// Stats describes the content of a cache directory.
+type Stats struct {
+	Dir     string `json:"dir"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// ReadStats counts the entries of the cache directory of the state
+// directory and their size.
+func ReadStats(stateDir string) (Stats, error) {
+	stats := Stats{Dir: filepath.Join(stateDir, DirName)}
+
+	err := filepath.WalkDir(stats.Dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == stats.Dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		return nil
+	})
+	return stats, err
+}
+
+// Clear removes the cache directory of the state directory and returns
+// what it held.
+func Clear(stateDir string) (Stats, error) {
+	stats, err := ReadStats(stateDir)
+	if err != nil {
+		return stats, err
+	}
+	return stats, os.RemoveAll(stats.Dir)
+}
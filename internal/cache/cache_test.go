@@ -0,0 +1,63 @@
+package cache_test
+
+import (
+	"go-cli-tool/internal/cache"
+	"go-cli-tool/internal/config"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type result struct {
+	Lines int
+	Names []string
+}
+
+func TestCachePutAndGet(t *testing.T) {
+	stateDir := filepath.Join(t.TempDir(), ".gocli")
+	c := cache.New(stateDir, "1.0.0", config.Default())
+
+	key := c.Key([]byte("const a = 1;\n"))
+	var found result
+	assert.False(t, c.Get(key, &found))
+
+	assert.NoError(t, c.Put(key, result{Lines: 1, Names: []string{"a"}}))
+	assert.True(t, c.Get(key, &found))
+	assert.Equal(t, result{Lines: 1, Names: []string{"a"}}, found)
+	assert.Equal(t, int64(1), c.Hits())
+	assert.Equal(t, int64(1), c.Misses())
+
+	stats, err := cache.ReadStats(stateDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Positive(t, stats.Bytes)
+
+	cleared, err := cache.Clear(stateDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cleared.Entries)
+	assert.False(t, c.Get(key, &found))
+
+	stats, err = cache.ReadStats(stateDir)
+	assert.NoError(t, err)
+	assert.Zero(t, stats.Entries)
+}
+
+func TestCacheKeyDependsOnVersionAndConfig(t *testing.T) {
+	stateDir := t.TempDir()
+	content := []byte("const a = 1;\n")
+	key := cache.New(stateDir, "1.0.0", config.Default()).Key(content)
+
+	assert.Equal(t, key, cache.New(stateDir, "1.0.0", config.Default()).Key(content))
+	assert.NotEqual(t, key, cache.New(stateDir, "1.0.0", config.Default()).Key([]byte("const a = 2;\n")))
+	assert.NotEqual(t, key, cache.New(stateDir, "1.1.0", config.Default()).Key(content))
+
+	settings := config.Default()
+	settings.TabWidth = 8
+	assert.NotEqual(t, key, cache.New(stateDir, "1.0.0", settings).Key(content))
+
+	// the settings of the command line do not change the results of a file
+	settings = config.Default()
+	settings.Jobs = 4
+	assert.Equal(t, key, cache.New(stateDir, "1.0.0", settings).Key(content))
+}
@@ -0,0 +1,125 @@
+// Package codeowners reads the CODEOWNERS file of a repository, which
+// assigns the files of the repository to the users and teams owning them.
+package codeowners
+
+import (
+	"bufio"
+	"go-cli-tool/internal/git"
+	"go-cli-tool/internal/ignore"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Locations are the paths of the CODEOWNERS file relative to the top of
+// the repository, in the order GitHub looks for them.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// rule assigns the files matching a pattern to their owners.
+type rule struct {
+	pattern ignore.Pattern
+	owners  []string
+}
+
+// Owners holds the rules of a CODEOWNERS file. As in GitHub, the last
+// matching rule decides, and a rule without owners leaves the files it
+// matches unowned.
+type Owners struct {
+	// Top is the directory the patterns are relative to.
+	Top   string
+	rules []rule
+}
+
+// Parse reads the rules of a CODEOWNERS file whose patterns are relative
+// to top.
+func Parse(r io.Reader, top string) (*Owners, error) {
+	owners := &Owners{Top: top}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if comment := strings.Index(line, " #"); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern, ok := ignore.ParsePattern(fields[0])
+		if !ok {
+			continue
+		}
+		owners.rules = append(owners.rules, rule{pattern: pattern, owners: fields[1:]})
+	}
+	return owners, scanner.Err()
+}
+
+// Load reads the CODEOWNERS file of the repository containing dir. It
+// returns nil when the repository has none, or dir is not in a repository.
+func Load(dir string) (*Owners, error) {
+	top, err := git.TopLevel(dir)
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, location := range Locations {
+		file, err := os.Open(filepath.Join(top, filepath.FromSlash(location)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return Parse(file, top)
+	}
+	return nil, nil
+}
+
+// Of returns the owners of the file at the given path, or nil when the file
+// has none or is outside of Top. Relative paths are relative to Top.
+func (o *Owners) Of(filePath string) []string {
+	if o == nil {
+		return nil
+	}
+
+	if filepath.IsAbs(filePath) {
+		// git reports the top with the symbolic links resolved
+		if resolved, err := filepath.EvalSymlinks(filePath); err == nil {
+			filePath = resolved
+		}
+		relative, err := filepath.Rel(o.Top, filePath)
+		if err != nil {
+			return nil
+		}
+		filePath = relative
+	}
+	filePath = filepath.ToSlash(filePath)
+	if filePath == ".." || strings.HasPrefix(filePath, "../") {
+		return nil
+	}
+
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if o.rules[i].matches(filePath) {
+			return o.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// matches reports whether the rule matches the file or one of the
+// directories containing it, which a pattern such as "docs/" owns.
+func (r rule) matches(filePath string) bool {
+	if r.pattern.Matches(filePath, false) {
+		return true
+	}
+	for dir := path.Dir(filePath); dir != "."; dir = path.Dir(dir) {
+		if r.pattern.Matches(dir, true) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package codeowners_test
+
+import (
+	"go-cli-tool/internal/codeowners"
+	"go-cli-tool/internal/git"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnersOf(t *testing.T) {
+	content := `# default owners
+*       @org/core
+*.ts    @org/typescript  # typed code
+/src/api/ @org/api @lead
+docs/   @org/docs
+/src/api/generated/
+`
+	owners, err := codeowners.Parse(strings.NewReader(content), "/repo")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"@org/core"}, owners.Of("index.js"))
+	assert.Equal(t, []string{"@org/typescript"}, owners.Of("lib/types.ts"))
+	assert.Equal(t, []string{"@org/api", "@lead"}, owners.Of("src/api/users/handler.js"))
+	assert.Equal(t, []string{"@org/api", "@lead"}, owners.Of("/repo/src/api/routes.ts"))
+	assert.Equal(t, []string{"@org/docs"}, owners.Of("packages/web/docs/intro.js"))
+
+	// a rule without owners leaves its files unowned
+	assert.Empty(t, owners.Of("src/api/generated/client.js"))
+	assert.Empty(t, owners.Of("/elsewhere/index.js"))
+}
+
+func TestLoadWithoutRepository(t *testing.T) {
+	owners, err := codeowners.Load(t.TempDir())
+	assert.NoError(t, err)
+	assert.Nil(t, owners)
+	assert.Nil(t, owners.Of("index.js"))
+}
+
+func TestLoadFromGitHubDirectory(t *testing.T) {
+	top := t.TempDir()
+	if _, err := git.Run(top, "init", "-q"); err != nil {
+		t.Skip("git is not available")
+	}
+	assert.NoError(t, os.MkdirAll(filepath.Join(top, ".github"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(top, ".github", "CODEOWNERS"), []byte("* @org/web\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(top, "CODEOWNERS"), []byte("* @org/ignored\n"), 0644))
+
+	owners, err := codeowners.Load(top)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"@org/web"}, owners.Of(filepath.Join(top, "app.js")))
+}
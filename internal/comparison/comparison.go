@@ -0,0 +1,421 @@
+// Package comparison compares two JSON reports written by the analyze
+// command, to show what changed between them rather than absolute totals.
+package comparison
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-cli-tool/internal/results"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metrics are the metrics compared between two reports, for the whole
+// analysis or for a file.
+type Metrics struct {
+	Lines               int     `json:"lines"`
+	Comments            int     `json:"comments"`
+	Functions           int     `json:"functions"`
+	Classes             int     `json:"classes"`
+	PublicMethods       int     `json:"publicMethods"`
+	PrivateMethods      int     `json:"privateMethods"`
+	AverageFunctionSize float64 `json:"averageFunctionSize"`
+}
+
+// Snapshot is the content of an analyze report that can be compared.
+type Snapshot struct {
+	Path   string
+	Totals Metrics
+	// Files holds the metrics of each file, which only detailed reports
+	// and reports of a single file have.
+	Files         map[string]Metrics
+	Dependencies  []string
+	NativeModules []string
+}
+
+// Metric is a metric of the comparison.
+type Metric struct {
+	Key   string
+	Title string
+	Value func(Metrics) float64
+}
+
+// ComparedMetrics lists the compared metrics, in display order.
+var ComparedMetrics = []Metric{
+	{"lines", "Lines", func(m Metrics) float64 { return float64(m.Lines) }},
+	{"comments", "Comments", func(m Metrics) float64 { return float64(m.Comments) }},
+	{"functions", "Functions", func(m Metrics) float64 { return float64(m.Functions) }},
+	{"classes", "Classes", func(m Metrics) float64 { return float64(m.Classes) }},
+	{"public_methods", "Public methods", func(m Metrics) float64 { return float64(m.PublicMethods) }},
+	{"private_methods", "Private methods", func(m Metrics) float64 { return float64(m.PrivateMethods) }},
+	{"average_function_size", "Average function size", func(m Metrics) float64 { return m.AverageFunctionSize }},
+}
+
+// Delta is the change of a metric.
+type Delta struct {
+	Metric string  `json:"metric"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Change float64 `json:"change"`
+}
+
+// Status tells how a file changed between the reports.
+type Status string
+
+const (
+	Added    Status = "added"
+	Removed  Status = "removed"
+	Modified Status = "modified"
+)
+
+// FileChange is a file whose metrics changed.
+type FileChange struct {
+	File   string  `json:"file"`
+	Status Status  `json:"status"`
+	Deltas []Delta `json:"deltas"`
+}
+
+// Result is the comparison of two reports.
+type Result struct {
+	Before string  `json:"before"`
+	After  string  `json:"after"`
+	Totals []Delta `json:"totals"`
+	// PerFile tells whether both reports have per-file metrics, without
+	// which Files is empty.
+	PerFile              bool         `json:"perFile"`
+	Files                []FileChange `json:"files"`
+	AddedDependencies    []string     `json:"addedDependencies"`
+	RemovedDependencies  []string     `json:"removedDependencies"`
+	AddedNativeModules   []string     `json:"addedNativeModules"`
+	RemovedNativeModules []string     `json:"removedNativeModules"`
+}
+
+// Changed tells whether anything differs between the reports.
+func (r Result) Changed() bool {
+	for _, delta := range r.Totals {
+		if delta.Change != 0 {
+			return true
+		}
+	}
+	return len(r.Files) > 0 || len(r.AddedDependencies) > 0 || len(r.RemovedDependencies) > 0 ||
+		len(r.AddedNativeModules) > 0 || len(r.RemovedNativeModules) > 0
+}
+
+// Compare returns the changes from one report to another.
+func Compare(before, after Snapshot) Result {
+	result := Result{
+		Before:  before.Path,
+		After:   after.Path,
+		Totals:  deltas(before.Totals, after.Totals),
+		PerFile: before.Files != nil && after.Files != nil,
+		Files:   []FileChange{},
+	}
+	result.AddedDependencies, result.RemovedDependencies = difference(before.Dependencies, after.Dependencies)
+	result.AddedNativeModules, result.RemovedNativeModules = difference(before.NativeModules, after.NativeModules)
+
+	if !result.PerFile {
+		return result
+	}
+
+	names := make(map[string]struct{}, len(before.Files)+len(after.Files))
+	for name := range before.Files {
+		names[name] = struct{}{}
+	}
+	for name := range after.Files {
+		names[name] = struct{}{}
+	}
+
+	for _, name := range sortedKeys(names) {
+		previous, existed := before.Files[name]
+		current, exists := after.Files[name]
+
+		change := FileChange{File: name, Status: Modified, Deltas: deltas(previous, current)}
+		switch {
+		case !existed:
+			change.Status = Added
+		case !exists:
+			change.Status = Removed
+		case previous == current:
+			continue
+		}
+		result.Files = append(result.Files, change)
+	}
+
+	return result
+}
+
+func deltas(before, after Metrics) []Delta {
+	deltas := make([]Delta, 0, len(ComparedMetrics))
+	for _, metric := range ComparedMetrics {
+		previous, current := metric.Value(before), metric.Value(after)
+		deltas = append(deltas, Delta{Metric: metric.Key, Before: previous, After: current, Change: current - previous})
+	}
+	return deltas
+}
+
+// difference returns the names only present after, and the ones only
+// present before.
+func difference(before, after []string) ([]string, []string) {
+	previous := make(map[string]struct{}, len(before))
+	for _, name := range before {
+		previous[name] = struct{}{}
+	}
+	current := make(map[string]struct{}, len(after))
+	for _, name := range after {
+		current[name] = struct{}{}
+	}
+
+	added := []string{}
+	for name := range current {
+		if _, ok := previous[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	removed := []string{}
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// number is a metric of a report, which the summary reports write either
+// as a JSON number or as a formatted string such as "12.5000" or "3.20%".
+type number float64
+
+func (n *number) UnmarshalJSON(content []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		*n = number(v)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "%"), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", v)
+		}
+		*n = number(parsed)
+	case nil:
+		*n = 0
+	default:
+		return fmt.Errorf("invalid number %s", content)
+	}
+	return nil
+}
+
+type dependencies struct {
+	Dependencies  []string `json:"dependencies"`
+	NativeModules []string `json:"native_modules"`
+}
+
+type function struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+// document holds the fields of both the summary and the detailed reports,
+// and the version of the versioned report.
+type document struct {
+	SchemaVersion string `json:"schema_version"`
+	// Directory is the analyzed file or directory of a summary report.
+	Directory string `json:"directory"`
+	// DirectoryPath is the analyzed directory of a detailed report.
+	DirectoryPath string `json:"directory_path"`
+	Summary       struct {
+		Lines               number        `json:"lines"`
+		Comments            number        `json:"comments"`
+		Functions           number        `json:"functions"`
+		Classes             number        `json:"classes"`
+		PublicMethods       number        `json:"public_methods"`
+		PrivateMethods      number        `json:"private_methods"`
+		AverageFunctionSize number        `json:"average_function_size"`
+		Dependencies        *dependencies `json:"dependencies"`
+
+		TotalLines          number `json:"total_lines"`
+		TotalComments       number `json:"total_comments"`
+		TotalFunctions      number `json:"total_functions"`
+		TotalClasses        number `json:"total_classes"`
+		TotalPublicMethods  number `json:"total_public_methods"`
+		TotalPrivateMethods number `json:"total_private_methods"`
+	} `json:"summary"`
+	Dependencies *dependencies `json:"dependencies"`
+	// Functions are the functions of a single file report.
+	Functions []function `json:"functions"`
+	Files     []struct {
+		Filename string `json:"filename"`
+		Metrics  struct {
+			Lines          number `json:"lines"`
+			Comments       number `json:"comments"`
+			Functions      number `json:"functions"`
+			Classes        number `json:"classes"`
+			PublicMethods  number `json:"public_methods"`
+			PrivateMethods number `json:"private_methods"`
+		} `json:"metrics"`
+		Functions []function `json:"functions"`
+	} `json:"files"`
+}
+
+// Load reads a JSON report written by analyze: the versioned report, or the
+// summary and detailed reports written by the versions before it.
+func Load(path string) (Snapshot, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Parse(content)
+}
+
+// Parse reads the content of a JSON report written by analyze.
+func Parse(content []byte) (Snapshot, error) {
+	var report document
+	if err := json.Unmarshal(content, &report); err != nil {
+		return Snapshot{}, fmt.Errorf("not an analyze JSON report: %w", err)
+	}
+
+	if report.SchemaVersion != "" {
+		versioned, err := results.Read(content)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		return versionedSnapshot(versioned), nil
+	}
+
+	if report.DirectoryPath != "" || report.Files != nil {
+		return detailedSnapshot(report), nil
+	}
+	if report.Directory == "" {
+		return Snapshot{}, fmt.Errorf("not an analyze JSON report: no analyzed file or directory")
+	}
+
+	summary := report.Summary
+	snapshot := Snapshot{
+		Path: report.Directory,
+		Totals: Metrics{
+			Lines:               int(summary.Lines),
+			Comments:            int(summary.Comments),
+			Functions:           int(summary.Functions),
+			Classes:             int(summary.Classes),
+			PublicMethods:       int(summary.PublicMethods),
+			PrivateMethods:      int(summary.PrivateMethods),
+			AverageFunctionSize: float64(summary.AverageFunctionSize),
+		},
+	}
+	if summary.Dependencies != nil {
+		snapshot.Dependencies = summary.Dependencies.Dependencies
+		snapshot.NativeModules = summary.Dependencies.NativeModules
+	}
+
+	// the report of a single file lists its functions, and its totals are
+	// the metrics of the file
+	if report.Functions != nil {
+		snapshot.Files = map[string]Metrics{report.Directory: snapshot.Totals}
+	}
+	return snapshot, nil
+}
+
+// versionedSnapshot reads the metrics of a versioned report, which always
+// has the metrics of each file.
+func versionedSnapshot(report results.Report) Snapshot {
+	snapshot := Snapshot{
+		Path: report.Root,
+		Totals: Metrics{
+			Lines:               report.Totals.Lines,
+			Comments:            report.Totals.CommentLines,
+			Functions:           report.Totals.Functions,
+			Classes:             report.Totals.Classes,
+			PublicMethods:       report.Totals.PublicMethods,
+			PrivateMethods:      report.Totals.PrivateMethods,
+			AverageFunctionSize: report.Totals.AverageFunctionSize,
+		},
+		Files:         make(map[string]Metrics, len(report.Files)),
+		Dependencies:  report.Totals.Dependencies,
+		NativeModules: report.Totals.NativeModules,
+	}
+
+	for _, file := range report.Files {
+		snapshot.Files[file.Path] = Metrics{
+			Lines:               file.Lines,
+			Comments:            file.CommentLines,
+			Functions:           file.FunctionCount,
+			Classes:             file.Classes,
+			PublicMethods:       file.PublicMethods,
+			PrivateMethods:      file.PrivateMethods,
+			AverageFunctionSize: file.AverageFunctionSize,
+		}
+	}
+	return snapshot
+}
+
+func detailedSnapshot(report document) Snapshot {
+	summary := report.Summary
+	snapshot := Snapshot{
+		Path: report.DirectoryPath,
+		Totals: Metrics{
+			Lines:          int(summary.TotalLines),
+			Comments:       int(summary.TotalComments),
+			Functions:      int(summary.TotalFunctions),
+			Classes:        int(summary.TotalClasses),
+			PublicMethods:  int(summary.TotalPublicMethods),
+			PrivateMethods: int(summary.TotalPrivateMethods),
+		},
+		Files: make(map[string]Metrics, len(report.Files)),
+	}
+	if report.Dependencies != nil {
+		snapshot.Dependencies = report.Dependencies.Dependencies
+		snapshot.NativeModules = report.Dependencies.NativeModules
+	}
+
+	// the average function size of a directory is the average of the files
+	// with functions, as analyze computes it
+	averages, withFunctions := 0.0, 0
+	for _, file := range report.Files {
+		metrics := Metrics{
+			Lines:               int(file.Metrics.Lines),
+			Comments:            int(file.Metrics.Comments),
+			Functions:           int(file.Metrics.Functions),
+			Classes:             int(file.Metrics.Classes),
+			PublicMethods:       int(file.Metrics.PublicMethods),
+			PrivateMethods:      int(file.Metrics.PrivateMethods),
+			AverageFunctionSize: averageSize(file.Functions),
+		}
+		snapshot.Files[file.Filename] = metrics
+		if metrics.AverageFunctionSize > 0 {
+			averages += metrics.AverageFunctionSize
+			withFunctions++
+		}
+	}
+	if withFunctions > 0 {
+		snapshot.Totals.AverageFunctionSize = averages / float64(withFunctions)
+	}
+
+	return snapshot
+}
+
+func averageSize(functions []function) float64 {
+	if len(functions) == 0 {
+		return 0
+	}
+	total := 0
+	for _, function := range functions {
+		total += function.EndLine - function.StartLine + 1
+	}
+	return float64(total) / float64(len(functions))
+}
@@ -0,0 +1,128 @@
+package comparison_test
+
+import (
+	"go-cli-tool/internal/comparison"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const summaryReport = `{
+  "directory": "src",
+  "summary": {
+    "lines": 120,
+    "comments": 10,
+    "comment_percentage": "8.33%",
+    "classes": 2,
+    "functions": 8,
+    "public_methods": 5,
+    "private_methods": 1,
+    "average_function_size": "6.5000",
+    "dependencies": {"total_dependencies": 2, "dependencies": ["lodash", "react"], "native_modules": ["fs"]}
+  }
+}`
+
+const detailedReport = `{
+  "directory_path": "src",
+  "summary": {"total_files": 2, "total_lines": 130, "total_comments": 10, "total_classes": 2, "total_functions": 9},
+  "dependencies": {"total_dependencies": 2, "dependencies": ["react", "moment"], "native_modules": ["fs"]},
+  "files": [
+    {"filename": "a.js", "metrics": {"lines": 100, "comments": 10, "classes": 2, "functions": 6},
+     "functions": [{"startLine": 1, "endLine": 4}, {"startLine": 6, "endLine": 7}]},
+    {"filename": "c.js", "metrics": {"lines": 30, "functions": 3},
+     "functions": [{"startLine": 1, "endLine": 10}]}
+  ]
+}`
+
+func TestParseSummaryReport(t *testing.T) {
+	snapshot, err := comparison.Parse([]byte(summaryReport))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "src", snapshot.Path)
+	assert.Equal(t, comparison.Metrics{Lines: 120, Comments: 10, Functions: 8, Classes: 2, PublicMethods: 5, PrivateMethods: 1, AverageFunctionSize: 6.5}, snapshot.Totals)
+	assert.Nil(t, snapshot.Files)
+	assert.Equal(t, []string{"lodash", "react"}, snapshot.Dependencies)
+}
+
+func TestParseDetailedReport(t *testing.T) {
+	snapshot, err := comparison.Parse([]byte(detailedReport))
+	assert.NoError(t, err)
+
+	assert.Len(t, snapshot.Files, 2)
+	assert.Equal(t, 3.0, snapshot.Files["a.js"].AverageFunctionSize)
+	// the average of the file averages, 3 and 10
+	assert.Equal(t, 6.5, snapshot.Totals.AverageFunctionSize)
+	assert.Equal(t, 130, snapshot.Totals.Lines)
+}
+
+const versionedReport = `{
+  "schema_version": "1",
+  "root": "/project/src",
+  "files": [
+    {"path": "a.js", "lines": 100, "comment_lines": 10, "classes": 2, "function_count": 6, "public_methods": 4, "average_function_size": 3},
+    {"path": "lib/a.js", "lines": 30, "function_count": 3, "average_function_size": 10}
+  ],
+  "totals": {"files": 2, "lines": 130, "comment_lines": 10, "classes": 2, "functions": 9, "public_methods": 4,
+    "average_function_size": 6.5, "dependencies": ["react"], "native_modules": ["fs"]}
+}`
+
+func TestParseVersionedReport(t *testing.T) {
+	snapshot, err := comparison.Parse([]byte(versionedReport))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/project/src", snapshot.Path)
+	assert.Equal(t, comparison.Metrics{Lines: 130, Comments: 10, Functions: 9, Classes: 2, PublicMethods: 4, AverageFunctionSize: 6.5}, snapshot.Totals)
+	assert.Len(t, snapshot.Files, 2)
+	assert.Equal(t, comparison.Metrics{Lines: 30, Functions: 3, AverageFunctionSize: 10}, snapshot.Files["lib/a.js"])
+	assert.Equal(t, []string{"react"}, snapshot.Dependencies)
+	assert.Equal(t, []string{"fs"}, snapshot.NativeModules)
+
+	_, err = comparison.Parse([]byte(`{"schema_version": "2", "root": "src", "files": []}`))
+	assert.EqualError(t, err, `unsupported report schema version "2", expected "1"`)
+}
+
+func TestParseRejectsOtherDocuments(t *testing.T) {
+	_, err := comparison.Parse([]byte(`{"report": "Count Lines"}`))
+	assert.Error(t, err)
+
+	_, err = comparison.Parse([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	before := comparison.Snapshot{
+		Totals:       comparison.Metrics{Lines: 100, Functions: 5},
+		Files:        map[string]comparison.Metrics{"a.js": {Lines: 60}, "b.js": {Lines: 40}, "same.js": {Lines: 1}},
+		Dependencies: []string{"lodash", "react"},
+	}
+	after := comparison.Snapshot{
+		Totals:       comparison.Metrics{Lines: 90, Functions: 6},
+		Files:        map[string]comparison.Metrics{"a.js": {Lines: 70}, "c.js": {Lines: 20}, "same.js": {Lines: 1}},
+		Dependencies: []string{"react", "moment"},
+	}
+
+	result := comparison.Compare(before, after)
+
+	assert.True(t, result.Changed())
+	assert.Equal(t, comparison.Delta{Metric: "lines", Before: 100, After: 90, Change: -10}, result.Totals[0])
+	assert.Equal(t, []string{"moment"}, result.AddedDependencies)
+	assert.Equal(t, []string{"lodash"}, result.RemovedDependencies)
+
+	assert.True(t, result.PerFile)
+	assert.Len(t, result.Files, 3)
+	assert.Equal(t, "a.js", result.Files[0].File)
+	assert.Equal(t, comparison.Modified, result.Files[0].Status)
+	assert.Equal(t, 10.0, result.Files[0].Deltas[0].Change)
+	assert.Equal(t, comparison.Removed, result.Files[1].Status)
+	assert.Equal(t, comparison.Added, result.Files[2].Status)
+}
+
+func TestCompareWithoutPerFileMetrics(t *testing.T) {
+	snapshot := comparison.Snapshot{Totals: comparison.Metrics{Lines: 10}}
+
+	result := comparison.Compare(snapshot, snapshot)
+
+	assert.False(t, result.PerFile)
+	assert.Empty(t, result.Files)
+	assert.False(t, result.Changed())
+}
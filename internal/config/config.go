@@ -0,0 +1,216 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-cli-tool/internal/git"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the names of the project configuration file, looked up in
+// this order in every directory from the working directory upward.
+var FileNames = []string{".gocli.yaml", ".gocli.yml", ".gocli.json"}
+
+// DefaultExclude lists the entries no analysis ever descends into. The
+// exclude globs of a configuration file are added to these.
+var DefaultExclude = []string{"node_modules", ".git", "dist", "build", "coverage", "vendor", ".DS_Store"}
+
+// Config holds the project-wide settings shared by all commands.
+type Config struct {
+	// Include restricts the analysis to the files matching one of these
+	// globs, relative to the analyzed directory. Empty includes every file.
+	Include []string `yaml:"include" json:"include"`
+	// Exclude skips the files and directories matching one of these globs.
+	// A glob without a slash matches an entry name at any depth.
+	Exclude []string `yaml:"exclude" json:"exclude"`
+	// Extensions limits the analyzed files to these extensions. Empty
+	// accepts every JavaScript and TypeScript extension.
+	Extensions []string `yaml:"extensions" json:"extensions"`
+	// TabWidth is the number of spaces a tab counts for in the
+	// indentation analysis.
+	TabWidth int `yaml:"tabWidth" json:"tabWidth"`
+	// BannedDependencies lists the modules that must not be imported. A
+	// module also bans its subpaths, so lodash bans lodash/fp.
+	BannedDependencies []string   `yaml:"bannedDependencies" json:"bannedDependencies"`
+	Thresholds         Thresholds `yaml:"thresholds" json:"thresholds"`
+	Output             Output     `yaml:"output" json:"output"`
+	// Files, when not nil, restricts the directory analyses to these files,
+	// given by absolute path. It is set from the command line by
+	// --changed-since and --staged, never by a configuration file.
+	Files []string `yaml:"-" json:"-"`
+	// Jobs is the number of files analyzed in parallel, one per CPU when
+	// zero or negative. It is set from the command line by --jobs.
+	Jobs int `yaml:"-" json:"-"`
+}
+
+// Thresholds are the limits above which a metric is reported as a problem.
+// A zero or negative limit is disabled, so a configuration file turns off
+// a default limit by setting it to -1.
+type Thresholds struct {
+	MaxComplexity          int     `yaml:"maxComplexity" json:"maxComplexity"`
+	MaxCognitiveComplexity int     `yaml:"maxCognitiveComplexity" json:"maxCognitiveComplexity"`
+	MaxNestingDepth        int     `yaml:"maxNestingDepth" json:"maxNestingDepth"`
+	MaxFunctionLines       int     `yaml:"maxFunctionLines" json:"maxFunctionLines"`
+	MinMaintainability     float64 `yaml:"minMaintainability" json:"minMaintainability"`
+	MinCommentPercentage   float64 `yaml:"minCommentPercentage" json:"minCommentPercentage"`
+	// MaxDependencies limits the external dependencies of each file.
+	MaxDependencies int `yaml:"maxDependencies" json:"maxDependencies"`
+	// AllowMixedIndentation accepts files indented with both tabs and
+	// spaces.
+	AllowMixedIndentation bool `yaml:"allowMixedIndentation" json:"allowMixedIndentation"`
+}
+
+// Output holds the defaults of the output flags, used when the flags are
+// not given on the command line.
+type Output struct {
+	Path     string `yaml:"path" json:"path"`
+	Detailed bool   `yaml:"detailed" json:"detailed"`
+}
+
+// Active is the configuration in effect for the running command.
+var Active = Default()
+
+// Default returns the configuration used when no configuration file is
+// found.
+func Default() Config {
+	return Config{
+		Exclude:  slices.Clone(DefaultExclude),
+		TabWidth: 4,
+		Thresholds: Thresholds{
+			MaxComplexity:          10,
+			MaxCognitiveComplexity: 15,
+			MaxNestingDepth:        4,
+			MaxFunctionLines:       50,
+		},
+	}
+}
+
+// Find looks for a configuration file in dir and its parents and returns
+// its path, or "" when there is none.
+func Find(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		for _, name := range FileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load reads the configuration file at path. Settings missing from the
+// file keep their default value.
+func Load(path string) (Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var file Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &file)
+	} else {
+		err = yaml.Unmarshal(content, &file)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	return merge(Default(), file), nil
+}
+
+// Discover loads the configuration file found from dir upward and returns
+// it with its path. Without a configuration file it returns the default
+// configuration and an empty path.
+func Discover(dir string) (Config, string, error) {
+	path := Find(dir)
+	if path == "" {
+		return Default(), "", nil
+	}
+
+	config, err := Load(path)
+	return config, path, err
+}
+
+// merge overrides the defaults with the settings present in a file.
+func merge(defaults, file Config) Config {
+	config := defaults
+	config.Include = file.Include
+	config.Exclude = append(config.Exclude, file.Exclude...)
+
+	config.Extensions = NormalizeExtensions(file.Extensions)
+
+	if file.TabWidth > 0 {
+		config.TabWidth = file.TabWidth
+	}
+
+	config.BannedDependencies = file.BannedDependencies
+
+	thresholds := file.Thresholds
+	if thresholds.MaxComplexity != 0 {
+		config.Thresholds.MaxComplexity = thresholds.MaxComplexity
+	}
+	if thresholds.MaxCognitiveComplexity != 0 {
+		config.Thresholds.MaxCognitiveComplexity = thresholds.MaxCognitiveComplexity
+	}
+	if thresholds.MaxNestingDepth != 0 {
+		config.Thresholds.MaxNestingDepth = thresholds.MaxNestingDepth
+	}
+	if thresholds.MaxFunctionLines != 0 {
+		config.Thresholds.MaxFunctionLines = thresholds.MaxFunctionLines
+	}
+	config.Thresholds.MinMaintainability = thresholds.MinMaintainability
+	config.Thresholds.MinCommentPercentage = thresholds.MinCommentPercentage
+	config.Thresholds.MaxDependencies = thresholds.MaxDependencies
+	config.Thresholds.AllowMixedIndentation = thresholds.AllowMixedIndentation
+
+	config.Output = file.Output
+	return config
+}
+
+// NormalizeExtensions returns the extensions lowercased and with a leading
+// dot, so that "TS" and ".ts" select the same files.
+func NormalizeExtensions(extensions []string) []string {
+	var normalized []string
+	for _, extension := range extensions {
+		extension = strings.ToLower(extension)
+		if !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
+		}
+		normalized = append(normalized, extension)
+	}
+	return normalized
+}
+
+// StateDirName is the directory where the data recorded between runs, such
+// as the analysis history, is kept.
+const StateDirName = ".gocli"
+
+// StateDir returns the state directory of the project containing dir: next
+// to its configuration file, else at the top of its git repository, else
+// in dir itself.
+func StateDir(dir string) string {
+	if path := Find(dir); path != "" {
+		return filepath.Join(filepath.Dir(path), StateDirName)
+	}
+	if top, err := git.TopLevel(dir); err == nil && top != "" {
+		return filepath.Join(top, StateDirName)
+	}
+	return filepath.Join(dir, StateDirName)
+}
@@ -0,0 +1,98 @@
+package config_test
+
+import (
+	"go-cli-tool/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverFromParentDirectory(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "src", "app")
+	assert.NoError(t, os.MkdirAll(nested, 0755))
+
+	content := `exclude:
+  - "src/legacy/**"
+extensions: [ts, .TSX]
+tabWidth: 2
+thresholds:
+  maxComplexity: 5
+  minMaintainability: 20
+output:
+  path: reports
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".gocli.yaml"), []byte(content), 0644))
+
+	settings, path, err := config.Discover(nested)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".gocli.yaml"), path)
+
+	assert.Contains(t, settings.Exclude, "node_modules", "Expected the default excludes to be kept")
+	assert.Contains(t, settings.Exclude, "src/legacy/**")
+	assert.Equal(t, []string{".ts", ".tsx"}, settings.Extensions)
+	assert.Equal(t, 2, settings.TabWidth)
+	assert.Equal(t, 5, settings.Thresholds.MaxComplexity)
+	assert.Equal(t, 15, settings.Thresholds.MaxCognitiveComplexity, "Expected unset thresholds to keep their default")
+	assert.Equal(t, 20.0, settings.Thresholds.MinMaintainability)
+	assert.Equal(t, "reports", settings.Output.Path)
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gocli.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"include": ["src/**"], "output": {"detailed": true}}`), 0644))
+
+	settings, err := config.Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"src/**"}, settings.Include)
+	assert.True(t, settings.Output.Detailed)
+	assert.Equal(t, 4, settings.TabWidth)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"include": `), 0644))
+	_, err = config.Load(path)
+	assert.Error(t, err)
+}
+
+func TestDiscoverWithoutConfigFile(t *testing.T) {
+	settings, path, err := config.Discover(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+	assert.Equal(t, config.Default(), settings)
+}
+
+func TestMatch(t *testing.T) {
+	assert.True(t, config.Match("node_modules", "node_modules"))
+	assert.True(t, config.Match("node_modules", "packages/web/node_modules"))
+	assert.True(t, config.Match("*.min.js", "public/vendor.min.js"))
+	assert.False(t, config.Match("*.min.js", "public/vendor.js"))
+
+	assert.True(t, config.Match("src/legacy/**", "src/legacy/old/a.js"))
+	assert.True(t, config.Match("src/legacy", "src/legacy/a.js"), "Expected a directory glob to match its content")
+	assert.False(t, config.Match("src/legacy/**", "lib/src/legacy/a.js"))
+	assert.True(t, config.Match("**/fixtures/*.js", "test/unit/fixtures/data.js"))
+	assert.True(t, config.Match("**/fixtures/*.js", "fixtures/data.js"))
+	assert.False(t, config.Match("src/*.js", "src/app/a.js"))
+}
+
+func TestIncludes(t *testing.T) {
+	settings := config.Default()
+	assert.True(t, settings.Includes("src/a.ts"))
+	assert.False(t, settings.Includes("README.md"))
+
+	settings.Include = []string{"src/**"}
+	settings.Extensions = []string{".js"}
+	assert.True(t, settings.Includes("src/app/a.js"))
+	assert.False(t, settings.Includes("src/app/a.ts"))
+	assert.False(t, settings.Includes("scripts/build.js"))
+}
+
+func TestStateDirNextToConfigurationFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "src")
+	assert.NoError(t, os.MkdirAll(nested, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".gocli.yaml"), []byte("tabWidth: 2\n"), 0644))
+
+	assert.Equal(t, filepath.Join(root, ".gocli"), config.StateDir(nested))
+}
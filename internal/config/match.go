@@ -0,0 +1,105 @@
+package config
+
+import (
+	"go-cli-tool/internal/policies"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Match reports whether the slash-separated relative path matches the
+// glob. `*` and `?` match within a path segment and `**` matches across
+// segments. A glob without a slash is matched against every segment of
+// the path, so "dist" or "*.min.js" apply at any depth.
+func Match(glob, relativePath string) bool {
+	glob = strings.TrimPrefix(filepath.ToSlash(glob), "./")
+	relativePath = filepath.ToSlash(relativePath)
+
+	if !strings.Contains(strings.TrimSuffix(glob, "/"), "/") {
+		glob = strings.TrimSuffix(glob, "/")
+		for _, segment := range strings.Split(relativePath, "/") {
+			if matched, _ := path.Match(glob, segment); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	glob = strings.TrimPrefix(glob, "/")
+	expression, err := regexp.Compile(globExpression(strings.TrimSuffix(glob, "/")))
+	if err != nil {
+		return false
+	}
+	return expression.MatchString(relativePath)
+}
+
+// globExpression translates a glob into an anchored regular expression
+// that also matches the paths inside a matching directory.
+func globExpression(glob string) string {
+	var expression strings.Builder
+	expression.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					expression.WriteString("(.*/)?")
+				} else {
+					expression.WriteString(".*")
+				}
+			} else {
+				expression.WriteString("[^/]*")
+			}
+		case '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	expression.WriteString("(/.*)?$")
+	return expression.String()
+}
+
+// Excludes reports whether the entry at the relative path is skipped by
+// the exclude globs.
+func (c Config) Excludes(relativePath string) bool {
+	for _, glob := range c.Exclude {
+		if Match(glob, relativePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Includes reports whether the file at the relative path is analyzed: it
+// must have a source extension and match an include glob, if any.
+func (c Config) Includes(relativePath string) bool {
+	if !c.HasSourceExtension(relativePath) {
+		return false
+	}
+	if len(c.Include) == 0 {
+		return true
+	}
+
+	for _, glob := range c.Include {
+		if Match(glob, relativePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSourceExtension reports whether the file name has one of the
+// configured extensions.
+func (c Config) HasSourceExtension(name string) bool {
+	if len(c.Extensions) == 0 {
+		return policies.IsJSFileExtension(name)
+	}
+	return slices.Contains(c.Extensions, strings.ToLower(filepath.Ext(name)))
+}
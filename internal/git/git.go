@@ -0,0 +1,203 @@
+// Package git runs the git command line against a local repository.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Run runs git with the given arguments in dir and returns its output.
+func Run(dir string, args ...string) (string, error) {
+	command := exec.Command("git", args...)
+	command.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), message)
+	}
+	return stdout.String(), nil
+}
+
+// TopLevel returns the root of the working tree containing dir.
+func TopLevel(dir string) (string, error) {
+	output, err := Run(dir, "rev-parse", "--show-toplevel")
+	return strings.TrimSpace(output), err
+}
+
+// Head returns the hash of the commit checked out in the repository
+// containing dir.
+func Head(dir string) (string, error) {
+	output, err := Run(dir, "rev-parse", "HEAD")
+	return strings.TrimSpace(output), err
+}
+
+// Branch returns the name of the branch checked out in the repository
+// containing dir, or "" on a detached HEAD.
+func Branch(dir string) (string, error) {
+	output, err := Run(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	branch := strings.TrimSpace(output)
+	if branch == "HEAD" {
+		branch = ""
+	}
+	return branch, err
+}
+
+// ChangedFiles returns the absolute paths of the files of the repository
+// containing dir that were added or modified since the common ancestor of
+// ref and HEAD, including the uncommitted and the untracked ones. Deleted
+// files are left out.
+func ChangedFiles(dir string, ref string) ([]string, error) {
+	base, err := Run(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := Run(dir, "diff", "--name-only", "--diff-filter=d", strings.TrimSpace(base))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := Run(dir, "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+
+	return absolutePaths(dir, changed+untracked)
+}
+
+// StagedFiles returns the absolute paths of the files added or modified in
+// the index of the repository containing dir.
+func StagedFiles(dir string) ([]string, error) {
+	staged, err := Run(dir, "diff", "--name-only", "--diff-filter=d", "--cached")
+	if err != nil {
+		return nil, err
+	}
+	return absolutePaths(dir, staged)
+}
+
+// absolutePaths resolves the paths listed by git, one per line and relative
+// to the top of the working tree.
+func absolutePaths(dir string, output string) ([]string, error) {
+	top, err := TopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		paths = append(paths, filepath.Join(top, filepath.FromSlash(line)))
+	}
+	return paths, nil
+}
+
+// FileChurn is how much a file changed over a period of the history.
+type FileChurn struct {
+	// Path is the absolute path of the file.
+	Path         string   `json:"path"`
+	Commits      int      `json:"commits"`
+	LinesAdded   int      `json:"linesAdded"`
+	LinesRemoved int      `json:"linesRemoved"`
+	Authors      []string `json:"authors"`
+}
+
+// Churn returns the churn of the files under dir changed by the commits
+// since the given date, in any format git log --since accepts such as
+// "90 days ago" or "2024-01-31", keyed by absolute path. An empty since
+// covers the whole history. Merge commits are left out, and renamed files
+// count as new ones.
+func Churn(dir string, since string) (map[string]*FileChurn, error) {
+	args := []string{"log", "--no-merges", "--no-renames", "--numstat", "--format=%x00%aN"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	output, err := Run(dir, append(args, "--", ".")...)
+	if err != nil {
+		return nil, err
+	}
+	top, err := TopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	churn := make(map[string]*FileChurn)
+	author := ""
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\x00") {
+			author = line[1:]
+			continue
+		}
+
+		// --numstat lines are "added<TAB>removed<TAB>path", with "-" as
+		// counts for binary files
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		path := filepath.Join(top, filepath.FromSlash(fields[2]))
+		file, ok := churn[path]
+		if !ok {
+			file = &FileChurn{Path: path}
+			churn[path] = file
+		}
+		file.Commits++
+		file.LinesAdded += count(fields[0])
+		file.LinesRemoved += count(fields[1])
+		if !slices.Contains(file.Authors, author) {
+			file.Authors = append(file.Authors, author)
+		}
+	}
+	return churn, nil
+}
+
+// count parses a line count of --numstat, which is "-" for binary files.
+func count(field string) int {
+	n, err := strconv.Atoi(field)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// NotCommitted is the author git blame gives to the lines not committed
+// yet.
+const NotCommitted = "Not Committed Yet"
+
+// Blame returns the author of the last change of each line of the file,
+// lines not committed yet included.
+func Blame(filePath string) ([]string, error) {
+	output, err := Run(filepath.Dir(filePath), "blame", "--line-porcelain", "--", filepath.Base(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	// --line-porcelain repeats the commit information before each line,
+	// which starts with a tab
+	authors := []string{}
+	author := ""
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "author "):
+			author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "\t"):
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
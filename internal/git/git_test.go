@@ -0,0 +1,67 @@
+package git_test
+
+import (
+	"go-cli-tool/internal/git"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func commit(t *testing.T, dir string, author string, file string, content string) {
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+	_, err := git.Run(dir, "add", file)
+	assert.NoError(t, err)
+	_, err = git.Run(dir, "-c", "user.name="+author, "-c", "user.email="+author+"@example.com", "commit", "-q", "-m", "change "+file)
+	assert.NoError(t, err)
+}
+
+func TestChurn(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NoError(t, err)
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	commit(t, dir, "Ana", "a.js", "one\ntwo\n")
+	commit(t, dir, "Bruno", "a.js", "one\nthree\n")
+	commit(t, dir, "Ana", "b.js", "one\n")
+
+	churn, err := git.Churn(dir, "")
+	assert.NoError(t, err)
+	assert.Len(t, churn, 2)
+
+	a := churn[filepath.Join(dir, "a.js")]
+	assert.Equal(t, 2, a.Commits)
+	assert.Equal(t, 3, a.LinesAdded)
+	assert.Equal(t, 1, a.LinesRemoved)
+	assert.ElementsMatch(t, []string{"Ana", "Bruno"}, a.Authors)
+
+	b := churn[filepath.Join(dir, "b.js")]
+	assert.Equal(t, 1, b.Commits)
+	assert.Equal(t, []string{"Ana"}, b.Authors)
+
+	churn, err = git.Churn(dir, "1 day ago")
+	assert.NoError(t, err)
+	assert.Len(t, churn, 2)
+}
+
+func TestBlame(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NoError(t, err)
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	commit(t, dir, "Ana", "a.js", "one\ntwo\n")
+	commit(t, dir, "Bruno", "a.js", "one\nthree\n")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.js"), []byte("one\nthree\nfour\n"), 0644))
+
+	authors, err := git.Blame(filepath.Join(dir, "a.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Ana", "Bruno", git.NotCommitted}, authors)
+
+	_, err = git.Blame(filepath.Join(dir, "untracked.js"))
+	assert.Error(t, err)
+}
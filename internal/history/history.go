@@ -0,0 +1,240 @@
+// Package history records the summary of every analysis, so that the
+// evolution of the metrics can be followed from run to run.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the history file in the state directory. It
+// holds one JSON encoded run per line.
+const FileName = "history.jsonl"
+
+// Metrics are the totals of an analysis.
+type Metrics struct {
+	Files               int     `json:"files"`
+	Lines               int     `json:"lines"`
+	Comments            int     `json:"comments"`
+	CommentPercentage   float64 `json:"commentPercentage"`
+	Classes             int     `json:"classes"`
+	Functions           int     `json:"functions"`
+	AverageFunctionSize float64 `json:"averageFunctionSize"`
+	AverageComplexity   float64 `json:"averageComplexity"`
+	MaxComplexity       int     `json:"maxComplexity"`
+	Dependencies        int     `json:"dependencies"`
+	Maintainability     float64 `json:"maintainability"`
+}
+
+// Run is an analysis recorded in the history.
+type Run struct {
+	// ID numbers the runs from 1, in the order they were recorded.
+	ID        int       `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	// Commit and Branch are the git commit and branch checked out when the
+	// analysis ran, empty outside of a repository.
+	Commit  string  `json:"commit,omitempty"`
+	Branch  string  `json:"branch,omitempty"`
+	Path    string  `json:"path"`
+	Metrics Metrics `json:"metrics"`
+	// ChangedSince and Staged record that the analysis was restricted to
+	// the files changed since a git ref, or to the staged files.
+	ChangedSince string `json:"changedSince,omitempty"`
+	Staged       bool   `json:"staged,omitempty"`
+}
+
+// Partial reports whether the analysis was restricted to the changed
+// files, so that its metrics cannot be compared to the complete runs.
+func (r Run) Partial() bool {
+	return r.ChangedSince != "" || r.Staged
+}
+
+// Scope describes the files the run analyzed: empty for a complete run.
+func (r Run) Scope() string {
+	if r.Staged {
+		return "staged"
+	}
+	if r.ChangedSince != "" {
+		return "since " + r.ChangedSince
+	}
+	return ""
+}
+
+// Complete returns the runs that analyzed all the files.
+func Complete(runs []Run) []Run {
+	var complete []Run
+	for _, run := range runs {
+		if !run.Partial() {
+			complete = append(complete, run)
+		}
+	}
+	return complete
+}
+
+// ShortCommit returns the abbreviated hash of the commit of the run.
+func (r Run) ShortCommit() string {
+	if len(r.Commit) > 7 {
+		return r.Commit[:7]
+	}
+	return r.Commit
+}
+
+// Metric is a metric followed in the history.
+type Metric struct {
+	Key   string
+	Title string
+	Value func(Metrics) float64
+}
+
+// TrackedMetrics lists the metrics compared between runs, in display order.
+var TrackedMetrics = []Metric{
+	{"files", "Files", func(m Metrics) float64 { return float64(m.Files) }},
+	{"lines", "Lines", func(m Metrics) float64 { return float64(m.Lines) }},
+	{"comments", "Comments", func(m Metrics) float64 { return float64(m.Comments) }},
+	{"comment_percentage", "Comment percentage", func(m Metrics) float64 { return m.CommentPercentage }},
+	{"classes", "Classes", func(m Metrics) float64 { return float64(m.Classes) }},
+	{"functions", "Functions", func(m Metrics) float64 { return float64(m.Functions) }},
+	{"average_function_size", "Average function size", func(m Metrics) float64 { return m.AverageFunctionSize }},
+	{"average_complexity", "Average complexity", func(m Metrics) float64 { return m.AverageComplexity }},
+	{"max_complexity", "Max complexity", func(m Metrics) float64 { return float64(m.MaxComplexity) }},
+	{"dependencies", "Dependencies", func(m Metrics) float64 { return float64(m.Dependencies) }},
+	{"maintainability", "Maintainability index", func(m Metrics) float64 { return m.Maintainability }},
+}
+
+// Delta is the change of a metric between two runs.
+type Delta struct {
+	Metric string  `json:"metric"`
+	From   float64 `json:"from"`
+	To     float64 `json:"to"`
+	Change float64 `json:"change"`
+}
+
+// Compare returns the change of every metric from one run to another.
+func Compare(from, to Run) []Delta {
+	deltas := make([]Delta, 0, len(TrackedMetrics))
+	for _, metric := range TrackedMetrics {
+		before, after := metric.Value(from.Metrics), metric.Value(to.Metrics)
+		deltas = append(deltas, Delta{Metric: metric.Key, From: before, To: after, Change: after - before})
+	}
+	return deltas
+}
+
+// Store is a history file.
+type Store struct {
+	Path string
+}
+
+// NewStore returns the history kept in a state directory.
+func NewStore(stateDir string) *Store {
+	return &Store{Path: filepath.Join(stateDir, FileName)}
+}
+
+// Runs returns the recorded runs, oldest first. A missing history file
+// holds no runs.
+func (s *Store) Runs() ([]Run, error) {
+	file, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("invalid history entry at %s:%d: %w", s.Path, line, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, scanner.Err()
+}
+
+// Append records a run, numbering it after the last recorded one, and
+// returns it with its ID. The history is locked while the run is numbered
+// and written, so that concurrent analyses get distinct IDs.
+func (s *Store) Append(run Run) (Run, error) {
+	if err := os.MkdirAll(filepath.Dir(s.Path), os.ModePerm); err != nil {
+		return run, err
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return run, err
+	}
+	defer unlock()
+
+	runs, err := s.Runs()
+	if err != nil {
+		return run, err
+	}
+	run.ID = 1
+	if len(runs) > 0 {
+		run.ID = runs[len(runs)-1].ID + 1
+	}
+
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return run, err
+	}
+
+	content, err := json.Marshal(run)
+	if err == nil {
+		_, err = file.Write(append(content, '\n'))
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return run, err
+}
+
+// lockTimeout is how long Append waits for the lock of the history, and
+// the age after which a lock left by an interrupted run is taken over.
+const lockTimeout = 5 * time.Second
+
+// lock creates the lock file of the history, waiting while another run
+// holds it, and returns the function removing it.
+func (s *Store) lock() (func(), error) {
+	path := s.Path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockTimeout {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the history %s is locked by another run", s.Path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Find returns the run with the given ID.
+func Find(runs []Run, id int) (Run, error) {
+	for _, run := range runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return Run{}, fmt.Errorf("no run with id %d in the history", id)
+}
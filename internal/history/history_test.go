@@ -0,0 +1,118 @@
+package history_test
+
+import (
+	"go-cli-tool/internal/history"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreAppendAndRuns(t *testing.T) {
+	store := history.NewStore(filepath.Join(t.TempDir(), ".gocli"))
+
+	runs, err := store.Runs()
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+
+	first, err := store.Append(history.Run{Timestamp: time.Now(), Path: "/src", Metrics: history.Metrics{Lines: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.ID)
+
+	second, err := store.Append(history.Run{Timestamp: time.Now(), Commit: "0123456789abcdef", Path: "/src", Metrics: history.Metrics{Lines: 12}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second.ID)
+	assert.Equal(t, "0123456", second.ShortCommit())
+
+	runs, err = store.Runs()
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, 12, runs[1].Metrics.Lines)
+
+	found, err := history.Find(runs, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, found.Metrics.Lines)
+
+	_, err = history.Find(runs, 3)
+	assert.Error(t, err)
+}
+
+func TestStoreAppendConcurrently(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), ".gocli")
+
+	start := make(chan struct{})
+	var wait sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			<-start
+			_, err := history.NewStore(directory).Append(history.Run{Timestamp: time.Now(), Path: "/src"})
+			assert.NoError(t, err)
+		}()
+	}
+	close(start)
+	wait.Wait()
+
+	runs, err := history.NewStore(directory).Runs()
+	assert.NoError(t, err)
+	assert.Len(t, runs, 50)
+	for i, run := range runs {
+		assert.Equal(t, i+1, run.ID)
+	}
+	assert.NoFileExists(t, history.NewStore(directory).Path+".lock")
+}
+
+func TestStoreRecordsPartialRuns(t *testing.T) {
+	store := history.NewStore(filepath.Join(t.TempDir(), ".gocli"))
+
+	_, err := store.Append(history.Run{Path: "/src", Metrics: history.Metrics{Lines: 1000}})
+	assert.NoError(t, err)
+	_, err = store.Append(history.Run{Path: "/src", ChangedSince: "origin/main", Metrics: history.Metrics{Lines: 40}})
+	assert.NoError(t, err)
+	_, err = store.Append(history.Run{Path: "/src", Staged: true, Metrics: history.Metrics{Lines: 5}})
+	assert.NoError(t, err)
+	_, err = store.Append(history.Run{Path: "/src", Metrics: history.Metrics{Lines: 1010}})
+	assert.NoError(t, err)
+
+	runs, err := store.Runs()
+	assert.NoError(t, err)
+	assert.Len(t, runs, 4)
+	assert.Equal(t, "", runs[0].Scope())
+	assert.Equal(t, "since origin/main", runs[1].Scope())
+	assert.Equal(t, "staged", runs[2].Scope())
+
+	complete := history.Complete(runs)
+	assert.Len(t, complete, 2)
+	assert.Equal(t, []int{1, 4}, []int{complete[0].ID, complete[1].ID})
+	assert.Equal(t, 10.0, history.Compare(complete[0], complete[1])[1].Change)
+}
+
+func TestStoreRejectsInvalidEntries(t *testing.T) {
+	directory := t.TempDir()
+	store := history.NewStore(directory)
+	assert.NoError(t, os.WriteFile(store.Path, []byte("{\"id\": 1}\nnot json\n"), 0644))
+
+	_, err := store.Runs()
+	assert.ErrorContains(t, err, "history.jsonl:2")
+}
+
+func TestCompare(t *testing.T) {
+	from := history.Run{Metrics: history.Metrics{Lines: 100, Functions: 10, AverageComplexity: 2.5}}
+	to := history.Run{Metrics: history.Metrics{Lines: 90, Functions: 12, AverageComplexity: 3}}
+
+	deltas := history.Compare(from, to)
+	assert.Len(t, deltas, len(history.TrackedMetrics))
+
+	changes := make(map[string]float64)
+	for _, delta := range deltas {
+		changes[delta.Metric] = delta.Change
+	}
+	assert.Equal(t, -10.0, changes["lines"])
+	assert.Equal(t, 2.0, changes["functions"])
+	assert.Equal(t, 0.5, changes["average_complexity"])
+	assert.Equal(t, 0.0, changes["dependencies"])
+}
@@ -0,0 +1,263 @@
+// Package ignore implements the .gitignore pattern semantics used to skip
+// files during directory analysis.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileNames are the ignore files honored in every directory: the Git and
+// ESLint ones, whose patterns projects already maintain, and the one
+// specific to this tool.
+var FileNames = []string{".gitignore", ".eslintignore", ".gocliignore"}
+
+// rule is a single pattern of an ignore file.
+type rule struct {
+	// base is the slash-separated directory of the ignore file, relative
+	// to the top of the matcher, or "" for the top itself
+	base       string
+	expression *regexp.Regexp
+	negate     bool
+	dirOnly    bool
+}
+
+// Matcher decides which paths below a directory are ignored. Patterns are
+// read from the ignore files of the enclosing Git repository, from its top
+// down to the analyzed directory, and from the directories loaded during
+// the walk. As in Git, the last matching pattern decides.
+type Matcher struct {
+	top   string
+	rules []rule
+}
+
+// NewMatcher returns a matcher for the walk of root, with the patterns of
+// the ignore files found between the top of the enclosing Git repository
+// and root, root excluded.
+func NewMatcher(root string) *Matcher {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return &Matcher{top: root}
+	}
+
+	top := repositoryTop(root)
+	matcher := &Matcher{top: top}
+
+	relative, err := filepath.Rel(top, root)
+	if err != nil || relative == "." {
+		return matcher
+	}
+
+	directory := top
+	matcher.LoadDir(directory)
+	for _, segment := range strings.Split(relative, string(filepath.Separator)) {
+		directory = filepath.Join(directory, segment)
+		if directory != root {
+			matcher.LoadDir(directory)
+		}
+	}
+
+	return matcher
+}
+
+// repositoryTop returns the closest directory at or above dir holding a
+// .git entry, or dir itself outside of a repository.
+func repositoryTop(dir string) string {
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// LoadDir adds the patterns of the ignore files found in the directory.
+// Directories must be loaded before the paths inside them are matched.
+func (m *Matcher) LoadDir(dir string) {
+	base := m.relative(dir)
+	if base == "." {
+		base = ""
+	}
+
+	for _, name := range FileNames {
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if rule, ok := parseRule(scanner.Text(), base); ok {
+				m.rules = append(m.rules, rule)
+			}
+		}
+		file.Close()
+	}
+}
+
+// Ignored reports whether the file or directory at path is ignored.
+func (m *Matcher) Ignored(path string, isDir bool) bool {
+	relative := m.relative(path)
+	if relative == "." || strings.HasPrefix(relative, "../") {
+		return false
+	}
+
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		candidate := relative
+		if rule.base != "" {
+			if !strings.HasPrefix(relative, rule.base+"/") {
+				continue
+			}
+			candidate = relative[len(rule.base)+1:]
+		}
+
+		if rule.expression.MatchString(candidate) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// Pattern is a single pattern in the .gitignore syntax, for the other
+// files that share it, such as CODEOWNERS.
+type Pattern struct {
+	rule rule
+}
+
+// ParsePattern parses a pattern in the .gitignore syntax. It returns false
+// for blank lines and comments.
+func ParsePattern(line string) (Pattern, bool) {
+	parsed, ok := parseRule(line, "")
+	return Pattern{rule: parsed}, ok
+}
+
+// Matches reports whether the file or directory at the slash-separated
+// path, relative to the directory of the pattern, matches it. Negation is
+// not taken into account.
+func (p Pattern) Matches(path string, isDir bool) bool {
+	if p.rule.dirOnly && !isDir {
+		return false
+	}
+	return p.rule.expression.MatchString(path)
+}
+
+func (m *Matcher) relative(path string) string {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+
+	relative, err := filepath.Rel(m.top, absolute)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relative)
+}
+
+// parseRule parses a line of an ignore file found in the base directory.
+func parseRule(line, base string) (rule, bool) {
+	line = trimTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	parsed := rule{base: base}
+	if strings.HasPrefix(line, "!") {
+		parsed.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		parsed.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	// a slash at the start or in the middle anchors the pattern to the
+	// directory of the ignore file; otherwise it matches at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expression := "^"
+	if !anchored {
+		expression += "(?:.*/)?"
+	}
+	expression += translate(line) + "$"
+
+	compiled, err := regexp.Compile(expression)
+	if err != nil {
+		return rule{}, false
+	}
+	parsed.expression = compiled
+	return parsed, true
+}
+
+// translate converts a gitignore glob into a regular expression.
+func translate(pattern string) string {
+	var expression strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/") && (i == 0 || pattern[i-1] == '/'):
+			expression.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && pattern[i:] == "**" && i > 0 && pattern[i-1] == '/':
+			expression.WriteString(".*")
+			i++
+		case c == '*':
+			expression.WriteString("[^/]*")
+		case c == '?':
+			expression.WriteString("[^/]")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expression.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				expression.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expression.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return expression.String()
+}
+
+// trimTrailingSpaces removes the trailing spaces of a pattern unless they
+// are escaped with a backslash.
+func trimTrailingSpaces(line string) string {
+	line = strings.TrimRight(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-2] + " "
+	}
+	return line
+}
@@ -0,0 +1,83 @@
+package ignore_test
+
+import (
+	"go-cli-tool/internal/ignore"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestIgnoredPatterns(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	writeFile(t, filepath.Join(root, ".gitignore"), `# generated files
+*.min.js
+!keep.min.js
+/out
+generated/
+docs/**/*.js
+**/fixtures/big.js
+\#literal.js
+trailing.js   
+`)
+
+	matcher := ignore.NewMatcher(root)
+	matcher.LoadDir(root)
+	path := func(relative string) string { return filepath.Join(root, relative) }
+
+	assert.True(t, matcher.Ignored(path("app.min.js"), false))
+	assert.True(t, matcher.Ignored(path("src/vendor/lib.min.js"), false), "Expected an unanchored pattern to match at any depth")
+	assert.False(t, matcher.Ignored(path("src/keep.min.js"), false), "Expected negation to re-include the file")
+
+	assert.True(t, matcher.Ignored(path("out"), true))
+	assert.False(t, matcher.Ignored(path("src/out"), true), "Expected a leading slash to anchor the pattern")
+
+	assert.True(t, matcher.Ignored(path("src/generated"), true))
+	assert.False(t, matcher.Ignored(path("src/generated"), false), "Expected a trailing slash to match directories only")
+
+	assert.True(t, matcher.Ignored(path("docs/a/b/c.js"), false))
+	assert.True(t, matcher.Ignored(path("docs/c.js"), false))
+	assert.False(t, matcher.Ignored(path("src/docs/c.js"), false))
+
+	assert.True(t, matcher.Ignored(path("test/unit/fixtures/big.js"), false))
+	assert.True(t, matcher.Ignored(path("fixtures/big.js"), false))
+
+	assert.True(t, matcher.Ignored(path("#literal.js"), false))
+	assert.True(t, matcher.Ignored(path("trailing.js"), false))
+	assert.False(t, matcher.Ignored(path("src/app.js"), false))
+}
+
+func TestNestedIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	writeFile(t, filepath.Join(root, ".gitignore"), "*.bundle.js\n")
+	writeFile(t, filepath.Join(root, "packages/web/.gitignore"), "/build.js\n!main.bundle.js\n")
+	writeFile(t, filepath.Join(root, "packages/web/.eslintignore"), "legacy/\n")
+	writeFile(t, filepath.Join(root, "packages/api/.gocliignore"), "seed.js\n")
+
+	// the walk starts below the repository top, whose patterns still apply
+	web := filepath.Join(root, "packages", "web")
+	matcher := ignore.NewMatcher(web)
+	matcher.LoadDir(web)
+
+	assert.True(t, matcher.Ignored(filepath.Join(web, "vendor.bundle.js"), false))
+	assert.False(t, matcher.Ignored(filepath.Join(web, "main.bundle.js"), false))
+	assert.True(t, matcher.Ignored(filepath.Join(web, "build.js"), false))
+	assert.False(t, matcher.Ignored(filepath.Join(web, "src/build.js"), false))
+	assert.True(t, matcher.Ignored(filepath.Join(web, "src/legacy"), true))
+
+	// the patterns of a directory apply only inside it
+	matcher = ignore.NewMatcher(root)
+	matcher.LoadDir(root)
+	matcher.LoadDir(filepath.Join(root, "packages", "api"))
+	assert.True(t, matcher.Ignored(filepath.Join(root, "packages/api/db/seed.js"), false))
+	assert.False(t, matcher.Ignored(filepath.Join(root, "packages/web/seed.js"), false))
+}
@@ -0,0 +1,652 @@
+package parser
+
+import (
+	"go-cli-tool/internal/tokenizer"
+	"strings"
+)
+
+// Dialect selects the syntax extensions understood by the parser.
+type Dialect int
+
+const (
+	JavaScript Dialect = iota
+	TypeScript
+)
+
+// FunctionKind tells how a function was written in the source code.
+type FunctionKind string
+
+const (
+	Declaration FunctionKind = "declaration"
+	Expression  FunctionKind = "expression"
+	Arrow       FunctionKind = "arrow"
+	Method      FunctionKind = "method"
+	Getter      FunctionKind = "getter"
+	Setter      FunctionKind = "setter"
+)
+
+// Function holds the facts collected for a single function.
+type Function struct {
+	Name      string       `json:"name"`
+	Kind      FunctionKind `json:"kind"`
+	Async     bool         `json:"async"`
+	Generator bool         `json:"generator"`
+	Static    bool         `json:"static"`
+	StartLine int          `json:"startLine"`
+	EndLine   int          `json:"endLine"`
+	Params    int          `json:"params"`
+	Class     string       `json:"class,omitempty"`
+	// Access is the TypeScript access modifier of a class member, if any.
+	Access string `json:"access,omitempty"`
+
+	// Parent is the index in File.Functions of the enclosing function, or -1.
+	Parent int `json:"-"`
+	// BodyStart and BodyEnd delimit the body in File.Tokens, both inclusive.
+	// For block bodies they point at the braces.
+	BodyStart int `json:"-"`
+	BodyEnd   int `json:"-"`
+}
+
+// Lines returns the number of lines spanned by the function.
+func (f Function) Lines() int {
+	return f.EndLine - f.StartLine + 1
+}
+
+// Class holds the facts collected for a single class.
+type Class struct {
+	Name      string `json:"name"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+}
+
+// TypeKind tells which TypeScript type-only construct was declared.
+type TypeKind string
+
+const (
+	Interface TypeKind = "interface"
+	Enum      TypeKind = "enum"
+	TypeAlias TypeKind = "type"
+)
+
+// TypeDeclaration holds the facts collected for a TypeScript interface,
+// enum or type alias.
+type TypeDeclaration struct {
+	Name      string   `json:"name"`
+	Kind      TypeKind `json:"kind"`
+	StartLine int      `json:"startLine"`
+	EndLine   int      `json:"endLine"`
+}
+
+// File is the syntax summary of a source file.
+type File struct {
+	// Tokens are the code tokens of the file, comments excluded.
+	Tokens    []tokenizer.Token
+	Functions []Function
+	Classes   []Class
+	Types     []TypeDeclaration
+
+	match []int
+}
+
+// MatchingBracket returns the index in Tokens of the bracket paired with
+// the (, [, {, ), ] or } token at index i, or -1 if it is unbalanced or not
+// a bracket.
+func (f *File) MatchingBracket(i int) int {
+	if i < 0 || i >= len(f.match) {
+		return -1
+	}
+	return f.match[i]
+}
+
+// CountTypes returns how many type declarations of the given kind the
+// file contains.
+func (f *File) CountTypes(kind TypeKind) int {
+	count := 0
+	for _, declaration := range f.Types {
+		if declaration.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// statementKeywords start a new statement, which ends a concise arrow
+// function body written without a trailing semicolon.
+var statementKeywords = map[string]bool{
+	"const": true, "let": true, "var": true, "function": true, "class": true,
+	"if": true, "for": true, "while": true, "do": true, "return": true,
+	"export": true, "import": true, "switch": true, "try": true,
+	"throw": true, "break": true, "continue": true,
+}
+
+// controlKeywords can be followed by `( ... ) {` without being a method.
+var controlKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"with": true, "function": true, "return": true, "typeof": true,
+	"new": true, "delete": true, "void": true, "in": true, "instanceof": true,
+}
+
+// keywordsBeforeObject are the keywords after which `{` opens an object
+// literal rather than a block.
+var keywordsBeforeObject = map[string]bool{
+	"return": true, "typeof": true, "case": true, "in": true, "delete": true,
+	"void": true, "throw": true, "new": true, "default": true,
+	"instanceof": true, "yield": true, "await": true, "of": true,
+}
+
+type scope struct {
+	class   string // name of the enclosing class
+	parent  int    // index of the enclosing function, -1 at the top level
+	members bool   // inside an object literal or class body
+	inClass bool   // directly inside a class body
+}
+
+type parser struct {
+	tokens     []tokenizer.Token
+	match      []int
+	file       *File
+	typescript bool
+	// typeOwner maps the `=` that follows a type annotation to the index
+	// of the annotated name, as in `const name: Type = value`.
+	typeOwner map[int]int
+}
+
+// Parse builds the syntax summary of a tokenized source file. Parsing is
+// tolerant: constructs it does not understand are skipped, so a syntax
+// error only affects the statement it appears in.
+func Parse(tokens []tokenizer.Token, dialect Dialect) *File {
+	code := make([]tokenizer.Token, 0, len(tokens))
+	for _, token := range tokens {
+		if !token.IsComment() {
+			code = append(code, token)
+		}
+	}
+
+	match := matchBrackets(code)
+	p := &parser{
+		tokens:     code,
+		match:      match,
+		file:       &File{Tokens: code, match: match},
+		typescript: dialect == TypeScript,
+		typeOwner:  make(map[int]int),
+	}
+	p.scan(0, len(code), scope{parent: -1})
+
+	return p.file
+}
+
+// ParseSource tokenizes and parses source code. JSX is recognized in
+// JavaScript only, since `<Type>value` is a type assertion in TypeScript.
+func ParseSource(source string, dialect Dialect) *File {
+	return Parse(tokenizer.TokenizeWith(source, tokenizer.Options{JSX: dialect == JavaScript}), dialect)
+}
+
+// matchBrackets pairs every opening (, [ and { token with its closing
+// token. The returned slice holds the index of the partner token, or -1.
+func matchBrackets(tokens []tokenizer.Token) []int {
+	match := make([]int, len(tokens))
+	var stack []int
+
+	for i, token := range tokens {
+		match[i] = -1
+		if token.Kind != tokenizer.Punctuator {
+			continue
+		}
+		switch token.Value {
+		case "(", "[", "{":
+			stack = append(stack, i)
+		case ")", "]", "}":
+			if len(stack) == 0 {
+				continue
+			}
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			match[open] = i
+			match[i] = open
+		}
+	}
+
+	return match
+}
+
+func (p *parser) scan(from, to int, s scope) {
+	for i := from; i < to; {
+		i = p.step(i, to, s)
+	}
+}
+
+// step handles the token at index i and returns the index of the next
+// token to look at.
+func (p *parser) step(i, to int, s scope) int {
+	token := p.tokens[i]
+
+	if s.members {
+		if next, ok := p.method(i, s); ok {
+			return next
+		}
+	}
+
+	if p.typescript {
+		if next, ok := p.typeScriptConstruct(i, to, s); ok {
+			return next
+		}
+	}
+
+	switch {
+	case token.Is(tokenizer.Keyword, "class") && !p.isMemberAccess(i):
+		if next, ok := p.class(i, s); ok {
+			return next
+		}
+	case token.Is(tokenizer.Keyword, "function") && !p.isMemberAccess(i):
+		if next, ok := p.functionKeyword(i, s); ok {
+			return next
+		}
+	case token.IsPunctuator("("), token.Kind == tokenizer.Identifier:
+		if next, ok := p.arrow(i, to, s); ok {
+			return next
+		}
+	}
+
+	if p.isOpening(i) && p.match[i] > i && p.match[i] < to {
+		inner := scope{class: s.class, parent: s.parent}
+		inner.members = token.IsPunctuator("{") && p.opensObject(i)
+		p.scan(i+1, p.match[i], inner)
+		return p.match[i] + 1
+	}
+
+	return i + 1
+}
+
+func (p *parser) class(i int, s scope) (int, bool) {
+	k := i + 1
+	name := ""
+	if k < len(p.tokens) && p.tokens[k].Kind == tokenizer.Identifier {
+		name = p.tokens[k].Value
+		k++
+	} else if k < len(p.tokens) && (p.tokens[k].IsPunctuator("{") || p.tokens[k].Is(tokenizer.Keyword, "extends")) {
+		name = p.assignedName(i)
+	} else {
+		return 0, false
+	}
+
+	// skip the heritage clause up to the class body
+	for k < len(p.tokens) && !p.tokens[k].IsPunctuator("{") {
+		if p.isOpening(k) && p.match[k] > k {
+			k = p.match[k]
+		}
+		k++
+	}
+	if k >= len(p.tokens) || p.match[k] < 0 {
+		return 0, false
+	}
+	end := p.match[k]
+
+	p.file.Classes = append(p.file.Classes, Class{
+		Name:      name,
+		StartLine: p.tokens[i].Line,
+		EndLine:   p.tokens[end].EndLine,
+	})
+
+	p.scan(k+1, end, scope{class: name, parent: s.parent, members: true, inClass: true})
+	return end + 1, true
+}
+
+func (p *parser) functionKeyword(i int, s scope) (int, bool) {
+	start := i
+	function := Function{Kind: Expression, Class: s.class, Parent: s.parent}
+
+	if i > 0 && p.tokens[i-1].Is(tokenizer.Identifier, "async") {
+		start = i - 1
+		function.Async = true
+	}
+
+	k := i + 1
+	if k < len(p.tokens) && p.tokens[k].IsPunctuator("*") {
+		function.Generator = true
+		k++
+	}
+
+	if k < len(p.tokens) && p.tokens[k].Kind == tokenizer.Identifier {
+		function.Name = p.tokens[k].Value
+		k++
+	} else {
+		function.Name = p.assignedName(start)
+	}
+
+	if p.isStatementStart(start) {
+		function.Kind = Declaration
+	}
+
+	k = p.skipTypeParameters(k)
+	if k >= len(p.tokens) || !p.tokens[k].IsPunctuator("(") || p.match[k] < 0 {
+		return 0, false
+	}
+
+	body := p.skipReturnType(p.match[k]+1, len(p.tokens), false)
+	if body >= len(p.tokens) || !p.tokens[body].IsPunctuator("{") || p.match[body] < 0 {
+		return 0, false
+	}
+
+	function.Params = p.countParams(k, p.match[k])
+	return p.add(function, start, k, body, p.match[body]), true
+}
+
+func (p *parser) arrow(i, to int, s scope) (int, bool) {
+	start := i
+	function := Function{Kind: Arrow, Class: s.class, Parent: s.parent}
+
+	k := i
+	if p.tokens[k].Is(tokenizer.Identifier, "async") && k+1 < to &&
+		(p.tokens[k+1].IsPunctuator("(") || p.tokens[k+1].Kind == tokenizer.Identifier) {
+		function.Async = true
+		k++
+	}
+
+	var params, arrow int
+	switch {
+	case p.tokens[k].IsPunctuator("(") && p.match[k] > k:
+		// `call(args) =>` is not valid, so the parenthesis must not follow a callee
+		if !function.Async && k > 0 && p.isCallee(k-1) {
+			return 0, false
+		}
+		params = k
+		arrow = p.skipReturnType(p.match[k]+1, to, true)
+		function.Params = p.countParams(k, p.match[k])
+		if p.typescript {
+			start = p.typeParametersStart(start)
+		}
+	case p.tokens[k].Kind == tokenizer.Identifier:
+		params = -1
+		arrow = k + 1
+		function.Params = 1
+	default:
+		return 0, false
+	}
+
+	if arrow >= to || !p.tokens[arrow].IsPunctuator("=>") {
+		return 0, false
+	}
+
+	function.Name = p.assignedName(start)
+	if s.inClass && function.Name != "" {
+		function.Access = p.fieldAccess(start)
+	}
+
+	bodyStart := arrow + 1
+	if bodyStart >= to {
+		return 0, false
+	}
+
+	var bodyEnd int
+	if p.tokens[bodyStart].IsPunctuator("{") && p.match[bodyStart] > bodyStart {
+		bodyEnd = p.match[bodyStart]
+	} else {
+		bodyEnd = p.conciseBodyEnd(bodyStart, to)
+	}
+
+	return p.add(function, start, params, bodyStart, bodyEnd), true
+}
+
+// method recognizes the method shorthand of object literals and class
+// bodies, including getters, setters, generators and static methods.
+func (p *parser) method(i int, s scope) (int, bool) {
+	if i > 0 && !s.inClass {
+		previous := p.tokens[i-1]
+		if !previous.IsPunctuator("{") && !previous.IsPunctuator(",") {
+			return 0, false
+		}
+	}
+	if i > 0 && (p.tokens[i-1].IsPunctuator(".") || p.tokens[i-1].IsPunctuator("@")) {
+		return 0, false
+	}
+
+	function := Function{Kind: Method, Class: s.class, Parent: s.parent}
+
+	k := i
+	for k+1 < len(p.tokens) {
+		token := p.tokens[k]
+		if token.IsPunctuator("*") {
+			function.Generator = true
+			k++
+			continue
+		}
+		if token.Kind != tokenizer.Identifier || !p.isModifier(k) {
+			break
+		}
+		switch token.Value {
+		case "static":
+			function.Static = true
+		case "async":
+			function.Async = true
+		case "get":
+			function.Kind = Getter
+		case "set":
+			function.Kind = Setter
+		case "public", "private", "protected":
+			function.Access = token.Value
+		}
+		k++
+	}
+
+	name := p.tokens[k]
+	open := k + 1
+	switch {
+	case name.Kind == tokenizer.Identifier, name.Kind == tokenizer.String, name.Kind == tokenizer.Number:
+		function.Name = name.Value
+	case name.Kind == tokenizer.Keyword && s.inClass && !controlKeywords[name.Value]:
+		function.Name = name.Value
+	case name.IsPunctuator("[") && p.match[k] > k:
+		function.Name = p.text(k, p.match[k])
+		open = p.match[k] + 1
+	default:
+		return 0, false
+	}
+
+	if p.typescript {
+		// optional methods and type parameters: name?<T>(
+		if open < len(p.tokens) && (p.tokens[open].IsPunctuator("?") || p.tokens[open].IsPunctuator("!")) {
+			open++
+		}
+		open = p.skipTypeParameters(open)
+	}
+
+	if open >= len(p.tokens) || !p.tokens[open].IsPunctuator("(") || p.match[open] < 0 {
+		return 0, false
+	}
+
+	body := p.skipReturnType(p.match[open]+1, len(p.tokens), false)
+	if body >= len(p.tokens) || !p.tokens[body].IsPunctuator("{") || p.match[body] < 0 {
+		return 0, false
+	}
+
+	function.Params = p.countParams(open, p.match[open])
+	return p.add(function, i, open, body, p.match[body]), true
+}
+
+// isModifier reports whether the identifier at index k modifies the member
+// name that follows it instead of being the member name itself.
+func (p *parser) isModifier(k int) bool {
+	switch value := p.tokens[k].Value; {
+	case value == "static", value == "async", value == "get", value == "set":
+	case p.typescript && typeScriptModifiers[value]:
+	default:
+		return false
+	}
+
+	next := p.tokens[k+1]
+	if next.Kind == tokenizer.Punctuator {
+		return next.IsPunctuator("[") || next.IsPunctuator("*")
+	}
+	return next.Line == p.tokens[k].Line || p.tokens[k].Value == "static"
+}
+
+// add records a function and scans its parameters and body for nested
+// functions. It returns the index of the token after the function.
+func (p *parser) add(function Function, start, params, bodyStart, bodyEnd int) int {
+	function.StartLine = p.tokens[start].Line
+	function.EndLine = p.tokens[bodyEnd].EndLine
+	function.BodyStart = bodyStart
+	function.BodyEnd = bodyEnd
+
+	index := len(p.file.Functions)
+	p.file.Functions = append(p.file.Functions, function)
+
+	inner := scope{class: function.Class, parent: index}
+	if params >= 0 && p.match[params] > params {
+		p.scan(params+1, p.match[params], inner)
+	}
+	if p.tokens[bodyStart].IsPunctuator("{") && p.match[bodyStart] == bodyEnd {
+		p.scan(bodyStart+1, bodyEnd, inner)
+	} else {
+		p.scan(bodyStart, bodyEnd+1, inner)
+	}
+
+	return bodyEnd + 1
+}
+
+// conciseBodyEnd returns the index of the last token of an arrow function
+// body written as a single expression.
+func (p *parser) conciseBodyEnd(from, to int) int {
+	end := from
+	for k := from; k < to; k++ {
+		token := p.tokens[k]
+		if k > from {
+			if token.IsPunctuator(",") || token.IsPunctuator(";") || p.isClosing(k) {
+				break
+			}
+			if token.Line > p.tokens[k-1].EndLine && statementKeywords[token.Value] {
+				break
+			}
+		}
+		if p.isOpening(k) && p.match[k] > k && p.match[k] < to {
+			k = p.match[k]
+		}
+		end = k
+	}
+	return end
+}
+
+// countParams counts the parameters declared between two parentheses.
+func (p *parser) countParams(open, close int) int {
+	if close <= open+1 {
+		return 0
+	}
+
+	count := 1
+	angles := 0
+	for k := open + 1; k < close; k++ {
+		if p.isOpening(k) && p.match[k] > k {
+			k = p.match[k]
+			continue
+		}
+		if p.typescript {
+			angles = p.angleDepth(k, angles)
+			if angles > 0 {
+				continue
+			}
+		}
+		// a trailing comma does not add a parameter
+		if p.tokens[k].IsPunctuator(",") && k+1 < close {
+			count++
+		}
+	}
+
+	// TypeScript's `this` parameter only declares the type of this
+	if p.typescript && p.tokens[open+1].Is(tokenizer.Keyword, "this") && p.tokens[open+2].IsPunctuator(":") {
+		count--
+	}
+	return count
+}
+
+// assignedName returns the name a function or class expression starting
+// at index start is bound to, as in `const name = () => {}`,
+// `this.name = function () {}` or `{ name: () => {} }`.
+func (p *parser) assignedName(start int) string {
+	if start < 2 {
+		return ""
+	}
+
+	operator := p.tokens[start-1]
+	target := p.tokens[start-2]
+	if !operator.IsPunctuator("=") && !operator.IsPunctuator(":") {
+		return ""
+	}
+	if owner, ok := p.typeOwner[start-1]; ok {
+		target = p.tokens[owner]
+	}
+	// `cond ? a : () => {}` is not a property
+	if operator.IsPunctuator(":") && start >= 3 && p.tokens[start-3].IsPunctuator("?") {
+		return ""
+	}
+
+	switch {
+	case target.Kind == tokenizer.Identifier:
+		return target.Value
+	case target.Kind == tokenizer.Keyword && start >= 3 && p.tokens[start-3].IsPunctuator("."):
+		return target.Value
+	case target.Kind == tokenizer.String && operator.IsPunctuator(":"):
+		return strings.Trim(target.Value, `'"`)
+	}
+	return ""
+}
+
+// isStatementStart reports whether the token at index i begins a
+// statement, which makes a `function` there a declaration.
+func (p *parser) isStatementStart(i int) bool {
+	if i == 0 {
+		return true
+	}
+	previous := p.tokens[i-1]
+	switch {
+	case previous.IsPunctuator(";"), previous.IsPunctuator("{"), previous.IsPunctuator("}"):
+		return true
+	case previous.Is(tokenizer.Keyword, "export"), previous.Is(tokenizer.Keyword, "default"):
+		return true
+	}
+	return false
+}
+
+// opensObject reports whether the `{` at index i opens an object literal
+// rather than a block.
+func (p *parser) opensObject(i int) bool {
+	if i == 0 {
+		return false
+	}
+	previous := p.tokens[i-1]
+	switch previous.Kind {
+	case tokenizer.Punctuator:
+		switch previous.Value {
+		case ")", "]", "}", ";", "{", "=>":
+			return false
+		}
+		return true
+	case tokenizer.Keyword, tokenizer.Identifier:
+		return keywordsBeforeObject[previous.Value]
+	case tokenizer.Template:
+		return strings.HasSuffix(previous.Value, "${")
+	}
+	return false
+}
+
+func (p *parser) isOpening(i int) bool {
+	token := p.tokens[i]
+	return token.Kind == tokenizer.Punctuator && (token.Value == "(" || token.Value == "[" || token.Value == "{")
+}
+
+func (p *parser) isClosing(i int) bool {
+	token := p.tokens[i]
+	return token.Kind == tokenizer.Punctuator && (token.Value == ")" || token.Value == "]" || token.Value == "}")
+}
+
+func (p *parser) isMemberAccess(i int) bool {
+	return i > 0 && (p.tokens[i-1].IsPunctuator(".") || p.tokens[i-1].IsPunctuator("?."))
+}
+
+// text joins the values of the tokens between from and to, inclusive.
+func (p *parser) text(from, to int) string {
+	var builder strings.Builder
+	for k := from; k <= to; k++ {
+		builder.WriteString(p.tokens[k].Value)
+	}
+	return builder.String()
+}
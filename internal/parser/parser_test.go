@@ -0,0 +1,207 @@
+package parser_test
+
+import (
+	"go-cli-tool/internal/parser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFunctionKinds(t *testing.T) {
+	source := `function declared(a, b) {
+	return a + b;
+}
+
+const expression = function () {};
+const arrow = async (x) => x * 2;
+const single = value => {
+	return value;
+};
+
+function* generate() {}
+
+class Shape extends Base {
+	constructor(name) {
+		super(name);
+	}
+
+	get area() {
+		return 0;
+	}
+
+	set area(value) {}
+
+	static async *items() {}
+
+	#secret() {}
+
+	handle = () => {};
+}
+
+const object = {
+	method(a, b, c,) {},
+	property: function () {},
+};
+`
+
+	file := parser.ParseSource(source, parser.JavaScript)
+
+	type expected struct {
+		name   string
+		kind   parser.FunctionKind
+		start  int
+		end    int
+		params int
+		class  string
+	}
+
+	want := []expected{
+		{"declared", parser.Declaration, 1, 3, 2, ""},
+		{"expression", parser.Expression, 5, 5, 0, ""},
+		{"arrow", parser.Arrow, 6, 6, 1, ""},
+		{"single", parser.Arrow, 7, 9, 1, ""},
+		{"generate", parser.Declaration, 11, 11, 0, ""},
+		{"constructor", parser.Method, 14, 16, 1, "Shape"},
+		{"area", parser.Getter, 18, 20, 0, "Shape"},
+		{"area", parser.Setter, 22, 22, 1, "Shape"},
+		{"items", parser.Method, 24, 24, 0, "Shape"},
+		{"#secret", parser.Method, 26, 26, 0, "Shape"},
+		{"handle", parser.Arrow, 28, 28, 0, "Shape"},
+		{"method", parser.Method, 32, 32, 3, ""},
+		{"property", parser.Expression, 33, 33, 0, ""},
+	}
+
+	if !assert.Len(t, file.Functions, len(want)) {
+		for _, function := range file.Functions {
+			t.Logf("%+v", function)
+		}
+		return
+	}
+
+	for i, function := range file.Functions {
+		assert.Equal(t, want[i].name, function.Name, "name of function %d", i)
+		assert.Equal(t, want[i].kind, function.Kind, "kind of %s", function.Name)
+		assert.Equal(t, want[i].start, function.StartLine, "start line of %s", function.Name)
+		assert.Equal(t, want[i].end, function.EndLine, "end line of %s", function.Name)
+		assert.Equal(t, want[i].params, function.Params, "params of %s", function.Name)
+		assert.Equal(t, want[i].class, function.Class, "class of %s", function.Name)
+	}
+
+	assert.True(t, file.Functions[2].Async)
+	assert.True(t, file.Functions[4].Generator)
+	assert.True(t, file.Functions[8].Static)
+	assert.True(t, file.Functions[8].Async)
+	assert.True(t, file.Functions[8].Generator)
+
+	assert.Equal(t, []parser.Class{{Name: "Shape", StartLine: 13, EndLine: 29}}, file.Classes)
+}
+
+func TestParseIgnoresBracesInsideLiterals(t *testing.T) {
+	source := "function outer() {\n" +
+		"\tconst text = '{ not a block';\n" +
+		"\tconst template = `${'}'} }`;\n" +
+		"\tconst re = /[{]/;\n" +
+		"\tsetTimeout(() => {\n" +
+		"\t\tdone();\n" +
+		"\t}, 10);\n" +
+		"}\n"
+
+	file := parser.ParseSource(source, parser.JavaScript)
+
+	assert.Len(t, file.Functions, 2)
+	assert.Equal(t, 1, file.Functions[0].StartLine)
+	assert.Equal(t, 8, file.Functions[0].EndLine)
+	assert.Equal(t, "", file.Functions[1].Name)
+	assert.Equal(t, 0, file.Functions[1].Parent)
+	assert.Equal(t, 3, file.Functions[1].Lines())
+}
+
+func TestParseTypeScript(t *testing.T) {
+	source := `interface Shape {
+	area(): number;
+	onResize: (width: number, height: number) => void;
+}
+
+enum Color { Red, Green }
+const enum Direction { Up = 1 }
+
+type Handler<T> = (event: T) => void;
+type Point = { x: number; y: number };
+
+export function total(items: Array<Item>, map: Map<string, number>): number {
+	return items.length;
+}
+
+abstract class Widget<T> implements Shape {
+	private readonly handlers: Handler<T>[] = [];
+	protected onResize: (width: number, height: number) => void = () => {};
+
+	constructor(private name: string, public size?: number) {
+		super();
+	}
+
+	public area(): number {
+		return 0;
+	}
+
+	abstract render(target: HTMLElement): void;
+
+	protected async load<K extends keyof T>(key: K): Promise<T[K]> {
+		return this.cache[key] as T[K];
+	}
+
+	private handle = (event: Event): boolean => true;
+}
+
+const identity = <T,>(value: T): T => value;
+const pick: Picker = (a, b) => (flag ? a : b);
+`
+
+	file := parser.ParseSource(source, parser.TypeScript)
+
+	type expected struct {
+		name   string
+		kind   parser.FunctionKind
+		start  int
+		end    int
+		params int
+		access string
+	}
+
+	want := []expected{
+		{"total", parser.Declaration, 12, 14, 2, ""},
+		{"onResize", parser.Arrow, 18, 18, 0, "protected"},
+		{"constructor", parser.Method, 20, 22, 2, ""},
+		{"area", parser.Method, 24, 26, 0, "public"},
+		{"load", parser.Method, 30, 32, 1, "protected"},
+		{"handle", parser.Arrow, 34, 34, 1, "private"},
+		{"identity", parser.Arrow, 37, 37, 1, ""},
+		{"pick", parser.Arrow, 38, 38, 2, ""},
+	}
+
+	if !assert.Len(t, file.Functions, len(want)) {
+		for _, function := range file.Functions {
+			t.Logf("%+v", function)
+		}
+		return
+	}
+
+	for i, function := range file.Functions {
+		assert.Equal(t, want[i].name, function.Name, "name of function %d", i)
+		assert.Equal(t, want[i].kind, function.Kind, "kind of %s", function.Name)
+		assert.Equal(t, want[i].start, function.StartLine, "start line of %s", function.Name)
+		assert.Equal(t, want[i].end, function.EndLine, "end line of %s", function.Name)
+		assert.Equal(t, want[i].params, function.Params, "params of %s", function.Name)
+		assert.Equal(t, want[i].access, function.Access, "access of %s", function.Name)
+	}
+
+	assert.Equal(t, []parser.TypeDeclaration{
+		{Name: "Shape", Kind: parser.Interface, StartLine: 1, EndLine: 4},
+		{Name: "Color", Kind: parser.Enum, StartLine: 6, EndLine: 6},
+		{Name: "Direction", Kind: parser.Enum, StartLine: 7, EndLine: 7},
+		{Name: "Handler", Kind: parser.TypeAlias, StartLine: 9, EndLine: 9},
+		{Name: "Point", Kind: parser.TypeAlias, StartLine: 10, EndLine: 10},
+	}, file.Types)
+
+	assert.Equal(t, []parser.Class{{Name: "Widget", StartLine: 16, EndLine: 35}}, file.Classes)
+}
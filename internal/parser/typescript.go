@@ -0,0 +1,337 @@
+package parser
+
+import "go-cli-tool/internal/tokenizer"
+
+// typeScriptModifiers may precede a class member name in TypeScript.
+var typeScriptModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "readonly": true,
+	"abstract": true, "override": true, "declare": true, "accessor": true,
+}
+
+// typeOperators are followed by another type, so a `{` or `(` after them
+// belongs to the type being skipped.
+var typeOperators = map[string]bool{
+	"|": true, "&": true, ":": true, "<": true, ",": true, "=>": true,
+	"?": true, ".": true, "=": true, "extends": true, "keyof": true,
+	"typeof": true, "infer": true, "is": true, "new": true, "readonly": true,
+	"unique": true, "asserts": true,
+}
+
+// typeContinuations may start a line that continues the type written on
+// the previous line.
+var typeContinuations = map[string]bool{
+	"|": true, "&": true, ".": true, "=>": true, "?": true, "extends": true,
+}
+
+// typeScriptConstruct skips type annotations and records interfaces,
+// enums and type aliases so that their bodies are never mistaken for code.
+func (p *parser) typeScriptConstruct(i, to int, s scope) (int, bool) {
+	token := p.tokens[i]
+
+	switch {
+	case token.IsPunctuator(":") && p.isTypeAnnotation(i, s):
+		end := p.skipType(i+1, to, false)
+		if end < to && p.tokens[end].IsPunctuator("=") {
+			owner := i - 1
+			if p.tokens[owner].IsPunctuator("?") || p.tokens[owner].IsPunctuator("!") {
+				owner--
+			}
+			p.typeOwner[end] = owner
+		}
+		return end, true
+	case token.Kind == tokenizer.Identifier && (token.Value == "as" || token.Value == "satisfies"):
+		if i == 0 || p.isMemberAccess(i) || p.tokens[i-1].IsPunctuator("*") {
+			return 0, false
+		}
+		return p.skipType(i+1, to, false), true
+	case token.Kind == tokenizer.Identifier && i+1 < to && p.tokens[i+1].Kind == tokenizer.Identifier:
+		if !p.isDeclarationStart(i) {
+			return 0, false
+		}
+		switch token.Value {
+		case "interface":
+			return p.interfaceDeclaration(i)
+		case "enum":
+			return p.enumDeclaration(i)
+		case "type":
+			return p.typeAlias(i, to)
+		}
+	}
+
+	return 0, false
+}
+
+func (p *parser) interfaceDeclaration(i int) (int, bool) {
+	k := i + 2
+	for k < len(p.tokens) && !p.tokens[k].IsPunctuator("{") {
+		if p.tokens[k].IsPunctuator(";") {
+			return 0, false
+		}
+		if p.isOpening(k) && p.match[k] > k {
+			k = p.match[k]
+		}
+		k++
+	}
+	if k >= len(p.tokens) || p.match[k] < 0 {
+		return 0, false
+	}
+
+	p.addType(Interface, i, i+1, p.match[k])
+	return p.match[k] + 1, true
+}
+
+func (p *parser) enumDeclaration(i int) (int, bool) {
+	body := i + 2
+	if body >= len(p.tokens) || !p.tokens[body].IsPunctuator("{") || p.match[body] < 0 {
+		return 0, false
+	}
+
+	start := i
+	if i > 0 && p.tokens[i-1].Is(tokenizer.Keyword, "const") {
+		start = i - 1
+	}
+	p.addType(Enum, start, i+1, p.match[body])
+	return p.match[body] + 1, true
+}
+
+func (p *parser) typeAlias(i, to int) (int, bool) {
+	k := p.skipTypeParameters(i + 2)
+	if k >= to || !p.tokens[k].IsPunctuator("=") {
+		return 0, false
+	}
+
+	end := p.skipType(k+1, to, false)
+	p.addType(TypeAlias, i, i+1, end-1)
+	return end, true
+}
+
+func (p *parser) addType(kind TypeKind, start, name, end int) {
+	p.file.Types = append(p.file.Types, TypeDeclaration{
+		Name:      p.tokens[name].Value,
+		Kind:      kind,
+		StartLine: p.tokens[start].Line,
+		EndLine:   p.tokens[end].EndLine,
+	})
+}
+
+// isDeclarationStart reports whether the contextual keyword at index i
+// begins a declaration rather than being used as a plain identifier.
+func (p *parser) isDeclarationStart(i int) bool {
+	if p.isStatementStart(i) {
+		return true
+	}
+	previous := p.tokens[i-1]
+	switch {
+	case previous.Is(tokenizer.Identifier, "declare"), previous.Is(tokenizer.Keyword, "const"):
+		return true
+	case previous.EndLine < p.tokens[i].Line:
+		return previous.Kind != tokenizer.Punctuator || previous.IsPunctuator(")") || previous.IsPunctuator("]")
+	}
+	return false
+}
+
+// isTypeAnnotation reports whether the `:` at index i introduces a type,
+// as opposed to an object property, a conditional expression, a case
+// clause or a label.
+func (p *parser) isTypeAnnotation(i int, s scope) bool {
+	if i == 0 {
+		return false
+	}
+
+	previous := p.tokens[i-1]
+	switch {
+	case previous.IsPunctuator("?"), previous.IsPunctuator("!"):
+		// optional and definitely assigned names: `name?: Type`
+		return true
+	case previous.Kind == tokenizer.Identifier, previous.Is(tokenizer.Keyword, "this"):
+	case previous.IsPunctuator(")"), previous.IsPunctuator("]"), previous.IsPunctuator("}"):
+	default:
+		return false
+	}
+
+	if s.members && !s.inClass {
+		return false
+	}
+	if i+1 < len(p.tokens) && p.tokens[i+1].Kind == tokenizer.Keyword && statementKeywords[p.tokens[i+1].Value] {
+		return false
+	}
+
+	for k := i - 1; k >= 0; k-- {
+		token := p.tokens[k]
+		if p.isClosing(k) && p.match[k] >= 0 && p.match[k] < k {
+			k = p.match[k]
+			continue
+		}
+		switch {
+		case token.IsPunctuator("?"), token.Is(tokenizer.Keyword, "case"):
+			return false
+		case token.IsPunctuator(";"), token.IsPunctuator(","), token.IsPunctuator("=>"), p.isOpening(k):
+			return true
+		}
+	}
+	return true
+}
+
+// skipType returns the index of the first token after the type starting
+// at index from. When stopAtArrow is set, a `=>` ends the type, as in the
+// return type of an arrow function.
+func (p *parser) skipType(from, to int, stopAtArrow bool) int {
+	angles := 0
+	expectType := true
+
+	for k := from; k < to; k++ {
+		token := p.tokens[k]
+
+		if angles == 0 {
+			if k > from && token.Line > p.tokens[k-1].EndLine && !expectType && !typeContinuations[token.Value] {
+				return k
+			}
+			switch {
+			case token.IsPunctuator(";"), token.IsPunctuator(","), token.IsPunctuator("="), p.isClosing(k):
+				return k
+			case token.IsPunctuator(">"), token.IsPunctuator(">>"), token.IsPunctuator(">>>"):
+				return k
+			case token.IsPunctuator("=>") && stopAtArrow:
+				return k
+			case token.IsPunctuator("{") && !expectType:
+				return k
+			}
+		}
+
+		if p.isOpening(k) && p.match[k] > k {
+			k = p.match[k]
+			expectType = false
+			continue
+		}
+
+		angles = p.angleDepth(k, angles)
+		expectType = token.Kind != tokenizer.String && typeOperators[token.Value]
+	}
+
+	return to
+}
+
+// skipReturnType skips the TypeScript return type that may follow the
+// parameters closed at index k-1 and returns the index after it.
+func (p *parser) skipReturnType(k, to int, stopAtArrow bool) int {
+	if !p.typescript || k >= to || !p.tokens[k].IsPunctuator(":") {
+		return k
+	}
+	return p.skipType(k+1, to, stopAtArrow)
+}
+
+// skipTypeParameters skips a `<...>` type parameter list starting at k.
+func (p *parser) skipTypeParameters(k int) int {
+	if !p.typescript || k >= len(p.tokens) || !p.tokens[k].IsPunctuator("<") {
+		return k
+	}
+
+	angles := 0
+	for ; k < len(p.tokens); k++ {
+		if p.isOpening(k) && p.match[k] > k {
+			k = p.match[k]
+			continue
+		}
+		angles = p.angleDepth(k, angles)
+		if angles == 0 {
+			return k + 1
+		}
+	}
+	return k
+}
+
+// typeParametersStart moves the start of a generic arrow function such as
+// `<T>(value: T) => value` back to its type parameter list.
+func (p *parser) typeParametersStart(start int) int {
+	if start == 0 || !p.tokens[start-1].IsPunctuator(">") {
+		return start
+	}
+
+	depth := 0
+	for k := start - 1; k >= 0; k-- {
+		token := p.tokens[k]
+		switch {
+		case token.IsPunctuator(">"):
+			depth++
+		case token.IsPunctuator(">>"):
+			depth += 2
+		case token.IsPunctuator("<"):
+			depth--
+		case p.isClosing(k) && p.match[k] >= 0 && p.match[k] < k:
+			k = p.match[k]
+		case token.IsPunctuator(";"), p.isOpening(k), p.isClosing(k):
+			return start
+		}
+		if depth == 0 {
+			if k > 0 && p.tokens[k-1].Is(tokenizer.Identifier, "async") {
+				return k - 1
+			}
+			return k
+		}
+	}
+	return start
+}
+
+// angleDepth returns the `<...>` nesting depth after the token at index k.
+func (p *parser) angleDepth(k, depth int) int {
+	token := p.tokens[k]
+	if token.Kind != tokenizer.Punctuator {
+		return depth
+	}
+
+	switch token.Value {
+	case "<":
+		return depth + 1
+	case ">":
+		depth--
+	case ">>":
+		depth -= 2
+	case ">>>":
+		depth -= 3
+	}
+	if depth < 0 {
+		return 0
+	}
+	return depth
+}
+
+// isCallee reports whether the token at index k can be called, which makes
+// a `(` after it the start of arguments rather than of parameters.
+func (p *parser) isCallee(k int) bool {
+	token := p.tokens[k]
+	switch token.Kind {
+	case tokenizer.Identifier:
+		switch token.Value {
+		case "async", "await", "yield", "of":
+			return false
+		}
+		return true
+	case tokenizer.Keyword:
+		return token.Value == "this" || token.Value == "super"
+	}
+	return token.IsPunctuator(")") || token.IsPunctuator("]")
+}
+
+// fieldAccess returns the access modifier of the class field that the
+// function starting at index start is assigned to.
+func (p *parser) fieldAccess(start int) string {
+	if !p.typescript || start < 2 {
+		return ""
+	}
+
+	name := start - 2
+	if owner, ok := p.typeOwner[start-1]; ok {
+		name = owner
+	}
+
+	for k := name - 1; k >= 0 && p.tokens[k].Kind == tokenizer.Identifier; k-- {
+		value := p.tokens[k].Value
+		switch {
+		case value == "public", value == "private", value == "protected":
+			return value
+		case !typeScriptModifiers[value] && value != "static":
+			return ""
+		}
+	}
+	return ""
+}
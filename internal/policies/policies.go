@@ -1,12 +1,11 @@
 package policies
 
 import (
+	"errors"
 	"fmt"
 	"go-cli-tool/internal/utils"
 	"os"
 	"path/filepath"
-
-	"github.com/spf13/cobra"
 )
 
 func ValidateDirectoryPath(directoryPath string) bool {
@@ -18,36 +17,61 @@ func ValidateDirectoryPath(directoryPath string) bool {
     return info.IsDir()
 }
 
+// IsJSFileExtension reports whether the file holds JavaScript or
+// TypeScript source code, JSX and single-file components included.
 func IsJSFileExtension(filePath string) bool {
 	ext := filepath.Ext(filePath)
 
-	return ext == ".js" || ext == ".mjs"
+	return ext == ".js" || ext == ".mjs" || ext == ".jsx" ||
+		IsTypeScriptFileExtension(filePath) || IsComponentFileExtension(filePath)
 }
 
+// IsTypeScriptFileExtension reports whether the file holds TypeScript
+// source code.
+func IsTypeScriptFileExtension(filePath string) bool {
+	switch filepath.Ext(filePath) {
+	case ".ts", ".tsx", ".mts", ".cts":
+		return true
+	}
+	return false
+}
 
-func ValidateFilePath(err bool, cmd *cobra.Command) bool {
-
-    if !IsJSFileExtension(utils.FilePath) {
-        fmt.Fprintf(cmd.OutOrStdout(), "%sOnly JavaScript files are accepted.%s", utils.RED, utils.RESET_COLOR)
-        err = true
-    }
+// IsComponentFileExtension reports whether the file is a Vue or Svelte
+// single-file component, whose code lives in <script> blocks.
+func IsComponentFileExtension(filePath string) bool {
+	ext := filepath.Ext(filePath)
 
-    return err
+	return ext == ".vue" || ext == ".svelte"
 }
 
 
-func ValidateUserInput(cmd *cobra.Command) bool {
+// acceptedExtensions lists the extensions IsJSFileExtension accepts, for
+// the error messages.
+const acceptedExtensions = ".js, .mjs, .jsx, .ts, .tsx, .mts, .cts, .vue and .svelte"
 
-    err := false
+// ValidateFilePath returns an error when the file is not a JavaScript or
+// TypeScript file.
+func ValidateFilePath(filePath string) error {
+
+    if !IsJSFileExtension(filePath) {
+        return fmt.Errorf("only JavaScript and TypeScript files are accepted (%s)", acceptedExtensions)
+    }
+
+    return nil
+}
+
+// ValidateUserInput returns an error when neither a file (-f) nor a
+// directory (-d) is given, or when the file is not a JavaScript or
+// TypeScript file.
+func ValidateUserInput() error {
 
     if utils.FilePath == "" && utils.DirectoryPath == "" {
-        cmd.Println("Please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files.")
-        err = true
+        return errors.New("please provide the path to the JavaScript file using the -f flag or use the -d flag to provide the path to the directory containing the JavaScript files")
     }
 
     if utils.FilePath != "" {
-       err = ValidateFilePath(err,cmd)
+        return ValidateFilePath(utils.FilePath)
     }
 
-    return err
-}
\ No newline at end of file
+    return nil
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"testing"
 
-	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -38,38 +37,48 @@ func TestIsJsFileExetension(t *testing.T) {
 	filePath = "file.mjs"
 	assert.True(t, policies.IsJSFileExtension(filePath), "Expected true for .mjs file extension")
 
+	// Test case: TypeScript files are accepted
+	for _, filePath := range []string{"file.ts", "file.tsx", "file.mts", "file.cts"} {
+		assert.True(t, policies.IsJSFileExtension(filePath), "Expected true for %s", filePath)
+		assert.True(t, policies.IsTypeScriptFileExtension(filePath), "Expected %s to be TypeScript", filePath)
+	}
+	assert.False(t, policies.IsTypeScriptFileExtension("file.js"), "Expected .js not to be TypeScript")
+
+	// Test case: JSX and single-file components are accepted
+	for _, filePath := range []string{"file.jsx", "file.vue", "file.svelte"} {
+		assert.True(t, policies.IsJSFileExtension(filePath), "Expected true for %s", filePath)
+	}
+	assert.True(t, policies.IsComponentFileExtension("file.vue"), "Expected .vue to be a component")
+	assert.False(t, policies.IsComponentFileExtension("file.jsx"), "Expected .jsx not to be a component")
+
 	// Test case: File has .txt extension
 	filePath = "file.txt"
 	assert.False(t, policies.IsJSFileExtension(filePath), "Expected false for .txt file extension")
 }
 
 func TestValidateFilePath(t *testing.T) {
+	assert.NoError(t, policies.ValidateFilePath("file.js"), "Expected no error for .js file extension")
+	assert.NoError(t, policies.ValidateFilePath("file.mjs"), "Expected no error for .mjs file extension")
+	assert.NoError(t, policies.ValidateFilePath("file.vue"), "Expected no error for .vue file extension")
 
-	var err bool
-	utils.FilePath = "file.js"
-	cmd := &cobra.Command{}
-	assert.False(t, policies.ValidateFilePath(err, cmd), "Expected false for .js file extension")
-
-	utils.FilePath = "file.mjs"
-	cmd = &cobra.Command{}
-	assert.False(t, policies.ValidateFilePath(err, cmd), "Expected false for .mjs file extension")
-
-	utils.FilePath = "file.txt"
-	cmd = &cobra.Command{}
-	assert.True(t, policies.ValidateFilePath(err, cmd), "Expected true for .txt file extension")
+	err := policies.ValidateFilePath("file.txt")
+	assert.Error(t, err, "Expected an error for .txt file extension")
+	assert.ErrorContains(t, err, ".ts")
 }
 
 func TestValidateUserInput(t *testing.T) {
-	cmd := &cobra.Command{}
-
 	tests.ResetGlobals()
-	assert.True(t, policies.ValidateUserInput(cmd), "Expected true for no file path or directory path provided")
+	assert.Error(t, policies.ValidateUserInput(), "Expected an error for no file path or directory path provided")
 
 	tests.ResetGlobals()
 	utils.FilePath = "file.js"
-	assert.False(t, policies.ValidateUserInput(cmd), "Expected false for file path provided")
+	assert.NoError(t, policies.ValidateUserInput(), "Expected no error for file path provided")
+
+	tests.ResetGlobals()
+	utils.FilePath = "notes.txt"
+	assert.Error(t, policies.ValidateUserInput(), "Expected an error for a file that is not JavaScript")
 
 	tests.ResetGlobals()
 	utils.DirectoryPath = t.TempDir()
-	assert.False(t, policies.ValidateUserInput(cmd), "Expected false for directory path provided")
-}
\ No newline at end of file
+	assert.NoError(t, policies.ValidateUserInput(), "Expected no error for directory path provided")
+}
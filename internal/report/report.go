@@ -0,0 +1,259 @@
+// Package report renders the results of the commands in the output formats
+// selected with --format, writing them to the terminal or to a file.
+package report
+
+import (
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Format names an output format.
+type Format string
+
+const (
+	Text     Format = "text"
+	JSON     Format = "json"
+	CSV      Format = "csv"
+	Markdown Format = "markdown"
+	HTML     Format = "html"
+	YAML     Format = "yaml"
+)
+
+// FlagUsage is the help text of the --format flag shared by the commands.
+const FlagUsage = "Output format: text, json, csv, markdown, html or yaml. Defaults to the extension of the -o path, or text in the terminal."
+
+// Column is a column of the per-file table of a report.
+type Column struct {
+	// Key names the column in the json, yaml and csv formats.
+	Key string
+	// Title names the column in the text, markdown and html formats.
+	Title string
+}
+
+// Field is a summary value of a report.
+type Field struct {
+	Key   string
+	Title string
+	Value interface{}
+}
+
+// Report is the result of a command, independent of its output format.
+type Report struct {
+	Title   string
+	Columns []Column
+	// Rows hold one line of the table per file, in the order of Columns.
+	Rows    [][]interface{}
+	Summary []Field
+	// Data, when set, is encoded by the json and yaml formats instead of
+	// the table and the summary, for commands with a richer structure.
+	Data interface{}
+	// Text, when set, renders the text format instead of the table, for
+	// commands with their own terminal layout.
+	Text func(w io.Writer)
+	// HTML, when set, renders the html format instead of the table, for
+	// commands with their own page.
+	HTML func(w io.Writer) error
+	// Markdown, when set, renders the markdown format instead of the table,
+	// for commands whose output is meant to be posted as a comment.
+	Markdown func(w io.Writer) error
+	// Skipped are the files the analysis could not read. The text,
+	// markdown, json and yaml formats list them after the results, and the
+	// html format in its default page.
+	Skipped []analyzer.SkippedFile
+}
+
+// Writer renders a report in one format.
+type Writer interface {
+	Write(w io.Writer, report Report) error
+}
+
+// WriterFunc adapts a function to the Writer interface.
+type WriterFunc func(w io.Writer, report Report) error
+
+func (f WriterFunc) Write(w io.Writer, report Report) error {
+	return f(w, report)
+}
+
+type registration struct {
+	writer    Writer
+	extension string
+}
+
+var writers = map[Format]registration{}
+
+// aliases are the alternative names accepted for the formats.
+var aliases = map[string]Format{"md": Markdown, "yml": YAML, "txt": Text}
+
+// Register makes a writer available under a format name. The extension is
+// used to recognize the format from the output path and to name the file
+// written into an output directory.
+func Register(format Format, extension string, writer Writer) {
+	writers[format] = registration{writer: writer, extension: extension}
+}
+
+func init() {
+	Register(Text, ".txt", WriterFunc(writeText))
+	Register(JSON, ".json", WriterFunc(writeJSON))
+	Register(CSV, ".csv", WriterFunc(writeCSV))
+	Register(Markdown, ".md", WriterFunc(writeMarkdown))
+	Register(HTML, ".html", WriterFunc(writeHTML))
+	Register(YAML, ".yaml", WriterFunc(writeYAML))
+}
+
+// Formats returns the names of the registered formats, sorted.
+func Formats() []string {
+	names := make([]string, 0, len(writers))
+	for format := range writers {
+		names = append(names, string(format))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseFormat returns the format with the given name or alias.
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if format, ok := aliases[name]; ok {
+		return format, nil
+	}
+	if _, ok := writers[Format(name)]; ok {
+		return Format(name), nil
+	}
+	return "", fmt.Errorf("unknown format %q, expected one of: %s", name, strings.Join(Formats(), ", "))
+}
+
+// formatOfPath recognizes the format from the extension of a path.
+func formatOfPath(path string) (Format, bool) {
+	extension := strings.ToLower(filepath.Ext(path))
+	if extension == "" {
+		return "", false
+	}
+	if format, ok := aliases[extension[1:]]; ok {
+		return format, true
+	}
+	for format, registered := range writers {
+		if registered.extension == extension || "."+string(format) == extension {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// Options tell where and how a report is written.
+type Options struct {
+	// Format is the format requested with --format. When empty it is taken
+	// from the extension of Path, and text is used in the terminal.
+	Format string
+	// Path is the output file, or a directory to write the report into.
+	// When empty the report is written to the terminal.
+	Path string
+	// FileFormat is the format of an output path without a known
+	// extension, such as a directory.
+	FileFormat Format
+	// FileName is the name, without extension, of the file written into an
+	// output directory. It defaults to "report".
+	FileName string
+}
+
+// resolve returns the format and the path of the output.
+func (o Options) resolve() (Format, string, error) {
+	format := Text
+	switch {
+	case o.Format != "":
+		parsed, err := ParseFormat(o.Format)
+		if err != nil {
+			return "", "", err
+		}
+		format = parsed
+	case o.Path != "":
+		if parsed, ok := formatOfPath(o.Path); ok {
+			format = parsed
+		} else if o.FileFormat != "" {
+			format = o.FileFormat
+		}
+	}
+
+	path := o.Path
+	if path != "" && isDirectory(path) {
+		name := o.FileName
+		if name == "" {
+			name = "report"
+		}
+		path = filepath.Join(path, name+writers[format].extension)
+	}
+
+	return format, path, nil
+}
+
+// isDirectory tells whether a path names a directory, either an existing
+// one or, with a trailing separator, one to be created.
+func isDirectory(path string) bool {
+	if strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(filepath.Separator)) {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Output writes a report to out, or to the output file of the options and
+// a confirmation to out. The content is the same in both cases.
+func Output(out io.Writer, report Report, options Options) error {
+	format, path, err := options.resolve()
+	if err != nil {
+		return err
+	}
+	writer := writers[format].writer
+
+	if path == "" {
+		return writer.Write(out, report)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	if err := writer.Write(file, report); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing %s report: %w", format, err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\033[1;34mReport generated successfully at %s%s\n", path, utils.RESET_COLOR)
+	return nil
+}
+
+// SortedKeys returns the keys of a map of per-file results in order, so
+// that the rows of a report are stable.
+func SortedKeys[V any](results map[string]V) []string {
+	keys := make([]string, 0, len(results))
+	for key := range results {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Print writes a report as requested by the --format and -o flags, which
+// the commands bind to utils.OutputFormat and utils.OutputFilePath, and
+// returns the error of the output. fileFormat is used for an output path
+// without a known extension.
+func Print(out io.Writer, report Report, fileFormat Format) error {
+	options := Options{
+		Format:     utils.OutputFormat,
+		Path:       utils.OutputFilePath,
+		FileFormat: fileFormat,
+	}
+	return Output(out, report, options)
+}
@@ -0,0 +1,122 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-cli-tool/internal/report"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleReport() report.Report {
+	return report.Report{
+		Title: "Count Lines",
+		Columns: []report.Column{
+			{Key: "file", Title: "File"},
+			{Key: "lines", Title: "Lines"},
+		},
+		Rows:    [][]interface{}{{"a.js", 6}, {"b|c.js", 2}},
+		Summary: []report.Field{{Key: "total_lines", Title: "Total lines", Value: 8}},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := report.ParseFormat("MD")
+	assert.NoError(t, err)
+	assert.Equal(t, report.Markdown, format)
+
+	format, err = report.ParseFormat("yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, report.YAML, format)
+
+	_, err = report.ParseFormat("xml")
+	assert.ErrorContains(t, err, `unknown format "xml"`)
+}
+
+func TestOutputFormats(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"text", "File    Lines\na.js    6\nb|c.js  2\nTotal lines: 8\n"},
+		{"csv", "file,lines\na.js,6\nb|c.js,2\n"},
+		{"markdown", "# Count Lines\n\n| File | Lines |\n| --- | --- |\n| a.js | 6 |\n| b\\|c.js | 2 |\n\n## Summary\n\n- **Total lines:** 8\n"},
+		{"yaml", "files:\n  - file: a.js\n    lines: 6\n  - file: b|c.js\n    lines: 2\nreport: Count Lines\nsummary:\n  total_lines: 8\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out bytes.Buffer
+			err := report.Output(&out, sampleReport(), report.Options{Format: test.format})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, out.String())
+		})
+	}
+}
+
+func TestOutputJSON(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, report.Output(&out, sampleReport(), report.Options{Format: "json"}))
+
+	var document map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &document))
+	assert.Equal(t, "Count Lines", document["report"])
+	assert.Equal(t, map[string]interface{}{"total_lines": float64(8)}, document["summary"])
+	assert.Len(t, document["files"], 2)
+}
+
+func TestOutputTextUsesCustomLayout(t *testing.T) {
+	r := sampleReport()
+	r.Text = func(w io.Writer) { fmt.Fprint(w, "custom\n") }
+
+	var out bytes.Buffer
+	assert.NoError(t, report.Output(&out, r, report.Options{}))
+	assert.Equal(t, "custom\n", out.String())
+}
+
+func TestOutputFormatFromExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "lines.md")
+
+	var out bytes.Buffer
+	assert.NoError(t, report.Output(&out, sampleReport(), report.Options{Path: path}))
+	assert.Contains(t, out.String(), "Report generated successfully at "+path)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "# Count Lines")
+}
+
+func TestOutputIntoDirectory(t *testing.T) {
+	directory := t.TempDir()
+
+	var out bytes.Buffer
+	options := report.Options{Path: directory, FileFormat: report.HTML}
+	assert.NoError(t, report.Output(&out, sampleReport(), options))
+	assert.FileExists(t, filepath.Join(directory, "report.html"))
+
+	options = report.Options{Path: directory, Format: "csv", FileName: "lines"}
+	assert.NoError(t, report.Output(&out, sampleReport(), options))
+	content, err := os.ReadFile(filepath.Join(directory, "lines.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "file,lines\na.js,6\nb|c.js,2\n", string(content))
+}
+
+func TestOutputIntoNewDirectory(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "reports") + "/"
+
+	var out bytes.Buffer
+	assert.NoError(t, report.Output(&out, sampleReport(), report.Options{Path: directory, FileFormat: report.JSON}))
+	assert.FileExists(t, filepath.Join(directory, "report.json"))
+}
+
+func TestOutputUnknownFormat(t *testing.T) {
+	var out bytes.Buffer
+	err := report.Output(&out, sampleReport(), report.Options{Format: "xml"})
+	assert.Error(t, err)
+	assert.Empty(t, out.String())
+}
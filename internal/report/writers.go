@@ -0,0 +1,286 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/utils"
+	"go-cli-tool/templates"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatValue renders a cell or summary value as the text formats show it.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// FormatNumber renders a metric, counts without decimals and averages with
+// two.
+func FormatNumber(value float64) string {
+	if value == math.Trunc(value) {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+// FormatChange renders the change of a metric with its sign.
+func FormatChange(change float64) string {
+	if change > 0 {
+		return "+" + FormatNumber(change)
+	}
+	if change == 0 {
+		return "0"
+	}
+	return FormatNumber(change)
+}
+
+// document returns the value encoded by the structured formats: the data
+// of the report, or its table and summary, with the skipped files under a
+// "skipped" key.
+func (r Report) document() interface{} {
+	document := r.Data
+	if document == nil {
+		document = r.Table()
+	}
+	if len(r.Skipped) == 0 {
+		return document
+	}
+
+	// the data of the commands is a struct or a map encoded as an object,
+	// which gets the key through its JSON form
+	var fields map[string]interface{}
+	content, err := json.Marshal(document)
+	if err != nil || json.Unmarshal(content, &fields) != nil || fields == nil {
+		return document
+	}
+	fields["skipped"] = r.Skipped
+	return fields
+}
+
+// writeSkipped lists the skipped files after the text format.
+func writeSkipped(w io.Writer, r Report) {
+	if len(r.Skipped) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%sSkipped files:%s\n", utils.RED, utils.RESET_COLOR)
+	for _, file := range r.Skipped {
+		fmt.Fprintf(w, "  %s: %s\n", file.Path, file.Reason)
+	}
+}
+
+// Table returns the table and the summary of the report as the json and
+// yaml formats encode them, with a "files" object per row keyed by the
+// column keys. Commands extend it to build their Data.
+func (r Report) Table() map[string]interface{} {
+	files := make([]map[string]interface{}, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		file := make(map[string]interface{}, len(r.Columns))
+		for i, column := range r.Columns {
+			if i < len(row) {
+				file[column.Key] = row[i]
+			}
+		}
+		files = append(files, file)
+	}
+
+	summary := make(map[string]interface{}, len(r.Summary))
+	for _, field := range r.Summary {
+		summary[field.Key] = field.Value
+	}
+
+	return map[string]interface{}{
+		"report":  r.Title,
+		"files":   files,
+		"summary": summary,
+	}
+}
+
+func writeText(w io.Writer, r Report) error {
+	defer writeSkipped(w, r)
+
+	if r.Text != nil {
+		r.Text(w)
+		return nil
+	}
+
+	if len(r.Columns) > 0 {
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		titles := make([]string, len(r.Columns))
+		for i, column := range r.Columns {
+			titles[i] = column.Title
+		}
+		fmt.Fprintln(table, strings.Join(titles, "\t"))
+		for _, row := range r.Rows {
+			cells := make([]string, len(row))
+			for i, value := range row {
+				cells[i] = FormatValue(value)
+			}
+			fmt.Fprintln(table, strings.Join(cells, "\t"))
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range r.Summary {
+		fmt.Fprintf(w, "%s: %s\n", field.Title, FormatValue(field.Value))
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, r Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.document())
+}
+
+func writeYAML(w io.Writer, r Report) error {
+	// going through JSON applies the json tags of the analyzer results, so
+	// both formats use the same field names
+	content, err := json.Marshal(r.document())
+	if err != nil {
+		return err
+	}
+	var document interface{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeCSV writes the per-file table, or the summary for reports without
+// one.
+func writeCSV(w io.Writer, r Report) error {
+	writer := csv.NewWriter(w)
+
+	if len(r.Columns) == 0 {
+		writer.Write([]string{"metric", "value"})
+		for _, field := range r.Summary {
+			writer.Write([]string{field.Key, FormatValue(field.Value)})
+		}
+	} else {
+		header := make([]string, len(r.Columns))
+		for i, column := range r.Columns {
+			header[i] = column.Key
+		}
+		writer.Write(header)
+		for _, row := range r.Rows {
+			record := make([]string, len(row))
+			for i, value := range row {
+				record[i] = FormatValue(value)
+			}
+			writer.Write(record)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeMarkdown(w io.Writer, r Report) error {
+	if r.Markdown != nil {
+		if err := r.Markdown(w); err != nil {
+			return err
+		}
+		writeMarkdownSkipped(w, r)
+		return nil
+	}
+
+	cell := func(value interface{}) string {
+		return strings.ReplaceAll(FormatValue(value), "|", `\|`)
+	}
+
+	fmt.Fprintf(w, "# %s\n", r.Title)
+
+	if len(r.Columns) > 0 {
+		titles := make([]string, len(r.Columns))
+		separators := make([]string, len(r.Columns))
+		for i, column := range r.Columns {
+			titles[i] = cell(column.Title)
+			separators[i] = "---"
+		}
+		fmt.Fprintf(w, "\n| %s |\n| %s |\n", strings.Join(titles, " | "), strings.Join(separators, " | "))
+		for _, row := range r.Rows {
+			cells := make([]string, len(row))
+			for i, value := range row {
+				cells[i] = cell(value)
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+
+	if len(r.Summary) > 0 {
+		fmt.Fprintf(w, "\n## Summary\n\n")
+		for _, field := range r.Summary {
+			fmt.Fprintf(w, "- **%s:** %s\n", field.Title, FormatValue(field.Value))
+		}
+	}
+	writeMarkdownSkipped(w, r)
+	return nil
+}
+
+func writeMarkdownSkipped(w io.Writer, r Report) {
+	if len(r.Skipped) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n## Skipped files\n\n")
+	for _, file := range r.Skipped {
+		fmt.Fprintf(w, "- `%s`: %s\n", file.Path, file.Reason)
+	}
+}
+
+// SkippedItems converts the skipped files for the HTML templates.
+func SkippedItems(files []analyzer.SkippedFile) []templates.SkippedItem {
+	var items []templates.SkippedItem
+	for _, file := range files {
+		items = append(items, templates.SkippedItem{Path: file.Path, Reason: file.Reason})
+	}
+	return items
+}
+
+func writeHTML(w io.Writer, r Report) error {
+	if r.HTML != nil {
+		return r.HTML(w)
+	}
+
+	data := templates.ReportData{Title: r.Title}
+
+	for _, column := range r.Columns {
+		data.Columns = append(data.Columns, column.Title)
+	}
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = FormatValue(value)
+		}
+		data.Rows = append(data.Rows, cells)
+	}
+	for _, field := range r.Summary {
+		data.Summary = append(data.Summary, templates.SummaryItem{Title: field.Title, Value: FormatValue(field.Value)})
+	}
+	data.Skipped = SkippedItems(r.Skipped)
+
+	return templates.RenderReport(w, data)
+}
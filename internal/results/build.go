@@ -0,0 +1,190 @@
+package results
+
+import (
+	"go-cli-tool/internal/analyzer"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// New builds the report of the results of the analyzers for the files
+// under root, keyed by their slash-separated path relative to root, as the
+// directory analyses key them.
+func New(root string, files map[string]analyzer.FileAnalysis, skipped []analyzer.SkippedFile) Report {
+	if absolute, err := filepath.Abs(root); err == nil {
+		root = absolute
+	}
+	report := Report{SchemaVersion: SchemaVersion, Root: root, Files: make([]File, 0, len(files))}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	totals := &report.Totals
+	var totalLines, withFunctions int
+	var averages, maintainability float64
+	var complexities []int
+	for _, path := range paths {
+		file := files[path]
+		report.Files = append(report.Files, newFile(path, file))
+
+		totals.Files++
+		totals.Lines += file.Lines.TotalLines
+		totals.CommentLines += file.Comments.CommentLines
+		totalLines += file.Percent.TotalLines
+		totals.Classes += file.ClassesAndFunctions.Classes
+		totals.Functions += file.ClassesAndFunctions.Functions
+		totals.Interfaces += file.ClassesAndFunctions.Interfaces
+		totals.Enums += file.ClassesAndFunctions.Enums
+		totals.TypeAliases += file.ClassesAndFunctions.TypeAliases
+		totals.PublicMethods += file.Methods.Public
+		totals.PrivateMethods += file.Methods.Private
+		if file.AverageFunctionSize > 0 {
+			averages += file.AverageFunctionSize
+			withFunctions++
+		}
+		for _, function := range file.Complexity.Functions {
+			complexities = append(complexities, function.Complexity)
+		}
+		maintainability += file.Maintainability.MaintainabilityIndex
+		totals.Halstead.Volume += file.Maintainability.Halstead.Volume
+		totals.Halstead.Difficulty += file.Maintainability.Halstead.Difficulty
+		totals.Halstead.Effort += file.Maintainability.Halstead.Effort
+		totals.Dependencies = append(totals.Dependencies, file.Dependencies.Dependencies...)
+		totals.NativeModules = append(totals.NativeModules, file.Dependencies.NativeModules...)
+	}
+
+	if totalLines > 0 {
+		totals.CommentPercentage = float64(totals.CommentLines) / float64(totalLines) * 100
+	}
+	if withFunctions > 0 {
+		totals.AverageFunctionSize = averages / float64(withFunctions)
+	}
+	if len(complexities) > 0 {
+		sum := 0
+		for _, complexity := range complexities {
+			sum += complexity
+			totals.MaxComplexity = max(totals.MaxComplexity, complexity)
+		}
+		totals.AverageComplexity = float64(sum) / float64(len(complexities))
+	}
+	if totals.Files > 0 {
+		totals.MaintainabilityIndex = maintainability / float64(totals.Files)
+		totals.Halstead.Difficulty /= float64(totals.Files)
+	}
+	totals.Dependencies = uniqueSorted(totals.Dependencies)
+	totals.NativeModules = uniqueSorted(totals.NativeModules)
+
+	for _, file := range skipped {
+		report.Skipped = append(report.Skipped, SkippedFile{Path: relativePath(root, file.Path), Reason: file.Reason})
+	}
+	return report
+}
+
+// newFile converts the results of the analyzers for a file.
+func newFile(path string, file analyzer.FileAnalysis) File {
+	indentation := file.Indentation
+	result := File{
+		Path:                 path,
+		Lines:                file.Lines.TotalLines,
+		CommentLines:         file.Comments.CommentLines,
+		CommentPercentage:    file.Percent.CommentPercentage,
+		Classes:              file.ClassesAndFunctions.Classes,
+		FunctionCount:        file.ClassesAndFunctions.Functions,
+		Interfaces:           file.ClassesAndFunctions.Interfaces,
+		Enums:                file.ClassesAndFunctions.Enums,
+		TypeAliases:          file.ClassesAndFunctions.TypeAliases,
+		PublicMethods:        file.Methods.Public,
+		PrivateMethods:       file.Methods.Private,
+		AverageFunctionSize:  file.AverageFunctionSize,
+		AverageComplexity:    file.Complexity.Average,
+		MaxComplexity:        file.Complexity.Max,
+		MaintainabilityIndex: file.Maintainability.MaintainabilityIndex,
+		Halstead: Halstead{
+			Volume:     file.Maintainability.Halstead.Volume,
+			Difficulty: file.Maintainability.Halstead.Difficulty,
+			Effort:     file.Maintainability.Halstead.Effort,
+		},
+		Indentation: Indentation{
+			MaxLevel:               indentation.MaxIndentLevel,
+			AverageLevel:           indentation.AverageIndentLevel,
+			UsesSpaces:             indentation.UsesSpaces,
+			UsesTabs:               indentation.UsesTabs,
+			Mixed:                  indentation.MixedIndentation,
+			MixedLine:              indentation.MixedIndentationLine,
+			MaxNestingDepth:        indentation.MaxNestingDepth,
+			MaxCognitiveComplexity: indentation.MaxCognitiveComplexity,
+			Distribution:           make([]IndentLevel, 0, len(indentation.IndentDistribution)),
+		},
+		Dependencies:  nonNil(file.Dependencies.Dependencies),
+		NativeModules: nonNil(file.Dependencies.NativeModules),
+		Functions:     make([]Function, 0, len(file.Functions)),
+	}
+
+	for _, frequency := range indentation.IndentDistribution {
+		result.Indentation.Distribution = append(result.Indentation.Distribution, IndentLevel{Level: frequency.Level, Count: frequency.Count})
+	}
+
+	// the results of the functions are in the order of file.Functions, and
+	// the complexity results carry the display name, with the class of
+	// methods and a placeholder for anonymous functions
+	for i, function := range file.Functions {
+		name := function.Name
+		var measured analyzer.FunctionComplexity
+		if i < len(file.Complexity.Functions) {
+			measured = file.Complexity.Functions[i]
+			name = measured.Name
+		}
+		var nesting analyzer.FunctionNesting
+		if i < len(indentation.Functions) {
+			nesting = indentation.Functions[i]
+		}
+		var volume float64
+		if i < len(file.Maintainability.Functions) {
+			volume = file.Maintainability.Functions[i].Volume
+		}
+		result.Functions = append(result.Functions, Function{
+			Name:                name,
+			Kind:                string(function.Kind),
+			StartLine:           function.StartLine,
+			EndLine:             function.EndLine,
+			Params:              function.Params,
+			Complexity:          measured.Complexity,
+			CognitiveComplexity: nesting.CognitiveComplexity,
+			NestingDepth:        nesting.NestingDepth,
+			Volume:              volume,
+		})
+	}
+	return result
+}
+
+// relativePath returns the path relative to the absolute root, with
+// forward slashes.
+func relativePath(root, path string) string {
+	if absolute, err := filepath.Abs(path); err == nil {
+		path = absolute
+	}
+	relative, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relative)
+}
+
+// uniqueSorted sorts the names and removes the duplicates.
+func uniqueSorted(names []string) []string {
+	names = nonNil(names)
+	sort.Strings(names)
+	return slices.Compact(names)
+}
+
+// nonNil returns an empty slice in place of nil, so that the lists are
+// encoded as [] rather than null.
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
@@ -0,0 +1,155 @@
+// Package results builds the typed report of an analysis from the results
+// of the analyzers. The commands and the public pkg/analysis package share
+// it, so that they write the same report.
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the version of the layout of Report, written in its
+// schema_version field. It changes whenever a field is removed, renamed or
+// changes meaning; adding a field does not change it.
+const SchemaVersion = "1"
+
+// Read decodes a report encoded as JSON. It fails on the reports of another
+// version of the schema.
+func Read(content []byte) (Report, error) {
+	var report Report
+	if err := json.Unmarshal(content, &report); err != nil {
+		return Report{}, err
+	}
+	if report.SchemaVersion != SchemaVersion {
+		return Report{}, fmt.Errorf("unsupported report schema version %q, expected %q", report.SchemaVersion, SchemaVersion)
+	}
+	return report, nil
+}
+
+// Report holds the results of an analysis.
+type Report struct {
+	SchemaVersion string `json:"schema_version"`
+	// Root is the absolute path of the analyzed directory, or of the
+	// directory of the analyzed file.
+	Root string `json:"root"`
+	// Files holds the results of every analyzed file, sorted by path.
+	Files  []File `json:"files"`
+	Totals Totals `json:"totals"`
+	// Skipped lists the files of the directory that could not be read.
+	// The results of the other files are complete.
+	Skipped []SkippedFile `json:"skipped,omitempty"`
+}
+
+// File holds the results of a file.
+type File struct {
+	// Path is relative to the root of the report, with forward slashes.
+	Path              string  `json:"path"`
+	Lines             int     `json:"lines"`
+	CommentLines      int     `json:"comment_lines"`
+	CommentPercentage float64 `json:"comment_percentage"`
+	Classes           int     `json:"classes"`
+	FunctionCount     int     `json:"function_count"`
+	// TypeScript type-only declarations
+	Interfaces          int     `json:"interfaces"`
+	Enums               int     `json:"enums"`
+	TypeAliases         int     `json:"type_aliases"`
+	PublicMethods       int     `json:"public_methods"`
+	PrivateMethods      int     `json:"private_methods"`
+	AverageFunctionSize float64 `json:"average_function_size"`
+	AverageComplexity   float64 `json:"average_complexity"`
+	MaxComplexity       int     `json:"max_complexity"`
+	// MaintainabilityIndex ranges from 0, hard to maintain, to 100.
+	MaintainabilityIndex float64     `json:"maintainability_index"`
+	Halstead             Halstead    `json:"halstead"`
+	Indentation          Indentation `json:"indentation"`
+	// Dependencies are the external modules the file imports, and
+	// NativeModules the Node.js built-in ones.
+	Dependencies  []string `json:"dependencies"`
+	NativeModules []string `json:"native_modules"`
+	// Owners are the owners of the file in the CODEOWNERS file, when the
+	// report lists them.
+	Owners []string `json:"owners,omitempty"`
+	// Functions is left out of the reports without the functions of the
+	// files.
+	Functions []Function `json:"functions,omitempty"`
+}
+
+// Halstead holds the Halstead metrics of a file or a function.
+type Halstead struct {
+	Volume     float64 `json:"volume"`
+	Difficulty float64 `json:"difficulty"`
+	Effort     float64 `json:"effort"`
+}
+
+// Function holds the results of a function, method or arrow function.
+type Function struct {
+	// Name carries the class of methods, and is <anonymous> for the
+	// functions without a name.
+	Name                string `json:"name"`
+	Kind                string `json:"kind"`
+	StartLine           int    `json:"start_line"`
+	EndLine             int    `json:"end_line"`
+	Params              int    `json:"params"`
+	Complexity          int    `json:"complexity"`
+	CognitiveComplexity int    `json:"cognitive_complexity"`
+	NestingDepth        int    `json:"nesting_depth"`
+	// Volume is the Halstead volume of the function.
+	Volume float64 `json:"volume"`
+}
+
+// Indentation holds the indentation statistics of a file, in spaces.
+type Indentation struct {
+	MaxLevel     int     `json:"max_level"`
+	AverageLevel float64 `json:"average_level"`
+	UsesSpaces   bool    `json:"uses_spaces"`
+	UsesTabs     bool    `json:"uses_tabs"`
+	Mixed        bool    `json:"mixed"`
+	// MixedLine is the first line indented with the character the file
+	// did not start with, or 0 when the indentation is consistent.
+	MixedLine              int `json:"mixed_line,omitempty"`
+	MaxNestingDepth        int `json:"max_nesting_depth"`
+	MaxCognitiveComplexity int `json:"max_cognitive_complexity"`
+	// Distribution counts the indented lines of each level, by level.
+	Distribution []IndentLevel `json:"distribution"`
+}
+
+// IndentLevel is the number of lines indented by Level spaces.
+type IndentLevel struct {
+	Level int `json:"level"`
+	Count int `json:"count"`
+}
+
+// Totals sums the results of all the files.
+type Totals struct {
+	Files             int     `json:"files"`
+	Lines             int     `json:"lines"`
+	CommentLines      int     `json:"comment_lines"`
+	CommentPercentage float64 `json:"comment_percentage"`
+	Classes           int     `json:"classes"`
+	Functions         int     `json:"functions"`
+	Interfaces        int     `json:"interfaces"`
+	Enums             int     `json:"enums"`
+	TypeAliases       int     `json:"type_aliases"`
+	PublicMethods     int     `json:"public_methods"`
+	PrivateMethods    int     `json:"private_methods"`
+	// AverageFunctionSize is the average of the files with functions.
+	AverageFunctionSize float64 `json:"average_function_size"`
+	// AverageComplexity and MaxComplexity are over all the functions.
+	AverageComplexity float64 `json:"average_complexity"`
+	MaxComplexity     int     `json:"max_complexity"`
+	// MaintainabilityIndex is the average of the files.
+	MaintainabilityIndex float64 `json:"maintainability_index"`
+	// Halstead sums the volume and effort of the files, and averages their
+	// difficulty.
+	Halstead Halstead `json:"halstead"`
+	// Dependencies and NativeModules list each module once, sorted.
+	Dependencies  []string `json:"dependencies"`
+	NativeModules []string `json:"native_modules"`
+}
+
+// SkippedFile is a file the analysis could not read.
+type SkippedFile struct {
+	// Path is relative to the root of the report, with forward slashes.
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,177 @@
+// Package sarif writes analyzer findings as a SARIF 2.1.0 log, the format
+// code-scanning platforms ingest.
+package sarif
+
+import (
+	"encoding/json"
+	"go-cli-tool/internal/analyzer"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	toolName           = "go-cli-tool"
+	toolInformationURI = "https://github.com/ruan-cardozo/go-cli-tool"
+
+	// rootBaseID names the analyzed directory, which the artifact locations
+	// are relative to.
+	rootBaseID = "SRCROOT"
+)
+
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool               Tool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]ArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []Result                    `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string                `json:"name"`
+	Version        string                `json:"version,omitempty"`
+	InformationURI string                `json:"informationUri"`
+	Rules          []ReportingDescriptor `json:"rules"`
+}
+
+// ReportingDescriptor is the definition of a rule.
+type ReportingDescriptor struct {
+	ID                   string        `json:"id"`
+	Name                 string        `json:"name"`
+	ShortDescription     Message       `json:"shortDescription"`
+	DefaultConfiguration Configuration `json:"defaultConfiguration"`
+}
+
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	RuleIndex int        `json:"ruleIndex"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// NewLog converts findings into a log with a single run. The files are
+// located relative to root when they are inside it; toolVersion is the
+// version of the CLI reporting them.
+func NewLog(findings []analyzer.Finding, root string, toolVersion string) Log {
+	rules := make([]ReportingDescriptor, 0, len(analyzer.Rules))
+	ruleIndexes := make(map[string]int, len(analyzer.Rules))
+	for i, rule := range analyzer.Rules {
+		ruleIndexes[rule.ID] = i
+		rules = append(rules, ReportingDescriptor{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			ShortDescription:     Message{Text: rule.Description},
+			DefaultConfiguration: Configuration{Level: string(rule.Level)},
+		})
+	}
+
+	run := Run{
+		Tool: Tool{Driver: Driver{
+			Name:           toolName,
+			Version:        toolVersion,
+			InformationURI: toolInformationURI,
+			Rules:          rules,
+		}},
+		Results: make([]Result, 0, len(findings)),
+	}
+
+	root, err := filepath.Abs(root)
+	if err == nil {
+		run.OriginalURIBaseIDs = map[string]ArtifactLocation{
+			rootBaseID: {URI: strings.TrimSuffix(fileURI(root), "/") + "/"},
+		}
+	}
+
+	for _, finding := range findings {
+		run.Results = append(run.Results, Result{
+			RuleID:    finding.Rule,
+			RuleIndex: ruleIndexes[finding.Rule],
+			Level:     string(finding.Level),
+			Message:   Message{Text: finding.Message},
+			Locations: []Location{{
+				PhysicalLocation: PhysicalLocation{
+					ArtifactLocation: artifactLocation(finding.File, root),
+					Region: Region{
+						StartLine:   max(finding.Line, 1),
+						StartColumn: max(finding.Column, 1),
+					},
+				},
+			}},
+		})
+	}
+
+	return Log{Version: Version, Schema: Schema, Runs: []Run{run}}
+}
+
+// Write encodes the log as indented JSON.
+func (l Log) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// artifactLocation locates a file relative to root, or by its absolute URI
+// when it lies outside of root.
+func artifactLocation(path string, root string) ArtifactLocation {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return ArtifactLocation{URI: filepath.ToSlash(path)}
+	}
+
+	relative, err := filepath.Rel(root, absolute)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return ArtifactLocation{URI: fileURI(absolute)}
+	}
+
+	uri := url.URL{Path: filepath.ToSlash(relative)}
+	return ArtifactLocation{URI: uri.EscapedPath(), URIBaseID: rootBaseID}
+}
+
+func fileURI(absolute string) string {
+	path := filepath.ToSlash(absolute)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	uri := url.URL{Scheme: "file", Path: path}
+	return uri.String()
+}
@@ -0,0 +1,56 @@
+package sarif_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/sarif"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLog(t *testing.T) {
+	root := t.TempDir()
+	findings := []analyzer.Finding{
+		{Rule: "banned-dependency", Level: analyzer.LevelError, File: filepath.Join(root, "src", "my app.js"), Line: 3, Column: 8, Message: "lodash is a banned dependency (lodash)"},
+		{Rule: "mixed-indentation", Level: analyzer.LevelWarning, File: "/elsewhere/b.js", Message: "indentation mixes tabs and spaces"},
+	}
+
+	log := sarif.NewLog(findings, root, "1.2.3")
+
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "1.2.3", run.Tool.Driver.Version)
+	assert.Len(t, run.Tool.Driver.Rules, len(analyzer.Rules))
+	assert.Equal(t, "error", run.Tool.Driver.Rules[3].DefaultConfiguration.Level)
+
+	first := run.Results[0]
+	assert.Equal(t, "banned-dependency", first.RuleID)
+	assert.Equal(t, 3, first.RuleIndex)
+	assert.Equal(t, "error", first.Level)
+	assert.Equal(t, sarif.ArtifactLocation{URI: "src/my%20app.js", URIBaseID: "SRCROOT"}, first.Locations[0].PhysicalLocation.ArtifactLocation)
+	assert.Equal(t, sarif.Region{StartLine: 3, StartColumn: 8}, first.Locations[0].PhysicalLocation.Region)
+
+	// files outside of the root keep their absolute location, and a
+	// finding without a position points at the start of the file
+	second := run.Results[1]
+	assert.Equal(t, sarif.ArtifactLocation{URI: "file:///elsewhere/b.js"}, second.Locations[0].PhysicalLocation.ArtifactLocation)
+	assert.Equal(t, sarif.Region{StartLine: 1, StartColumn: 1}, second.Locations[0].PhysicalLocation.Region)
+}
+
+func TestLogWrite(t *testing.T) {
+	var output bytes.Buffer
+	assert.NoError(t, sarif.NewLog(nil, t.TempDir(), "dev").Write(&output))
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, "2.1.0", decoded["version"])
+	assert.Equal(t, sarif.Schema, decoded["$schema"])
+
+	runs := decoded["runs"].([]interface{})
+	assert.Equal(t, []interface{}{}, runs[0].(map[string]interface{})["results"])
+}
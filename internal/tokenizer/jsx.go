@@ -0,0 +1,146 @@
+package tokenizer
+
+import "strings"
+
+// jsxAllowed decides whether a `<` at the current position opens a JSX
+// element: it must appear where an expression may start and be followed
+// by a tag name or by `>` for a fragment.
+func (l *lexer) jsxAllowed() bool {
+	if !l.regexAllowed() {
+		return false
+	}
+	if l.peek(1) == '>' {
+		return true
+	}
+	if !l.isIdentifierStart(l.pos + 1) {
+		return false
+	}
+
+	// `<T,>(x) => x` and `<T extends U>(x) => x` are generic arrow
+	// functions in .tsx files, not elements
+	k := l.pos + 1
+	for k < len(l.src) && (isASCIILetter(l.src[k]) || isDigit(l.src[k]) || l.src[k] == '_' || l.src[k] == '$') {
+		k++
+	}
+	rest := strings.TrimLeft(l.src[k:], " \t")
+	return !strings.HasPrefix(rest, ",") && !strings.HasPrefix(rest, "extends ")
+}
+
+// scanJSXElement scans a JSX element or fragment starting at `<`, nested
+// elements included. Markup is emitted as JSXText tokens, one per line, so
+// braces and quotes in text children are never mistaken for code, while
+// the {expressions} embedded in it are tokenized as regular code.
+func (l *lexer) scanJSXElement() {
+	depth := 0
+	inTag, closing := true, false
+	start, line, column := l.pos, l.line, l.column()
+	l.pos++
+
+	flush := func() {
+		text := l.src[start:l.pos]
+		trimmed := strings.TrimLeft(text, " \t\r")
+		offset := start + len(text) - len(trimmed)
+		trimmed = strings.TrimRight(trimmed, " \t\r\n")
+		if trimmed != "" {
+			l.tokens = append(l.tokens, Token{JSXText, trimmed, line, l.line, column + offset - start, offset})
+		}
+	}
+	restart := func() {
+		start, line, column = l.pos, l.line, l.column()
+	}
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		switch {
+		case c == '\n':
+			flush()
+			l.advance()
+			restart()
+			continue
+		case c == '{':
+			flush()
+			l.scanJSXExpression()
+			restart()
+			continue
+		}
+
+		if !inTag {
+			if c == '<' {
+				inTag = true
+				closing = l.peek(1) == '/'
+			}
+			l.pos++
+			continue
+		}
+
+		switch {
+		case c == '"' || c == '\'':
+			l.pos++
+			for l.pos < len(l.src) && l.src[l.pos] != c {
+				l.advance()
+			}
+			l.pos++
+			continue
+		case c == '/' && l.peek(1) == '>' && !closing:
+			l.pos += 2
+			inTag = false
+		case c == '>':
+			l.pos++
+			inTag = false
+			if closing {
+				depth--
+			} else {
+				depth++
+			}
+		default:
+			l.pos++
+			continue
+		}
+
+		if depth == 0 {
+			break
+		}
+	}
+
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+	flush()
+}
+
+// scanJSXExpression tokenizes a `{...}` container embedded in JSX markup.
+// A container holding only comments, as in {/* note */}, yields just the
+// comment tokens so that the line still counts as a comment line.
+func (l *lexer) scanJSXExpression() {
+	open := len(l.tokens)
+	start, line, column := l.pos, l.line, l.column()
+	l.pos++
+	l.emit(Punctuator, start, line, column)
+
+	l.braces = append(l.braces, false)
+	depth := len(l.braces)
+
+	for {
+		l.skipWhitespace()
+		if l.pos >= len(l.src) {
+			return
+		}
+		if l.src[l.pos] == '}' && len(l.braces) == depth {
+			break
+		}
+		l.next()
+	}
+
+	l.braces = l.braces[:depth-1]
+	start, line, column = l.pos, l.line, l.column()
+	l.pos++
+
+	for _, token := range l.tokens[open+1:] {
+		if !token.IsComment() {
+			l.emit(Punctuator, start, line, column)
+			return
+		}
+	}
+	l.tokens = append(l.tokens[:open], l.tokens[open+1:]...)
+}
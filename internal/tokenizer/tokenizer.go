@@ -0,0 +1,468 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind identifies the lexical category of a token.
+type Kind int
+
+const (
+	Identifier Kind = iota
+	Keyword
+	Punctuator
+	Number
+	String
+	Template
+	RegExp
+	LineComment
+	BlockComment
+	// JSXText is a line of JSX markup: tags, attributes and text children.
+	JSXText
+)
+
+var kindNames = map[Kind]string{
+	Identifier:   "Identifier",
+	Keyword:      "Keyword",
+	Punctuator:   "Punctuator",
+	Number:       "Number",
+	String:       "String",
+	Template:     "Template",
+	RegExp:       "RegExp",
+	LineComment:  "LineComment",
+	BlockComment: "BlockComment",
+	JSXText:      "JSXText",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// Token is a single lexical element of a JavaScript source file.
+// Template literals are split at their substitutions, so `a${b}c` yields
+// the template pieces "`a${" and "}c`" with the tokens of b in between.
+type Token struct {
+	Kind    Kind
+	Value   string
+	Line    int // 1-based line where the token starts
+	EndLine int // line where the token ends, greater than Line for multi-line tokens
+	Column  int // 1-based byte column where the token starts
+	Offset  int // byte offset of the token in the source
+}
+
+// IsComment reports whether the token is a line or block comment.
+func (t Token) IsComment() bool {
+	return t.Kind == LineComment || t.Kind == BlockComment
+}
+
+// Is reports whether the token has the given kind and value.
+func (t Token) Is(kind Kind, value string) bool {
+	return t.Kind == kind && t.Value == value
+}
+
+// IsPunctuator reports whether the token is the given punctuator.
+func (t Token) IsPunctuator(value string) bool {
+	return t.Is(Punctuator, value)
+}
+
+var keywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true,
+	"do": true, "else": true, "export": true, "extends": true, "false": true,
+	"finally": true, "for": true, "function": true, "if": true, "import": true,
+	"in": true, "instanceof": true, "new": true, "null": true, "return": true,
+	"super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true,
+	"with": true,
+}
+
+// keywordsBeforeExpression are the keywords after which a `/` starts a
+// regular expression instead of a division.
+var keywordsBeforeExpression = map[string]bool{
+	"case": true, "delete": true, "do": true, "else": true, "in": true,
+	"instanceof": true, "new": true, "return": true, "throw": true,
+	"typeof": true, "void": true, "await": true, "yield": true, "of": true,
+}
+
+// punctuators is ordered so that longer operators are matched first.
+var punctuators = []string{
+	">>>=",
+	"...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<", ">>", "**",
+	"{", "}", "(", ")", "[", "]", ";", ",", "<", ">", "+", "-", "*", "/",
+	"%", "&", "|", "^", "!", "~", "?", ":", "=", ".", "@", "#",
+}
+
+// IsKeyword reports whether word is a reserved JavaScript keyword.
+func IsKeyword(word string) bool {
+	return keywords[word]
+}
+
+// Options selects the syntax extensions recognized by the tokenizer.
+type Options struct {
+	// JSX enables JSX markup. It must be off for TypeScript files without
+	// JSX, where `<Type>value` is a type assertion.
+	JSX bool
+}
+
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	lineAt int // offset where the current line starts
+	tokens []Token
+	// braces tracks open `{` and `${`; true marks a template substitution
+	braces []bool
+	jsx    bool
+}
+
+// Tokenize splits JavaScript source code, JSX included, into tokens. It
+// never fails: unterminated strings, comments and templates simply run to
+// the end of the input, so analyzers can still work on partially valid
+// files.
+func Tokenize(source string) []Token {
+	return TokenizeWith(source, Options{JSX: true})
+}
+
+// TokenizeWith splits source code into tokens using the given options.
+func TokenizeWith(source string, options Options) []Token {
+	l := &lexer{src: source, line: 1, jsx: options.JSX}
+
+	if strings.HasPrefix(source, "#!") {
+		l.scanLineComment()
+	}
+
+	for {
+		l.skipWhitespace()
+		if l.pos >= len(l.src) {
+			break
+		}
+		l.next()
+	}
+
+	return l.tokens
+}
+
+func (l *lexer) next() {
+	start, line, column := l.pos, l.line, l.column()
+	c := l.src[l.pos]
+
+	switch {
+	case c == '/' && l.peek(1) == '/':
+		l.scanLineComment()
+		return
+	case c == '/' && l.peek(1) == '*':
+		l.scanBlockComment()
+		return
+	case c == '\'' || c == '"':
+		l.scanString(c)
+		l.emit(String, start, line, column)
+		return
+	case c == '`':
+		l.pos++
+		l.scanTemplate(start, line, column)
+		return
+	case c == '}' && len(l.braces) > 0 && l.braces[len(l.braces)-1]:
+		l.braces = l.braces[:len(l.braces)-1]
+		l.pos++
+		l.scanTemplate(start, line, column)
+		return
+	case isDigit(c) || (c == '.' && isDigit(l.peek(1))):
+		l.scanNumber(start)
+		l.emit(Number, start, line, column)
+		return
+	case c == '/' && l.regexAllowed():
+		l.scanRegExp()
+		l.emit(RegExp, start, line, column)
+		return
+	case c == '<' && l.jsx && l.jsxAllowed():
+		l.scanJSXElement()
+		return
+	case c == '#' && l.isIdentifierStart(l.pos+1):
+		l.pos++
+		l.scanIdentifier()
+		l.emit(Identifier, start, line, column)
+		return
+	case l.isIdentifierStart(l.pos):
+		l.scanIdentifier()
+		if l.pos == start {
+			// never stall on a character the identifier does not take
+			break
+		}
+		kind := Identifier
+		if keywords[l.src[start:l.pos]] {
+			kind = Keyword
+		}
+		l.emit(kind, start, line, column)
+		return
+	}
+
+	for _, p := range punctuators {
+		if strings.HasPrefix(l.src[l.pos:], p) {
+			// `a?.5:b` is a conditional, not optional chaining
+			if p == "?." && isDigit(l.peek(2)) {
+				continue
+			}
+			l.pos += len(p)
+			switch p {
+			case "{":
+				l.braces = append(l.braces, false)
+			case "}":
+				if len(l.braces) > 0 {
+					l.braces = l.braces[:len(l.braces)-1]
+				}
+			}
+			l.emit(Punctuator, start, line, column)
+			return
+		}
+	}
+
+	// Unknown character: keep it as a punctuator so nothing is lost.
+	_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+	l.emit(Punctuator, start, line, column)
+}
+
+func (l *lexer) emit(kind Kind, start, line, column int) {
+	l.tokens = append(l.tokens, Token{
+		Kind:    kind,
+		Value:   l.src[start:l.pos],
+		Line:    line,
+		EndLine: l.line,
+		Column:  column,
+		Offset:  start,
+	})
+}
+
+func (l *lexer) column() int {
+	return l.pos - l.lineAt + 1
+}
+
+func (l *lexer) peek(n int) byte {
+	if l.pos+n < len(l.src) {
+		return l.src[l.pos+n]
+	}
+	return 0
+}
+
+// advance moves past the current byte, keeping the line counter in sync.
+func (l *lexer) advance() {
+	if l.src[l.pos] == '\n' {
+		l.line++
+		l.lineAt = l.pos + 1
+	}
+	l.pos++
+}
+
+func (l *lexer) skipWhitespace() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f' {
+			l.advance()
+			continue
+		}
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if unicode.IsSpace(r) || r == '\uFEFF' {
+				l.pos += size
+				continue
+			}
+		}
+		return
+	}
+}
+
+func (l *lexer) scanLineComment() {
+	start, line, column := l.pos, l.line, l.column()
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.pos++
+	}
+	value := strings.TrimRight(l.src[start:l.pos], "\r")
+	l.tokens = append(l.tokens, Token{LineComment, value, line, line, column, start})
+}
+
+func (l *lexer) scanBlockComment() {
+	start, line, column := l.pos, l.line, l.column()
+	l.pos += 2
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '*' && l.peek(1) == '/' {
+			l.pos += 2
+			break
+		}
+		l.advance()
+	}
+	l.emit(BlockComment, start, line, column)
+}
+
+func (l *lexer) scanString(quote byte) {
+	l.pos++
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == quote:
+			l.pos++
+			return
+		case c == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			l.advance()
+		case c == '\n':
+			// unterminated string literal
+			return
+		default:
+			l.pos++
+		}
+	}
+}
+
+// scanTemplate scans template characters up to the closing backtick or the
+// next substitution. The opening "`" or "}" has already been consumed.
+func (l *lexer) scanTemplate(start, line, column int) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '`':
+			l.pos++
+			l.emit(Template, start, line, column)
+			return
+		case c == '$' && l.peek(1) == '{':
+			l.pos += 2
+			l.braces = append(l.braces, true)
+			l.emit(Template, start, line, column)
+			return
+		case c == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			l.advance()
+		default:
+			l.advance()
+		}
+	}
+	l.emit(Template, start, line, column)
+}
+
+func (l *lexer) scanNumber(start int) {
+	hex := strings.HasPrefix(strings.ToLower(l.src[start:]), "0x")
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if isDigit(c) || isASCIILetter(c) || c == '_' || c == '.' {
+			l.pos++
+			continue
+		}
+		// exponent sign, as in 1e-9
+		if (c == '+' || c == '-') && !hex && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E') {
+			l.pos++
+			continue
+		}
+		return
+	}
+}
+
+func (l *lexer) scanRegExp() {
+	l.pos++
+	inClass := false
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\\' && l.pos+1 < len(l.src) && l.src[l.pos+1] != '\n':
+			l.pos += 2
+			continue
+		case c == '\n':
+			// not a valid regular expression; stop at the line end
+			return
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			l.pos++
+			for l.pos < len(l.src) && (isASCIILetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+				l.pos++
+			}
+			return
+		}
+		l.pos++
+	}
+}
+
+// regexAllowed decides whether a `/` at the current position starts a
+// regular expression literal, based on the previous significant token.
+func (l *lexer) regexAllowed() bool {
+	prev, ok := l.previous()
+	if !ok {
+		return true
+	}
+	switch prev.Kind {
+	case Number, String, RegExp:
+		return false
+	case Template:
+		// a template piece ending in "${" opens an expression
+		return strings.HasSuffix(prev.Value, "${")
+	case Keyword:
+		return prev.Value != "this" && prev.Value != "super" && prev.Value != "null" &&
+			prev.Value != "true" && prev.Value != "false"
+	case Identifier:
+		return keywordsBeforeExpression[prev.Value]
+	case Punctuator:
+		return prev.Value != ")" && prev.Value != "]" && prev.Value != "}" &&
+			prev.Value != "++" && prev.Value != "--"
+	}
+	return true
+}
+
+func (l *lexer) previous() (Token, bool) {
+	for i := len(l.tokens) - 1; i >= 0; i-- {
+		if !l.tokens[i].IsComment() {
+			return l.tokens[i], true
+		}
+	}
+	return Token{}, false
+}
+
+func (l *lexer) isIdentifierStart(pos int) bool {
+	if pos >= len(l.src) {
+		return false
+	}
+	c := l.src[pos]
+	if c < utf8.RuneSelf {
+		// a backslash only starts an identifier as a unicode escape
+		return isASCIILetter(c) || c == '_' || c == '$' || (c == '\\' && pos+1 < len(l.src) && l.src[pos+1] == 'u')
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[pos:])
+	return unicode.IsLetter(r)
+}
+
+func (l *lexer) scanIdentifier() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c < utf8.RuneSelf {
+			if isASCIILetter(c) || isDigit(c) || c == '_' || c == '$' {
+				l.pos++
+				continue
+			}
+			// unicode escape such as \u0061
+			if c == '\\' && l.peek(1) == 'u' {
+				l.pos += 2
+				continue
+			}
+			return
+		}
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r) && r != '\u200C' && r != '\u200D' {
+			return
+		}
+		l.pos += size
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
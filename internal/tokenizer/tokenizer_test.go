@@ -0,0 +1,160 @@
+package tokenizer_test
+
+import (
+	"go-cli-tool/internal/tokenizer"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func kindsOf(tokens []tokenizer.Token) []tokenizer.Kind {
+	kinds := make([]tokenizer.Kind, 0, len(tokens))
+	for _, token := range tokens {
+		kinds = append(kinds, token.Kind)
+	}
+	return kinds
+}
+
+func TestTokenizeComments(t *testing.T) {
+	tokens := tokenizer.Tokenize("// line\nconst url = 'http://example.com'; /* block\ncomment */")
+
+	assert.Equal(t, []tokenizer.Kind{
+		tokenizer.LineComment,
+		tokenizer.Keyword,
+		tokenizer.Identifier,
+		tokenizer.Punctuator,
+		tokenizer.String,
+		tokenizer.Punctuator,
+		tokenizer.BlockComment,
+	}, kindsOf(tokens))
+
+	assert.Equal(t, "'http://example.com'", tokens[4].Value)
+	assert.Equal(t, 2, tokens[6].Line)
+	assert.Equal(t, 3, tokens[6].EndLine)
+}
+
+func TestTokenizeRegExpAndDivision(t *testing.T) {
+	tokens := tokenizer.Tokenize("const re = /\\/\\/[/]*/g; const half = total / 2 / 1;")
+
+	assert.Equal(t, tokenizer.RegExp, tokens[3].Kind)
+	assert.Equal(t, "/\\/\\/[/]*/g", tokens[3].Value)
+
+	divisions := 0
+	for _, token := range tokens {
+		if token.IsPunctuator("/") {
+			divisions++
+		}
+		assert.NotEqual(t, tokenizer.LineComment, token.Kind, "regular expression must not start a comment")
+	}
+	assert.Equal(t, 2, divisions)
+}
+
+func TestTokenizeTemplateLiteral(t *testing.T) {
+	tokens := tokenizer.Tokenize("const s = `a ${ fn({ x: `//${y}` }) } b\n// not a comment`;")
+
+	for _, token := range tokens {
+		assert.False(t, token.IsComment(), "template content must not be treated as a comment")
+	}
+
+	var templates []string
+	for _, token := range tokens {
+		if token.Kind == tokenizer.Template {
+			templates = append(templates, token.Value)
+		}
+	}
+	assert.Equal(t, []string{"`a ${", "`//${", "}`", "} b\n// not a comment`"}, templates)
+	assert.Equal(t, tokenizer.Punctuator, tokens[len(tokens)-1].Kind)
+}
+
+func TestTokenizeLineAndColumn(t *testing.T) {
+	tokens := tokenizer.Tokenize("function a() {\n\treturn 1;\n}")
+
+	last := tokens[len(tokens)-1]
+	assert.Equal(t, "}", last.Value)
+	assert.Equal(t, 3, last.Line)
+	assert.Equal(t, 1, last.Column)
+
+	assert.Equal(t, "return", tokens[5].Value)
+	assert.Equal(t, tokenizer.Keyword, tokens[5].Kind)
+	assert.Equal(t, 2, tokens[5].Line)
+	assert.Equal(t, 2, tokens[5].Column)
+}
+
+func TestTokenizeUnterminatedInput(t *testing.T) {
+	tokens := tokenizer.Tokenize("const s = 'open\nconst t = `never closed")
+
+	assert.Equal(t, tokenizer.String, tokens[3].Kind)
+	assert.Equal(t, tokenizer.Template, tokens[len(tokens)-1].Kind)
+}
+
+func TestTokenizeStrayBackslash(t *testing.T) {
+	inputs := map[string]string{
+		"if (x) /foo\\/bar/.test(s)": "\\",
+		"a \\x":                      "\\",
+		"a\n\\\nb":                   "\\",
+	}
+	for source, backslash := range inputs {
+		tokens := tokenizer.Tokenize(source)
+
+		found := false
+		for _, token := range tokens {
+			assert.NotEmpty(t, token.Value, "Expected every token of %q to consume input", source)
+			if token.Value == backslash {
+				found = true
+				assert.Equal(t, tokenizer.Punctuator, token.Kind)
+			}
+		}
+		assert.True(t, found, "Expected the backslash of %q to be kept", source)
+	}
+
+	tokens := tokenizer.Tokenize("const \\u0061b = 1;")
+	assert.Equal(t, tokenizer.Identifier, tokens[1].Kind)
+	assert.Equal(t, "\\u0061b", tokens[1].Value)
+}
+
+func TestTokenizeJSX(t *testing.T) {
+	source := "const view = (\n" +
+		"\t<div onClick={() => go()}>\n" +
+		"\t\t{/* note */}\n" +
+		"\t\tDon't { \"{\" } // text\n" +
+		"\t\t<></>\n" +
+		"\t</div>\n" +
+		");"
+
+	tokens := tokenizer.Tokenize(source)
+
+	var markup []string
+	comments := 0
+	for _, token := range tokens {
+		switch {
+		case token.Kind == tokenizer.JSXText:
+			markup = append(markup, token.Value)
+		case token.IsComment():
+			comments++
+			assert.Equal(t, 3, token.Line)
+		}
+	}
+	assert.Equal(t, []string{"<div onClick=", ">", "Don't", "// text", "<></>", "</div>"}, markup)
+	assert.Equal(t, 1, comments, "only the JSX comment is a comment")
+	assert.Equal(t, ";", tokens[len(tokens)-1].Value)
+
+	braces := 0
+	for _, token := range tokens {
+		if token.IsPunctuator("{") {
+			braces++
+		}
+	}
+	assert.Equal(t, 2, braces, "a container holding only a comment yields no braces")
+}
+
+func TestTokenizeWithoutJSX(t *testing.T) {
+	tokens := tokenizer.TokenizeWith("const n = <number>value;", tokenizer.Options{})
+
+	for _, token := range tokens {
+		assert.NotEqual(t, tokenizer.JSXText, token.Kind, "type assertions are not markup")
+	}
+
+	generic := tokenizer.Tokenize("const id = <T,>(x: T) => x;")
+	assert.Equal(t, "<", generic[3].Value)
+	assert.Equal(t, tokenizer.Punctuator, generic[3].Kind)
+}
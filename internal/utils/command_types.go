@@ -6,4 +6,16 @@ const (
     COUNT_LINES              CommandType = "Count Lines"
     COUNT_CLASS_AND_FUNCTIONS CommandType = "Count Class And Functions"
     COUNT_COMMENTS           CommandType = "Count Comments"
-)
\ No newline at end of file
+    COMMENT_PERCENTAGE       CommandType = "Comment Percentage"
+    COUNT_METHODS            CommandType = "Count Methods"
+    AVERAGE_FUNCTION_SIZE    CommandType = "Average Function Size"
+    COMPLEXITY               CommandType = "Cyclomatic Complexity"
+    INDENTATION              CommandType = "Indentation"
+    DEPENDENCIES             CommandType = "Dependencies"
+    QUALITY_GATES            CommandType = "Quality Gates"
+    ANALYSIS                 CommandType = "Analysis"
+    HISTORY                  CommandType = "History"
+    DIFF                     CommandType = "Analysis Diff"
+    HOTSPOTS                 CommandType = "Hotspots"
+    OWNERSHIP                CommandType = "Ownership"
+)
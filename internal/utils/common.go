@@ -3,5 +3,14 @@ package utils
 var FilePath string
 var DirectoryPath string
 var OutputFilePath string
+var OutputFormat string
 var SummaryOnly bool
-var Detailed bool
\ No newline at end of file
+var Detailed bool
+var ChangedSince string
+var Staged bool
+var Jobs int
+var Watch bool
+var Include []string
+var Exclude []string
+var Extensions []string
+var TabWidth int
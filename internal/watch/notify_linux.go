@@ -0,0 +1,119 @@
+//go:build linux
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inotify watches every directory of a tree, as inotify does not watch
+// subdirectories.
+type inotify struct {
+	root string
+	fd   int
+	// dirs are the watched directories by watch descriptor.
+	dirs map[int32]string
+}
+
+// notify sends the paths changed in the tree rooted at root to events with
+// inotify, until done is closed.
+func notify(root string, events chan<- string, done <-chan struct{}) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	// a non-blocking descriptor is read through the runtime poller, so that
+	// closing the file stops the read
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	watcher := &inotify{root: root, fd: fd, dirs: make(map[int32]string)}
+	if err := watcher.addTree(root, nil); err != nil {
+		file.Close()
+		return err
+	}
+
+	go func() {
+		<-done
+		file.Close()
+	}()
+	go watcher.read(file, func(path string) bool { return send(events, path, done) })
+	return nil
+}
+
+// addTree watches the directory at dir and the directories below it. The
+// files found are passed to found, as a new directory may already hold
+// files when it is watched.
+func (w *inotify) addTree(dir string, found func(path string) bool) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// the entry was removed while walking
+			return nil
+		}
+		if skipped(w.root, path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			if found != nil && !found(path) {
+				return filepath.SkipAll
+			}
+			return nil
+		}
+
+		wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			return err
+		}
+		w.dirs[int32(wd)] = path
+		return nil
+	})
+}
+
+// read decodes the events of the inotify file until it is closed.
+func (w *inotify) read(file *os.File, changed func(path string) bool) {
+	buffer := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := file.Read(buffer)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			offset = nameStart + int(event.Len)
+			name := strings.TrimRight(string(buffer[nameStart:offset]), "\x00")
+
+			if event.Mask&syscall.IN_IGNORED != 0 {
+				delete(w.dirs, event.Wd)
+				continue
+			}
+			dir, ok := w.dirs[event.Wd]
+			if !ok || name == "" {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if skipped(w.root, path) {
+				continue
+			}
+
+			if event.Mask&syscall.IN_ISDIR != 0 && event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+				// the watch of a new directory can fail once the limit of
+				// watches is reached, its files are then not followed
+				w.addTree(path, changed)
+			}
+			if !changed(path) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package watch
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// notify is only implemented with inotify, the other platforms are polled.
+func notify(root string, events chan<- string, done <-chan struct{}) error {
+	return fmt.Errorf("file system notifications are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,209 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/report"
+	"go-cli-tool/internal/utils"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// FlagUsage describes the --watch flag of the commands.
+const FlagUsage = "Keep watching the directory, analyzing again the files that change and redrawing the summary with the changes since the start (inotify on Linux, polling elsewhere)"
+
+// DefaultInterval is the period of the polling.
+const DefaultInterval = time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// Metric is a value of the summary of a watched directory.
+type Metric struct {
+	Name  string
+	Value float64
+}
+
+// Session keeps the results of the analysis of every file of a directory,
+// analyzes again the files that change and redraws the summary of the
+// results, with the change of every metric since the session started.
+type Session[T any] struct {
+	// Title names the analysis in the summary.
+	Title string
+	// Dir is the watched directory.
+	Dir string
+	// Analyze analyzes a file.
+	Analyze func(path string) (T, error)
+	// Summarize computes the metrics of the summary from the results of
+	// all the files, keyed by path. The number of files is shown before
+	// them.
+	Summarize func(results map[string]T) []Metric
+	// Interval is the period of the polling, DefaultInterval when zero.
+	Interval time.Duration
+
+	results map[string]T
+	// skipped are the files that could not be analyzed, with the reason.
+	skipped map[string]error
+	start   []Metric
+}
+
+// Watch analyzes the directory and watches it until ctx is done or the
+// process is interrupted.
+func (s *Session[T]) Watch(ctx context.Context, out io.Writer) error {
+	if s.Dir == "" {
+		return errors.New("the watch mode needs a directory (-d)")
+	}
+	dir, err := utils.ExpandPath(s.Dir)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	watcher := New(dir, interval)
+	defer watcher.Close()
+
+	return s.Run(ctx, out, dir, watcher.Mode, watcher.Changes())
+}
+
+// Run analyzes the directory, then analyzes again the files of every batch
+// of changed paths received, until ctx is done or changes is closed. Mode
+// describes how the changes are detected.
+func (s *Session[T]) Run(ctx context.Context, out io.Writer, dir string, mode string, changes <-chan []string) error {
+	s.results = make(map[string]T)
+	s.skipped = make(map[string]error)
+	if _, err := s.update(dir, nil); err != nil {
+		return err
+	}
+	s.start = s.summary()
+	s.draw(out, dir, mode, fmt.Sprintf("Started at %s", time.Now().Format("15:04:05")))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case paths, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			analyzed, err := s.update(dir, paths)
+			if err != nil {
+				return err
+			}
+			s.draw(out, dir, mode, fmt.Sprintf("Last change at %s: %d paths changed, %d files analyzed again", time.Now().Format("15:04:05"), len(paths), analyzed))
+		}
+	}
+}
+
+// update lists the files of the directory again and analyzes the new files
+// and the changed ones, forgetting the removed files. It returns the number
+// of files analyzed.
+func (s *Session[T]) update(dir string, changedPaths []string) (int, error) {
+	files, err := analyzer.SourceFiles(dir)
+	unreadable, err := analyzer.Skipped(err)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := make(map[string]bool, len(changedPaths))
+	for _, path := range changedPaths {
+		changed[path] = true
+	}
+
+	results := make(map[string]T, len(files))
+	skipped := make(map[string]error)
+	analyzed := 0
+	for _, path := range files {
+		result, known := s.results[path]
+		if known && !changed[path] {
+			results[path] = result
+			continue
+		}
+		if err, failed := s.skipped[path]; failed && !changed[path] {
+			skipped[path] = err
+			continue
+		}
+
+		analyzed++
+		result, err := s.Analyze(path)
+		if err != nil {
+			skipped[path] = err
+			continue
+		}
+		results[path] = result
+	}
+
+	for _, file := range unreadable {
+		skipped[file.Path] = errors.New(file.Reason)
+	}
+
+	s.results = results
+	s.skipped = skipped
+	return analyzed, nil
+}
+
+// draw clears the terminal and prints the summary.
+func (s *Session[T]) draw(out io.Writer, dir string, mode string, status string) {
+	fmt.Fprint(out, clearScreen)
+	fmt.Fprintf(out, "%s=== %s: watching %s (%s) ===%s\n\n", utils.BLUE, s.Title, dir, mode, utils.RESET_COLOR)
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Metric\tValue\tSince start")
+	for _, metric := range s.summary() {
+		change := round(metric.Value) - round(s.startValue(metric.Name))
+		fmt.Fprintf(table, "%s\t%s\t%s\n", metric.Name, report.FormatNumber(round(metric.Value)), report.FormatChange(round(change)))
+	}
+	table.Flush()
+
+	if len(s.skipped) > 0 {
+		paths := make([]string, 0, len(s.skipped))
+		for path := range s.skipped {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		fmt.Fprintf(out, "\n%sSkipped files:%s\n", utils.RED, utils.RESET_COLOR)
+		for _, path := range paths {
+			fmt.Fprintf(out, "  %s: %s\n", path, s.skipped[path])
+		}
+	}
+
+	fmt.Fprintf(out, "\n%s\nPress Ctrl+C to stop.\n", status)
+}
+
+// summary returns the metrics of the current results.
+func (s *Session[T]) summary() []Metric {
+	return append([]Metric{{Name: "Files", Value: float64(len(s.results))}}, s.Summarize(s.results)...)
+}
+
+// round rounds a metric to the precision it is displayed with, so that
+// the floating-point noise of averages is not shown as a change.
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// startValue returns the value of the metric when the session started.
+func (s *Session[T]) startValue(name string) float64 {
+	for _, metric := range s.start {
+		if metric.Name == name {
+			return metric.Value
+		}
+	}
+	return 0
+}
@@ -0,0 +1,181 @@
+// Package watch reports the files changing in a directory tree, with the
+// file system notifications of the platform when it has them and by
+// polling otherwise, and redraws the summary of the analysis of the tree as
+// they change.
+package watch
+
+import (
+	"go-cli-tool/internal/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// settleTime is how long the watcher waits for more changes after a change
+// before reporting them together, as editors and tools often write several
+// files, or the same file several times, in a row.
+const settleTime = 100 * time.Millisecond
+
+// The modes of a Watcher.
+const (
+	Notify  = "inotify"
+	Polling = "polling"
+)
+
+// Watcher reports the paths changed in a directory tree, in batches. The
+// directories excluded by the configuration and the state directory are
+// not watched.
+type Watcher struct {
+	// Mode is how the changes are detected, Notify or Polling.
+	Mode    string
+	changes chan []string
+	done    chan struct{}
+	close   sync.Once
+}
+
+// New watches the directory tree rooted at root with the file system
+// notifications of the platform, or by polling it at the given interval
+// when they are not available.
+func New(root string, interval time.Duration) *Watcher {
+	w := newWatcher(Notify)
+	events := make(chan string)
+	if err := notify(root, events, w.done); err != nil {
+		return Poll(root, interval)
+	}
+
+	go w.batch(events)
+	return w
+}
+
+// Poll watches the directory tree rooted at root by comparing the size and
+// the modification time of its files at the given interval.
+func Poll(root string, interval time.Duration) *Watcher {
+	w := newWatcher(Polling)
+	events := make(chan string)
+	previous := snapshot(root)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+
+			current := snapshot(root)
+			for path, state := range current {
+				if previous[path] != state && !send(events, path, w.done) {
+					return
+				}
+			}
+			for path := range previous {
+				if _, ok := current[path]; !ok && !send(events, path, w.done) {
+					return
+				}
+			}
+			previous = current
+		}
+	}()
+
+	go w.batch(events)
+	return w
+}
+
+func newWatcher(mode string) *Watcher {
+	return &Watcher{Mode: mode, changes: make(chan []string), done: make(chan struct{})}
+}
+
+// Changes returns the channel receiving the changed paths, sorted, once
+// they settled. It is closed when the watcher is.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Close stops watching.
+func (w *Watcher) Close() {
+	w.close.Do(func() { close(w.done) })
+}
+
+// batch gathers the changed paths until they settle.
+func (w *Watcher) batch(events <-chan string) {
+	defer close(w.changes)
+
+	pending := make(map[string]bool)
+	var settled <-chan time.Time
+	for {
+		select {
+		case <-w.done:
+			return
+		case path := <-events:
+			pending[path] = true
+			if settled == nil {
+				settled = time.After(settleTime)
+			}
+		case <-settled:
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			settled = nil
+
+			select {
+			case w.changes <- paths:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// send hands a changed path to the watcher, and reports whether it is
+// still watching.
+func send(events chan<- string, path string, done <-chan struct{}) bool {
+	select {
+	case events <- path:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// skipped reports whether the entry at path is left out of the watch.
+func skipped(root, path string) bool {
+	relativePath, err := filepath.Rel(root, path)
+	if err != nil || relativePath == "." {
+		return false
+	}
+	return filepath.Base(path) == config.StateDirName || config.Active.Excludes(filepath.ToSlash(relativePath))
+}
+
+// fileState is what the polling compares to detect a change.
+type fileState struct {
+	size    int64
+	modTime int64
+}
+
+// snapshot returns the state of the files of the tree rooted at root.
+func snapshot(root string) map[string]fileState {
+	files := make(map[string]fileState)
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || skipped(root, path) {
+			if err == nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files[path] = fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+		}
+		return nil
+	})
+	return files
+}
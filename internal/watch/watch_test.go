@@ -0,0 +1,133 @@
+package watch_test
+
+import (
+	"bytes"
+	"context"
+	"go-cli-tool/internal/watch"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nextChange waits for a batch of changes containing path.
+func nextChange(t *testing.T, w *watch.Watcher, path string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case paths := <-w.Changes():
+			for _, changed := range paths {
+				if changed == path {
+					return
+				}
+			}
+		case <-timeout:
+			t.Fatalf("no change reported for %s", path)
+		}
+	}
+}
+
+func testWatcher(t *testing.T, newWatcher func(root string) *watch.Watcher) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.js"), []byte("const a = 1;\n"), 0644))
+
+	w := newWatcher(dir)
+	defer w.Close()
+
+	modified := filepath.Join(dir, "a.js")
+	// the polling compares modification times, which may be coarse
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(modified, []byte("const a = 2;\nconst b = 3;\n"), 0644))
+	nextChange(t, w, modified)
+
+	created := filepath.Join(dir, "lib", "b.js")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(created), 0755))
+	assert.NoError(t, os.WriteFile(created, []byte("const b = 1;\n"), 0644))
+	nextChange(t, w, created)
+
+	assert.NoError(t, os.Remove(modified))
+	nextChange(t, w, modified)
+}
+
+func TestWatcher(t *testing.T) {
+	testWatcher(t, func(root string) *watch.Watcher { return watch.New(root, 10*time.Millisecond) })
+}
+
+func TestPollingWatcher(t *testing.T) {
+	testWatcher(t, func(root string) *watch.Watcher {
+		w := watch.Poll(root, 10*time.Millisecond)
+		assert.Equal(t, watch.Polling, w.Mode)
+		return w
+	})
+}
+
+func TestWatcherSkipsStateDirectory(t *testing.T) {
+	dir := t.TempDir()
+	w := watch.Poll(dir, 10*time.Millisecond)
+	defer w.Close()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".gocli", "cache"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".gocli", "cache", "entry.json"), []byte("{}"), 0644))
+	source := filepath.Join(dir, "a.js")
+	assert.NoError(t, os.WriteFile(source, []byte("const a = 1;\n"), 0644))
+
+	paths := <-w.Changes()
+	assert.Equal(t, []string{source}, paths)
+}
+
+func countLines(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	return strings.Count(string(content), "\n"), err
+}
+
+func TestSessionRun(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.js")
+	second := filepath.Join(dir, "b.js")
+	assert.NoError(t, os.WriteFile(first, []byte("const a = 1;\n"), 0644))
+	assert.NoError(t, os.WriteFile(second, []byte("const b = 1;\n"), 0644))
+
+	analyzed := []string{}
+	session := &watch.Session[int]{
+		Title: "Count Lines",
+		Dir:   dir,
+		Analyze: func(path string) (int, error) {
+			analyzed = append(analyzed, filepath.Base(path))
+			return countLines(path)
+		},
+		Summarize: func(results map[string]int) []watch.Metric {
+			total := 0
+			for _, lines := range results {
+				total += lines
+			}
+			return []watch.Metric{{Name: "Lines", Value: float64(total)}}
+		},
+	}
+
+	changes := make(chan []string)
+	var out bytes.Buffer
+	done := make(chan error)
+	go func() { done <- session.Run(context.Background(), &out, dir, watch.Polling, changes) }()
+	// the session receives the changes once the directory is analyzed
+	changes <- nil
+
+	assert.NoError(t, os.WriteFile(first, []byte("const a = 1;\nconst b = 2;\nconst c = 3;\n"), 0644))
+	changes <- []string{first}
+	assert.NoError(t, os.Remove(second))
+	changes <- []string{second}
+	close(changes)
+	assert.NoError(t, <-done)
+
+	// only the changed file is analyzed again
+	assert.Equal(t, []string{"a.js", "b.js", "a.js"}, analyzed)
+
+	screens := strings.Split(out.String(), "\033[H\033[2J")
+	last := screens[len(screens)-1]
+	assert.Regexp(t, `Files\s+1\s+-1\n`, last)
+	assert.Regexp(t, `Lines\s+3\s+\+1\n`, last)
+	assert.Contains(t, last, "1 paths changed, 0 files analyzed again")
+	assert.Contains(t, screens[1], "Files   2")
+}
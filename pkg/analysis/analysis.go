@@ -0,0 +1,103 @@
+// Package analysis runs the analyzers of go-cli-tool from other Go programs.
+//
+// The analysis depends only on its Options: it neither reads the command
+// line flags nor the project configuration file, and several analyses can
+// run at the same time.
+//
+//	report, err := analysis.Analyze(ctx, analysis.Options{Path: "src"})
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-cli-tool/internal/analyzer"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/internal/results"
+	"os"
+	"path/filepath"
+)
+
+// Options select what an analysis reads.
+type Options struct {
+	// Path is the JavaScript or TypeScript file, or the directory, to
+	// analyze.
+	Path string
+	// Include restricts the analysis of a directory to the files matching
+	// one of these globs, relative to the directory. Empty includes every
+	// file.
+	Include []string
+	// Exclude skips the files and directories matching one of these globs,
+	// in addition to node_modules, .git, dist, build, coverage and vendor
+	// and to the entries of the .gitignore, .eslintignore and .gocliignore
+	// files of the directory. A glob without a slash matches an entry name
+	// at any depth.
+	Exclude []string
+	// Extensions limits the analyzed files to these extensions, such as
+	// ".js" or "ts". Empty accepts every JavaScript and TypeScript
+	// extension.
+	Extensions []string
+	// TabWidth is the number of spaces a tab counts for in the indentation
+	// results, 4 when zero.
+	TabWidth int
+	// Jobs is the number of files analyzed in parallel, one per CPU when
+	// zero.
+	Jobs int
+}
+
+// settings returns the configuration of the analyzers for the options.
+func (o Options) settings() config.Config {
+	settings := config.Default()
+	settings.Include = o.Include
+	settings.Exclude = append(settings.Exclude, o.Exclude...)
+	settings.Extensions = config.NormalizeExtensions(o.Extensions)
+	if o.TabWidth > 0 {
+		settings.TabWidth = o.TabWidth
+	}
+	settings.Jobs = o.Jobs
+	return settings
+}
+
+// Analyze runs all the analyzers over the file or directory of the options.
+//
+// The files of a directory that cannot be read are listed in
+// Report.Skipped, and the report is returned with a nil error. Analyze
+// fails when the path does not exist, when a file is not a JavaScript or
+// TypeScript file, and with the error of ctx once ctx is done.
+func Analyze(ctx context.Context, options Options) (Report, error) {
+	if options.Path == "" {
+		return Report{}, errors.New("no file or directory path provided")
+	}
+	path, err := filepath.Abs(options.Path)
+	if err != nil {
+		return Report{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Report{}, err
+	}
+
+	settings := options.settings()
+	directoryAnalyzer := &analyzer.DirectoryAnalyzerImpl{Config: &settings}
+
+	if !info.IsDir() {
+		if !settings.HasSourceExtension(path) {
+			return Report{}, fmt.Errorf("file %s is not a JavaScript or TypeScript file", path)
+		}
+		if err := ctx.Err(); err != nil {
+			return Report{}, err
+		}
+		file, err := directoryAnalyzer.AnalyzeFile(path)
+		if err != nil {
+			return Report{}, err
+		}
+		return results.New(filepath.Dir(path), map[string]analyzer.FileAnalysis{filepath.Base(path): file}, nil), nil
+	}
+
+	directory, err := directoryAnalyzer.AnalyzeDirectoryContext(ctx, path)
+	skipped, err := analyzer.Skipped(err)
+	if err != nil {
+		return Report{}, err
+	}
+	return results.New(path, directory.Files, skipped), nil
+}
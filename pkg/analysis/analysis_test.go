@@ -0,0 +1,157 @@
+package analysis_test
+
+import (
+	"context"
+	"go-cli-tool/internal/config"
+	"go-cli-tool/pkg/analysis"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestAnalyzeDirectory(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"index.js":     "// entry\nimport lodash from 'lodash';\nfunction main(x) {\n\tif (x) {\n\t\treturn 1;\n\t}\n\treturn 0;\n}\n",
+		"lib/index.js": "import fs from 'fs';\nclass Store {\n    read() {\n        return fs.readFileSync('a');\n    }\n}\n",
+	})
+
+	report, err := analysis.Analyze(context.Background(), analysis.Options{Path: dir})
+	assert.NoError(t, err)
+
+	assert.Equal(t, analysis.SchemaVersion, report.SchemaVersion)
+	assert.Equal(t, dir, report.Root)
+	// the two index.js are reported apart
+	assert.Len(t, report.Files, 2)
+	assert.Equal(t, "index.js", report.Files[0].Path)
+	assert.Equal(t, "lib/index.js", report.Files[1].Path)
+
+	main := report.Files[0]
+	assert.Equal(t, 8, main.Lines)
+	assert.Equal(t, 1, main.CommentLines)
+	assert.Equal(t, []string{"lodash"}, main.Dependencies)
+	assert.Equal(t, 8, main.Indentation.MaxLevel)
+	assert.True(t, main.Indentation.UsesTabs)
+	assert.Equal(t, []analysis.IndentLevel{{Level: 0, Count: 3}, {Level: 4, Count: 3}, {Level: 8, Count: 1}}, main.Indentation.Distribution)
+	assert.Equal(t, []analysis.Function{{Name: "main", Kind: "declaration", StartLine: 3, EndLine: 8, Params: 1, Complexity: 2, CognitiveComplexity: 1, NestingDepth: 1, Volume: 33}}, main.Functions)
+
+	store := report.Files[1]
+	assert.Equal(t, 1, store.Classes)
+	assert.Equal(t, []string{"fs"}, store.NativeModules)
+	assert.Equal(t, "Store.read", store.Functions[0].Name)
+
+	assert.Equal(t, 2, report.Totals.Files)
+	assert.Equal(t, main.Lines+store.Lines, report.Totals.Lines)
+	assert.Equal(t, 1, report.Totals.Classes)
+	assert.Equal(t, 2, report.Totals.MaxComplexity)
+	assert.Equal(t, []string{"lodash"}, report.Totals.Dependencies)
+	assert.Equal(t, []string{"fs"}, report.Totals.NativeModules)
+	assert.Empty(t, report.Skipped)
+}
+
+func TestAnalyzeFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"app.ts": "interface User {\n  name: string;\n}\nexport const greet = (user: User) => user.name;\n"})
+
+	report, err := analysis.Analyze(context.Background(), analysis.Options{Path: filepath.Join(dir, "app.ts")})
+	assert.NoError(t, err)
+
+	assert.Equal(t, dir, report.Root)
+	assert.Len(t, report.Files, 1)
+	assert.Equal(t, "app.ts", report.Files[0].Path)
+	assert.Equal(t, 1, report.Files[0].Interfaces)
+	assert.Equal(t, 1, report.Totals.Files)
+	assert.Equal(t, 1, report.Totals.Interfaces)
+}
+
+func TestAnalyzeFunctionsOnTheSameLine(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"chain.js": "[1].map(x => { if (x) { if (x) {} } return x }).filter(y => y);\n"})
+
+	report, err := analysis.Analyze(context.Background(), analysis.Options{Path: filepath.Join(dir, "chain.js")})
+	assert.NoError(t, err)
+
+	functions := report.Files[0].Functions
+	assert.Len(t, functions, 2)
+	assert.Equal(t, 3, functions[0].Complexity)
+	assert.Equal(t, 1, functions[1].Complexity)
+	assert.Equal(t, 2, functions[0].NestingDepth)
+	assert.Equal(t, 0, functions[1].NestingDepth)
+	assert.Greater(t, functions[0].Volume, functions[1].Volume)
+}
+
+func TestAnalyzeAppliesOptions(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"src/app.js":       "function app() {\n\treturn 1;\n}\n",
+		"src/app.test.js":  "test('app', () => {});\n",
+		"src/types.ts":     "type Id = string;\n",
+		"scripts/build.js": "console.log('build');\n",
+	})
+
+	report, err := analysis.Analyze(context.Background(), analysis.Options{
+		Path:       dir,
+		Include:    []string{"src/**"},
+		Exclude:    []string{"*.test.js"},
+		Extensions: []string{"js"},
+		TabWidth:   2,
+		Jobs:       1,
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, report.Files, 1)
+	assert.Equal(t, "src/app.js", report.Files[0].Path)
+	assert.Equal(t, 2, report.Files[0].Indentation.MaxLevel)
+}
+
+func TestAnalyzeDoesNotUseTheActiveConfiguration(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"app.js": "function app() {\n\treturn 1;\n}\n"})
+
+	active := config.Active
+	defer func() { config.Active = active }()
+	config.Active.Exclude = append(config.Active.Exclude, "app.js")
+	config.Active.TabWidth = 8
+
+	report, err := analysis.Analyze(context.Background(), analysis.Options{Path: dir})
+	assert.NoError(t, err)
+
+	assert.Len(t, report.Files, 1)
+	assert.Equal(t, 4, report.Files[0].Indentation.MaxLevel)
+}
+
+func TestAnalyzeReportsSkippedFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"app.js": "const a = 1;\n"})
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "removed.js"), filepath.Join(dir, "broken.js")))
+
+	report, err := analysis.Analyze(context.Background(), analysis.Options{Path: dir})
+	assert.NoError(t, err)
+
+	assert.Len(t, report.Files, 1)
+	assert.Equal(t, []analysis.SkippedFile{{Path: "broken.js", Reason: "no such file or directory"}}, report.Skipped)
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"notes.txt": "notes\n", "app.js": "const a = 1;\n"})
+
+	_, err := analysis.Analyze(context.Background(), analysis.Options{})
+	assert.EqualError(t, err, "no file or directory path provided")
+
+	_, err = analysis.Analyze(context.Background(), analysis.Options{Path: filepath.Join(dir, "missing")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = analysis.Analyze(context.Background(), analysis.Options{Path: filepath.Join(dir, "notes.txt")})
+	assert.EqualError(t, err, "file "+filepath.Join(dir, "notes.txt")+" is not a JavaScript or TypeScript file")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = analysis.Analyze(ctx, analysis.Options{Path: dir})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	_ "embed"
+	"go-cli-tool/internal/results"
+)
+
+// SchemaVersion is the version of the layout of Report, written in its
+// schema_version field. It changes whenever a field is removed, renamed or
+// changes meaning; adding a field does not change it.
+const SchemaVersion = results.SchemaVersion
+
+//go:embed report.schema.json
+var schema []byte
+
+// Schema returns the JSON Schema of the reports, encoded as JSON, for the
+// consumers of the reports to validate them.
+func Schema() []byte {
+	return append([]byte(nil), schema...)
+}
+
+// ReadReport decodes a report encoded as JSON. It fails on the reports of
+// another version of the schema.
+func ReadReport(content []byte) (Report, error) {
+	return results.Read(content)
+}
+
+// Report holds the results of an analysis: the results of every file,
+// sorted by path relative to Root, the totals of the files and the files
+// that could not be read.
+type Report = results.Report
+
+// File holds the results of a file.
+type File = results.File
+
+// Halstead holds the Halstead metrics of a file or a function.
+type Halstead = results.Halstead
+
+// Function holds the results of a function, method or arrow function.
+type Function = results.Function
+
+// Indentation holds the indentation statistics of a file, in spaces.
+type Indentation = results.Indentation
+
+// IndentLevel is the number of lines indented by Level spaces.
+type IndentLevel = results.IndentLevel
+
+// Totals sums the results of all the files.
+type Totals = results.Totals
+
+// SkippedFile is a file the analysis could not read.
+type SkippedFile = results.SkippedFile
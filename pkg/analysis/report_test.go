@@ -0,0 +1,93 @@
+package analysis_test
+
+import (
+	"encoding/json"
+	"go-cli-tool/pkg/analysis"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// schemaObject is the part of an object of the schema the tests compare to
+// the fields of the report.
+type schemaObject struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// jsonFields returns the names of the JSON fields of a struct, and the ones
+// always encoded.
+func jsonFields(value interface{}) (names []string, required []string) {
+	fields := reflect.TypeOf(value)
+	for i := 0; i < fields.NumField(); i++ {
+		name, options, _ := strings.Cut(fields.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+		if options != "omitempty" {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(required)
+	return names, required
+}
+
+func TestSchemaDescribesTheReport(t *testing.T) {
+	var schema struct {
+		schemaObject
+		Defs map[string]schemaObject `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(analysis.Schema(), &schema))
+
+	objects := map[string]interface{}{
+		"file":         analysis.File{},
+		"function":     analysis.Function{},
+		"halstead":     analysis.Halstead{},
+		"indentation":  analysis.Indentation{},
+		"indent_level": analysis.IndentLevel{},
+		"totals":       analysis.Totals{},
+		"skipped_file": analysis.SkippedFile{},
+	}
+
+	check := func(name string, object schemaObject, value interface{}) {
+		names, required := jsonFields(value)
+		properties := make([]string, 0, len(object.Properties))
+		for property := range object.Properties {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		sort.Strings(object.Required)
+
+		assert.Equal(t, names, properties, "properties of %s", name)
+		assert.Equal(t, required, object.Required, "required properties of %s", name)
+	}
+
+	check("report", schema.schemaObject, analysis.Report{})
+	for name, value := range objects {
+		check(name, schema.Defs[name], value)
+	}
+
+	var version struct {
+		Const string `json:"const"`
+	}
+	assert.NoError(t, json.Unmarshal(schema.Properties["schema_version"], &version))
+	assert.Equal(t, analysis.SchemaVersion, version.Const)
+}
+
+func TestReadReport(t *testing.T) {
+	report := analysis.Report{SchemaVersion: analysis.SchemaVersion, Root: "/src", Files: []analysis.File{{Path: "app.js", Lines: 3}}}
+	content, err := json.Marshal(report)
+	assert.NoError(t, err)
+
+	read, err := analysis.ReadReport(content)
+	assert.NoError(t, err)
+	assert.Equal(t, report, read)
+
+	_, err = analysis.ReadReport([]byte(`{"directory": "src", "summary": {}}`))
+	assert.EqualError(t, err, `unsupported report schema version "", expected "1"`)
+
+	_, err = analysis.ReadReport([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package templates
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+)
+
+//go:embed dashboard.html
+var dashboardTemplate string
+
+var dashboard = template.Must(template.New("dashboard").Parse(dashboardTemplate))
+
+// Dashboard is the content of the HTML dashboard of the analyze command. It
+// is rendered as a single page with its styles and scripts inlined, so it
+// can be opened offline.
+type Dashboard struct {
+	Title   string
+	Path    string
+	Summary []SummaryItem
+	Files   []DashboardFile
+	// IndentLevels is the number of lines at each indentation level, over
+	// all the files.
+	IndentLevels []Bar
+	// FunctionSizes is a histogram of the function lengths in lines.
+	FunctionSizes []Bar
+	Dependencies  []Dependency
+	// Skipped are the files of the directory that could not be analyzed.
+	Skipped []SkippedItem
+}
+
+// DashboardFile holds the metrics of a file and the details shown when
+// drilling down into it.
+type DashboardFile struct {
+	// ID anchors the details of the file in the page.
+	ID                     string
+	Name                   string
+	Lines                  int
+	Comments               int
+	CommentPercentage      float64
+	Classes                int
+	FunctionCount          int
+	PublicMethods          int
+	PrivateMethods         int
+	AverageComplexity      float64
+	MaxComplexity          int
+	MaxNestingDepth        int
+	MaxCognitiveComplexity int
+	Maintainability        float64
+	// Indentation is "spaces", "tabs", "mixed" or empty for a file without
+	// indented lines.
+	Indentation   string
+	Functions     []DashboardFunction
+	Dependencies  []string
+	NativeModules []string
+}
+
+// DashboardFunction holds the metrics of a function of a file.
+type DashboardFunction struct {
+	Name                string
+	StartLine           int
+	Lines               int
+	Params              int
+	Complexity          int
+	NestingDepth        int
+	CognitiveComplexity int
+	Volume              float64
+}
+
+// Bar is a bar of a chart.
+type Bar struct {
+	Label string
+	Count int
+	// Height is the height of the bar in percent of the tallest one.
+	Height float64
+}
+
+// Dependency is a module imported by the analyzed files.
+type Dependency struct {
+	Name   string
+	Native bool
+	Files  []string
+}
+
+// NewBars returns the bars of a chart with the given labels and counts,
+// scaled to the highest count.
+func NewBars(labels []string, counts []int) []Bar {
+	highest := 0
+	for _, count := range counts {
+		highest = max(highest, count)
+	}
+
+	bars := make([]Bar, len(labels))
+	for i, label := range labels {
+		bars[i] = Bar{Label: label, Count: counts[i]}
+		if highest > 0 {
+			bars[i].Height = float64(counts[i]) * 100 / float64(highest)
+		}
+	}
+	return bars
+}
+
+// RenderDashboard writes the HTML dashboard of data.
+func RenderDashboard(w io.Writer, data Dashboard) error {
+	return dashboard.Execute(w, data)
+}
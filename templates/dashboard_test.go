@@ -0,0 +1,50 @@
+package templates_test
+
+import (
+	"bytes"
+	"go-cli-tool/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBars(t *testing.T) {
+	bars := templates.NewBars([]string{"0", "1", "2"}, []int{4, 2, 0})
+
+	assert.Equal(t, []templates.Bar{
+		{Label: "0", Count: 4, Height: 100},
+		{Label: "1", Count: 2, Height: 50},
+		{Label: "2", Count: 0, Height: 0},
+	}, bars)
+}
+
+func TestRenderDashboard(t *testing.T) {
+	data := templates.Dashboard{
+		Title:   "Analysis",
+		Summary: []templates.SummaryItem{{Title: "Total lines", Value: "12"}},
+		Files: []templates.DashboardFile{{
+			ID:          "file-1",
+			Name:        "<app>.js",
+			Lines:       12,
+			Indentation: "mixed",
+			Functions:   []templates.DashboardFunction{{Name: "A.run", StartLine: 3, Lines: 4, Complexity: 2}},
+		}},
+		FunctionSizes: templates.NewBars([]string{"1-5"}, []int{1}),
+		Dependencies:  []templates.Dependency{{Name: "lodash", Files: []string{"<app>.js"}}},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, templates.RenderDashboard(&out, data))
+
+	html := out.String()
+	assert.Contains(t, html, "<title>Analysis Dashboard</title>")
+	assert.Contains(t, html, `<details id="file-1">`)
+	assert.Contains(t, html, "&lt;app&gt;.js")
+	assert.Contains(t, html, "<td>A.run</td>")
+	assert.Contains(t, html, `<td class="mixed">mixed</td>`)
+	assert.Contains(t, html, `style="height: 100.0%"`)
+	assert.Contains(t, html, "<td>lodash</td>")
+	// the page must open offline
+	assert.NotContains(t, html, "http://")
+	assert.NotContains(t, html, "https://")
+}
@@ -2,111 +2,38 @@ package templates
 
 import (
 	_ "embed"
-	"fmt"
-	"go-cli-tool/internal/analyzer"
-	"go-cli-tool/internal/utils"
 	"html/template"
-	"os"
-	"path/filepath"
-
-	"github.com/spf13/cobra"
+	"io"
 )
 
 //go:embed report.html
 var reportTemplate string
 
-type FileResult struct {
-	FileName   string
-	TotalLines int
-	Classes    int
-	Func       int
-	Comments   int
-}
+var report = template.Must(template.New("report").Parse(reportTemplate))
 
-type ReportData struct {
-	Files            []FileResult
-	TotalByDirectory string
-	CommandType      utils.CommandType
-	HasClasses       bool
-	HasFunctions     bool
+// SummaryItem is a total shown above the table of a report.
+type SummaryItem struct {
+	Title string
+	Value string
 }
 
-type GenericsType interface {
-	analyzer.FilesNameCountLineMap | analyzer.ClassesAndFunctionsMap | analyzer.CommentsMap | analyzer.PercentResultMap
+// SkippedItem is a file the analysis could not read, with the reason.
+type SkippedItem struct {
+	Path   string
+	Reason string
 }
 
-func SaveResultsToHTML[T GenericsType](
-	result T,
-	totalByDirectory string,
-	filePath string,
-	commandType utils.CommandType,
-	cmd *cobra.Command,
-	hasClasses bool,
-	hasFunctions bool) {
-
-	var files []FileResult
-	switch v := any(result).(type) {
-	case analyzer.FilesNameCountLineMap:
-		for fileName, res := range v {
-			files = append(files, FileResult{
-				FileName:   fileName,
-				TotalLines: res.TotalLines,
-			})
-		}
-	case analyzer.ClassesAndFunctionsMap:
-		for fileName, res := range v {
-			files = append(files, FileResult{
-				FileName:   fileName,
-				Classes:    res.Classes,
-				Func:       res.Functions,
-				TotalLines: res.Classes + res.Functions,
-			})
-		}
-	case analyzer.CommentsMap:
-		for fileName, res := range v {
-			files = append(files, FileResult{
-				FileName:   fileName,
-				TotalLines: res.CommentLines,
-			})
-		}
-	}
-
-	data := ReportData{
-		Files:            files,
-		TotalByDirectory: totalByDirectory,
-		CommandType:      commandType,
-		HasClasses:       hasClasses,
-		HasFunctions:     hasFunctions,
-	}
-
-	tmpl, err := template.New("report").Parse(reportTemplate)
-	if err != nil {
-		fmt.Printf("Error parsing template: %s\n", err)
-		return
-	}
-
-	// Ensure the directory exists
-	outputDir := filepath.Dir(filePath)
-	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
-		err = os.MkdirAll(outputDir, os.ModePerm)
-		if err != nil {
-			fmt.Printf("Error creating directory: %s\n", err)
-			return
-		}
-	}
-
-	filePath = filepath.Join(outputDir, "report.html")
-	file, err := os.Create(filePath)
-	if err != nil {
-		fmt.Printf("Error creating file: %s\n", err)
-		return
-	}
-	defer file.Close()
-
-	err = tmpl.Execute(file, data)
-	if err != nil {
-		fmt.Printf("Error executing template: %s\n", err)
-	}
+// ReportData is the content of an HTML report: a filterable table with a
+// row per file, whose first column is the file name, and its totals.
+type ReportData struct {
+	Title   string
+	Summary []SummaryItem
+	Columns []string
+	Rows    [][]string
+	Skipped []SkippedItem
+}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "\033[1;34mReport generated successfully at %s\033[0m\n", filePath)
+// RenderReport writes the HTML report of data.
+func RenderReport(w io.Writer, data ReportData) error {
+	return report.Execute(w, data)
 }
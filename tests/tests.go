@@ -6,4 +6,13 @@ func ResetGlobals() {
 	utils.FilePath = ""
 	utils.DirectoryPath = ""
 	utils.OutputFilePath = ""
+	utils.OutputFormat = ""
+	utils.ChangedSince = ""
+	utils.Staged = false
+	utils.Jobs = 0
+	utils.Watch = false
+	utils.Include = nil
+	utils.Exclude = nil
+	utils.Extensions = nil
+	utils.TabWidth = 0
 }
\ No newline at end of file